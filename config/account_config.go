@@ -0,0 +1,21 @@
+package config
+
+// 多账号之间的执行方式（与 platform.RunMode 取值一致）
+const (
+	ACCOUNT_RUN_MODE_SEQUENTIAL = "sequential" // 逐个账号执行
+	ACCOUNT_RUN_MODE_CONCURRENT = "concurrent" // 所有账号同时执行（各账号使用独立的浏览器上下文）
+)
+
+// AccountConfig 多账号投递配置（账号本身保存在数据库 account 表中）
+type AccountConfig struct {
+	RunMode        string `yaml:"runMode"`        // 账号之间的执行方式：sequential / concurrent
+	IncludeDefault bool   `yaml:"includeDefault"` // 是否同时投递默认账号（使用本配置文件中的 boss 配置与未区分账号的历史数据）
+}
+
+// DefaultAccountConfig 默认多账号配置（逐个执行，包含默认账号，与单账号部署的行为一致）
+func DefaultAccountConfig() AccountConfig {
+	return AccountConfig{
+		RunMode:        ACCOUNT_RUN_MODE_SEQUENTIAL,
+		IncludeDefault: true,
+	}
+}
@@ -12,31 +12,71 @@ import (
 
 // BossConfig Boss直聘配置数据结构
 type BossConfig struct {
-	SayHi          string            `yaml:"sayHi"`
-	Debugger       bool              `yaml:"debugger"`
-	Keywords       []string          `yaml:"keywords"`
-	CityCode       []string          `yaml:"cityCode"`
-	CustomCityCode map[string]string `yaml:"customCityCode"`
-	Industry       []string          `yaml:"industry"`
-	Experience     []string          `yaml:"experience"`
-	JobType        string            `yaml:"jobType"`
-	Salary         []string          `yaml:"salary"` // 改为 []string，因为Java中是List<String>
-	Degree         []string          `yaml:"degree"`
-	Scale          []string          `yaml:"scale"`
-	Stage          []string          `yaml:"stage"`
-	EnableAI       bool              `yaml:"enableAI"`
-	FilterDeadHR   bool              `yaml:"filterDeadHR"`
-	SendImgResume  bool              `yaml:"sendImgResume"`
-	ExpectedSalary []int             `yaml:"expectedSalary"`
-	WaitTime       string            `yaml:"waitTime"`
-	DeadStatus     []string          `yaml:"deadStatus"`
+	SayHi             string            `yaml:"sayHi"`
+	Debugger          bool              `yaml:"debugger"`
+	Keywords          []string          `yaml:"keywords"`
+	CityCode          []string          `yaml:"cityCode"`
+	CustomCityCode    map[string]string `yaml:"customCityCode"`
+	Industry          []string          `yaml:"industry"`
+	Experience        []string          `yaml:"experience"`
+	JobType           string            `yaml:"jobType"`
+	Salary            []string          `yaml:"salary"` // 改为 []string，因为Java中是List<String>
+	Degree            []string          `yaml:"degree"`
+	Scale             []string          `yaml:"scale"`
+	Stage             []string          `yaml:"stage"`
+	EnableAI          bool              `yaml:"enableAI"`
+	FilterDeadHR      bool              `yaml:"filterDeadHR"`
+	SendImgResume     bool              `yaml:"sendImgResume"`
+	ExpectedSalary    []int             `yaml:"expectedSalary"`
+	WaitTime          string            `yaml:"waitTime"`
+	DeadStatus        []string          `yaml:"deadStatus"`            // 视为不活跃的HR活跃状态（如 半年前活跃、本月活跃），需开启 filterDeadHR
+	MaxInactiveDays   int               `yaml:"maxInactiveDays"`       // HR最长不活跃天数（0=不限），需开启 filterDeadHR
+	EnableAIScore     bool              `yaml:"enableAIScore"`         // 投递前使用AI评估岗位匹配度
+	AIScoreThreshold  int               `yaml:"aiScoreThreshold"`      // 匹配度低于该分数（0-100）的岗位将被过滤
+	EnableAIExtract   bool              `yaml:"enableAIExtract"`       // 使用AI从职位描述中提取技术栈、年限、加班、外包等结构化属性
+	FilterOvertime    bool              `yaml:"filterOvertime"`        // 过滤有加班信号（大小周、996）的岗位，需开启 enableAIExtract
+	FilterOutsourcing bool              `yaml:"filterOutsourcing"`     // 过滤外包/驻场岗位，需开启 enableAIExtract
+	MaxRequiredYears  int               `yaml:"maxRequiredYears"`      // 过滤年限要求高于该值的岗位（0=不限），需开启 enableAIExtract
+	GreetingBanWords  []string          `yaml:"greetingBannedPhrases"` // AI打招呼语额外禁用语，命中时重新生成或回退到 sayHi
+	FilterRules       []FilterRule      `yaml:"filterRules"`           // 自定义过滤规则，先于数据库中的规则执行
+	FilterHeadhunter  bool              `yaml:"filterHeadhunter"`      // 投递时过滤猎头招聘的岗位
+	FilterAgency      bool              `yaml:"filterAgency"`          // 投递时过滤外包/派遣机构招聘的岗位
+	HunterCompanies   []string          `yaml:"headhunterCompanies"`   // 额外的猎头公司名单（公司名称包含即命中）
+	AgencyCompanies   []string          `yaml:"agencyCompanies"`       // 额外的外包/派遣公司名单（公司名称包含即命中）
+	CommutePoints     []CommutePoint    `yaml:"commutePoints"`         // 通勤点（家、公司等），岗位距离按最近的通勤点计算
+	MaxCommuteKm      float64           `yaml:"maxCommuteKm"`          // 过滤距最近通勤点超过该距离（千米）的岗位（0=不限）
+	Districts         []string          `yaml:"businessDistricts"`     // 只投递这些区县/商圈的岗位（为空不限，如 南山区、科技园）
+	JdInclude         []string          `yaml:"jdInclude"`             // 职位描述与标签须全部命中的关键词条目（| 表示或，& 表示且，re: 前缀表示正则）
+	JdExclude         []string          `yaml:"jdExclude"`             // 职位描述与标签命中任一条目即过滤（格式同 jdInclude）
+	DetailConcurrency int               `yaml:"detailConcurrency"`     // 筛选阶段并发获取岗位详情的页面数（≤1 时逐个点击卡片获取），打招呼始终逐个进行
+	DetailTimeout     int               `yaml:"detailTimeout"`         // 单个岗位详情页面的超时时间（秒，0 使用默认值 20）
+	CaptureDir        string            `yaml:"captureDir"`            // 记录岗位列表、详情等接口原始响应的目录（用作测试样本，为空不记录）
 }
 
-var GlobalConfig Config
+// CommutePoint 通勤点坐标
+type CommutePoint struct {
+	Name      string  `yaml:"name"`      // 名称，如 家、公司
+	Latitude  float64 `yaml:"latitude"`  // 纬度
+	Longitude float64 `yaml:"longitude"` // 经度
+}
+
+// FilterRule 配置文件中的自定义过滤规则
+type FilterRule struct {
+	Name       string `yaml:"name"`       // 规则名称（skip 动作记录为过滤原因）
+	Expression string `yaml:"expression"` // 规则表达式，如 salaryMax < 20 || jd contains "外包"
+	Action     string `yaml:"action"`     // 动作：skip / priority / tag
+	Priority   int    `yaml:"priority"`   // priority 动作的优先级加成
+	Tag        string `yaml:"tag"`        // tag 动作的标签（为空时使用规则名称）
+}
+
+// GlobalConfig 全局配置（浏览器与多账号配置预置默认值，配置文件中未出现的字段保持默认）
+var GlobalConfig = Config{Browser: DefaultBrowserConfig(), Accounts: DefaultAccountConfig()}
 
 // Config 全局配置结构
 type Config struct {
-	Boss BossConfig `yaml:"boss"`
+	Boss     BossConfig    `yaml:"boss"`
+	Browser  BrowserConfig `yaml:"browser"`
+	Accounts AccountConfig `yaml:"accounts"`
 }
 
 // LoadConfig 加载配置文件
@@ -0,0 +1,49 @@
+package config
+
+// 浏览器类型
+const (
+	BROWSER_TYPE_CHROMIUM = "chromium"
+	BROWSER_TYPE_FIREFOX  = "firefox"
+	BROWSER_TYPE_WEBKIT   = "webkit"
+)
+
+// DEFAULT_BROWSER_USER_AGENT 默认 User-Agent（桌面版 Chrome）
+const DEFAULT_BROWSER_USER_AGENT = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/135.0.0.0 Safari/537.36"
+
+// BrowserConfig 浏览器启动配置
+type BrowserConfig struct {
+	Type           string   `yaml:"type"`           // 浏览器类型：chromium / firefox / webkit
+	Headless       bool     `yaml:"headless"`       // 无头模式（Linux 服务器、容器中运行时开启）
+	Channel        string   `yaml:"channel"`        // 浏览器渠道（如 chrome、msedge，仅 chromium），为空使用 Playwright 自带浏览器
+	ExecutablePath string   `yaml:"executablePath"` // 浏览器可执行文件路径，为空使用 Playwright 自带浏览器
+	DebugPort      int      `yaml:"debugPort"`      // 远程调试端口（仅 chromium，0=不开启）
+	UserAgent      string   `yaml:"userAgent"`      // User-Agent，为空使用浏览器默认值
+	Viewport       Viewport `yaml:"viewport"`       // 视口大小，宽高为 0 时不固定视口（跟随窗口大小）
+	Locale         string   `yaml:"locale"`         // 语言区域，如 zh-CN
+	TimezoneId     string   `yaml:"timezoneId"`     // 时区，如 Asia/Shanghai
+	Args           []string `yaml:"args"`           // 额外的浏览器启动参数
+	SlowMo         float64  `yaml:"slowMo"`         // 每个操作之间的延迟（毫秒）
+	UserDataDir    string   `yaml:"userDataDir"`    // 持久化浏览器配置目录的根目录（保留 localStorage、IndexedDB 等），为空不持久化
+	Profile        string   `yaml:"profile"`        // 账号配置目录名（userDataDir 下的子目录，默认 default）
+	StorageState   string   `yaml:"storageState"`   // Playwright 存储状态文件：启动时存在则导入，登录成功与关闭时导出，为空不使用
+}
+
+// Viewport 浏览器视口大小
+type Viewport struct {
+	Width  int `yaml:"width"`
+	Height int `yaml:"height"`
+}
+
+// DefaultBrowserConfig 默认浏览器配置（有界面的 Chromium，与配置文件缺少 browser 节时的行为一致）
+func DefaultBrowserConfig() BrowserConfig {
+	return BrowserConfig{
+		Type:       BROWSER_TYPE_CHROMIUM,
+		Headless:   false,
+		DebugPort:  7866,
+		UserAgent:  DEFAULT_BROWSER_USER_AGENT,
+		Locale:     "zh-CN",
+		TimezoneId: "Asia/Shanghai",
+		Args:       []string{"--start-maximized"},
+		SlowMo:     50,
+	}
+}
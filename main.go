@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"get_jobs_go/config"
+	"get_jobs_go/mockllm"
 	"get_jobs_go/model"
 	"get_jobs_go/repository"
 	"get_jobs_go/service"
 	"get_jobs_go/worker/boss"
+	"get_jobs_go/worker/platform"
 	"get_jobs_go/worker/playwright_manager"
 	"os"
 	"os/signal"
@@ -20,12 +23,35 @@ import (
 	"gorm.io/gorm"
 )
 
+// 命令行参数
+var (
+	mockLLM       = flag.Bool("mock-llm", false, "使用本地模拟大模型服务代替真实AI接口（离线演示）")
+	mockLLMAddr   = flag.String("mock-llm-addr", "127.0.0.1:0", "本地模拟大模型服务监听地址")
+	testRule      = flag.String("test-rule", "", "对已采集的职位试运行过滤规则表达式，打印命中与未命中的职位后退出")
+	testRuleLimit = flag.Int("test-rule-limit", service.DEFAULT_RULE_TEST_LIMIT, "试运行规则时扫描的最近职位数量")
+)
+
 type Application struct {
 	db                *gorm.DB
 	configService     *service.ConfigService
 	cookieService     service.CookieService
+	accountService    *service.AccountService
+	statsService      *service.BossService
 	playwrightManager *playwright_manager.PlaywrightManager
 	bossJobService    *boss.BossJobService
+	orchestrator      *platform.Orchestrator
+	mockLLMServer     *mockllm.Server
+	mockLLMURL        string
+}
+
+// accountServices 单个账号的数据服务（仓储基于该账号的数据库会话创建）
+type accountServices struct {
+	bossService       *service.BossService
+	configService     *service.ConfigService
+	aiService         *service.AiService
+	jobService        *service.JobService
+	filterRuleService *service.FilterRuleService
+	cookieService     *service.CookieService
 }
 
 // NewApplication 创建新的应用程序实例
@@ -60,22 +86,16 @@ func (app *Application) InitDatabase() error {
 
 	app.db = db
 	log.Println("✓ MySQL 数据库连接成功")
+
+	// 注册账号隔离回调（repository.WithAccount 创建的会话只读写该账号的数据）
+	if err := repository.RegisterAccountScope(db); err != nil {
+		return fmt.Errorf("注册账号隔离回调失败: %v", err)
+	}
 	
 
-	// 自动迁移数据库表
-	if err := db.AutoMigrate(
-		// 这里应该添加需要迁移的模型
-		&model.AiEntity{},
-		&model.BlacklistEntity{},
-		&model.BossConfigEntity{},
-		&model.BossIndustryEntity{},
-		&model.BossJobDataEntity{},
-		&model.BossOptionEntity{},
-		&model.ConfigEntity{},
-		&model.CookieEntity{},
-		&model.Job{},
-	); err != nil {
-		return fmt.Errorf("数据库迁移失败: %v", err)
+	// 自动迁移数据库表（旧版 boss_data 表中的职位一次性迁移到统一职位表）
+	if err := repository.Migrate(db); err != nil {
+		return err
 	}
 
 	log.Println("✓ 数据库表迁移完成")
@@ -93,17 +113,74 @@ func (app *Application) InitServices() error {
 		return fmt.Errorf("数据库初始化失败: %v", err)
 	}
 
+	// 启动本地模拟大模型服务（所有账号共用）
+	if *mockLLM {
+		app.mockLLMServer = mockllm.NewServer()
+		mockURL, err := app.mockLLMServer.Start(*mockLLMAddr)
+		if err != nil {
+			return err
+		}
+		app.mockLLMURL = mockURL
+		log.Printf("✓ 已启用本地模拟大模型服务: %s", mockURL)
+	}
+
+	// 初始化默认账号的服务（默认账号同样只读写自己的数据）
+	services := app.newAccountServices(repository.WithAccount(app.db, model.DEFAULT_ACCOUNT_ID))
+	app.configService = services.configService
+	app.cookieService = *services.cookieService
+
+	// 跨账号统计使用不区分账号的会话，通过 BossJobQuery.AccountId 筛选账号
+	app.statsService = app.newAccountServices(app.db).bossService
+
+	if purged, err := services.aiService.PurgeExpiredCache(); err != nil {
+		log.Printf("清理过期AI缓存失败: %v", err)
+	} else if purged > 0 {
+		log.Printf("已清理过期AI缓存 %d 条", purged)
+	}
+
+	config.LoadConfig("")
+
+	// 初始化Playwright管理器
+	playwrightManager := playwright_manager.NewPlaywrightManager(
+		*services.cookieService,
+		config.GlobalConfig.Browser,
+	)
+	app.playwrightManager = playwrightManager
+	// 初始化Playwright管理器
+	if err := app.playwrightManager.Init(); err != nil {
+		return fmt.Errorf("Playwright管理器初始化失败: %v", err)
+	}
+	
+	// 初始化Boss任务服务
+	app.bossJobService = app.newBossJobService(services, playwrightManager)
+
+	// 初始化账号服务与多账号编排器（后续平台在 newAccountDelivery 中注册）
+	app.accountService = service.NewAccountService(repository.NewAccountRepository(app.db))
+	if err := app.initOrchestrator(); err != nil {
+		return err
+	}
+	
+	log.Println("✓ 所有服务初始化完成")
+	return nil
+}
+
+// newAccountServices 基于指定数据库会话（repository.WithAccount 创建的账号会话，或不区分账号的全局会话）初始化数据服务
+func (app *Application) newAccountServices(db *gorm.DB) *accountServices {
 	// 初始化仓库
-	configRepo := repository.NewConfigRepository(app.db)
-	cookieRepo := repository.NewCookieRepository(app.db)
+	configRepo := repository.NewConfigRepository(db)
+	cookieRepo := repository.NewCookieRepository(db)
 
 	// 初始化Boss相关的仓库
-	bossOptionRepo := repository.NewBossOptionRepository(app.db)
-	bossIndustryRepo := repository.NewBossIndustryRepository(app.db)
-	bossConfigRepo := repository.NewBossConfigRepository(app.db)
-	blacklistRepo := repository.NewBlacklistRepository(app.db)
-	jobDataRepo := repository.NewBossJobDataRepository(app.db)
-	aiRepo := repository.NewAiRepository(app.db)
+	bossOptionRepo := repository.NewBossOptionRepository(db)
+	bossIndustryRepo := repository.NewBossIndustryRepository(db)
+	bossConfigRepo := repository.NewBossConfigRepository(db)
+	blacklistRepo := repository.NewBlacklistRepository(db)
+	whitelistRepo := repository.NewWhitelistRepository(db)
+	aiRepo := repository.NewAiRepository(db)
+	aiCacheRepo := repository.NewAiCacheRepository(db)
+	aiUsageRepo := repository.NewAiUsageRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	filterRuleRepo := repository.NewFilterRuleRepository(db)
 
 	// 初始化Boss服务
 	bossService := service.NewBossService(
@@ -111,44 +188,116 @@ func (app *Application) InitServices() error {
 		bossIndustryRepo,
 		bossConfigRepo,
 		blacklistRepo,
-		jobDataRepo,
-		app.db,
+		whitelistRepo,
+		jobRepo,
+		aiUsageRepo,
+		db,
 	)
 
 	// 初始化配置服务
 	configService := service.NewConfigService(configRepo, bossService)
-	app.configService = configService
 
 	// 初始化AI服务
-	aiService := service.NewAiService(aiRepo, *configService)
-
-	// 初始化Cookie服务
-	cookieService := service.NewCookieService(cookieRepo)
-	app.cookieService = *cookieService
-
-	config.LoadConfig("")
+	aiService := service.NewAiService(aiRepo, aiCacheRepo, aiUsageRepo, jobRepo, *configService)
+	if app.mockLLMURL != "" {
+		aiService.SetConfigOverrides(map[string]string{
+			"PROVIDER": service.AI_PROVIDER_OPENAI_CHAT,
+			"BASE_URL": app.mockLLMURL,
+			"API_KEY":  "mock",
+			"MODEL":    mockllm.DEFAULT_MODEL,
+		})
+	}
 
-	// 初始化Playwright管理器
-	playwrightManager := playwright_manager.NewPlaywrightManager(
-		*cookieService,
-	)
-	app.playwrightManager = playwrightManager
-	// 初始化Playwright管理器
-	if err := app.playwrightManager.Init(); err != nil {
-		return fmt.Errorf("Playwright管理器初始化失败: %v", err)
+	return &accountServices{
+		bossService:       bossService,
+		configService:     configService,
+		aiService:         aiService,
+		jobService:        service.NewJobService(jobRepo),
+		filterRuleService: service.NewFilterRuleService(filterRuleRepo, jobRepo, bossService),
+		cookieService:     service.NewCookieService(cookieRepo),
 	}
-	
-	// 初始化Boss任务服务
-	bossJobService := boss.NewBossJobService(
+}
+
+// newBossJobService 使用账号的数据服务与浏览器上下文创建Boss任务服务
+func (app *Application) newBossJobService(services *accountServices, playwrightManager *playwright_manager.PlaywrightManager) *boss.BossJobService {
+	return boss.NewBossJobService(
 		playwrightManager,
-		configService,
+		services.configService,
 		func() *boss.Boss {
-			return boss.NewBoss(bossService, aiService)
+			return boss.NewBoss(services.bossService, services.aiService, services.jobService, services.filterRuleService)
 		},
 	)
-	app.bossJobService = bossJobService
-	
-	log.Println("✓ 所有服务初始化完成")
+}
+
+// newAccountDelivery 创建账号投递任务（后续平台在此注册）
+func (app *Application) newAccountDelivery(accountId int64, accountName string, bossJobService *boss.BossJobService) *platform.AccountDelivery {
+	bossJobService.SetAccount(accountId, accountName)
+	return platform.NewAccountDelivery(accountId, accountName, platform.RunModeSequential, bossJobService)
+}
+
+// initOrchestrator 初始化多账号编排器：默认账号与每个启用的账号各注册一个账号投递任务，
+// 其他账号使用独立的数据库会话、Cookie 与浏览器上下文
+func (app *Application) initOrchestrator() error {
+	app.orchestrator = platform.NewOrchestrator()
+	if config.GlobalConfig.Accounts.IncludeDefault {
+		app.orchestrator.Register(app.newAccountDelivery(model.DEFAULT_ACCOUNT_ID, model.DEFAULT_ACCOUNT_NAME, app.bossJobService))
+	}
+
+	accounts, err := app.accountService.GetEnabledAccounts()
+	if err != nil {
+		return fmt.Errorf("加载账号失败: %v", err)
+	}
+	for _, account := range accounts {
+		services := app.newAccountServices(repository.WithAccount(app.db, account.ID))
+		playwrightManager, err := app.playwrightManager.ForAccount(account, *services.cookieService)
+		if err != nil {
+			log.Printf("⚠️ %v，跳过该账号", err)
+			continue
+		}
+		app.orchestrator.Register(app.newAccountDelivery(account.ID, account.Name, app.newBossJobService(services, playwrightManager)))
+		log.Printf("✓ 账号 %s 已就绪", account.Name)
+	}
+	return nil
+}
+
+// RunRuleTest 对默认账号已采集的职位试运行过滤规则表达式，打印命中与未命中的职位
+func (app *Application) RunRuleTest(expression string, limit int) error {
+	if err := app.InitDatabase(); err != nil {
+		return fmt.Errorf("数据库初始化失败: %v", err)
+	}
+	defer func() {
+		if sqlDB, err := app.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}()
+
+	services := app.newAccountServices(repository.WithAccount(app.db, model.DEFAULT_ACCOUNT_ID))
+	result, err := services.filterRuleService.TestRule(expression, limit)
+	if err != nil {
+		return fmt.Errorf("规则表达式无效: %v", err)
+	}
+
+	printJob := func(job *model.Job) {
+		fmt.Printf("  [%d] %s - %s（%s，%s）\n", job.ID, job.CompanyName, job.JobName, job.City, job.Salary)
+	}
+	fmt.Printf("规则: %s\n", result.Expression)
+	fmt.Printf("引用字段: %v\n", result.Fields)
+	fmt.Printf("扫描职位 %d 个，命中 %d 个，未命中 %d 个，求值失败 %d 个\n",
+		result.Scanned, result.Matched, len(result.Unmatched), len(result.Errors))
+	fmt.Println("命中:")
+	for _, job := range result.Jobs {
+		printJob(job)
+	}
+	fmt.Println("未命中:")
+	for _, job := range result.Unmatched {
+		printJob(job)
+	}
+	if len(result.Errors) > 0 {
+		fmt.Println("求值失败:")
+		for _, message := range result.Errors {
+			fmt.Printf("  %s\n", message)
+		}
+	}
 	return nil
 }
 
@@ -158,22 +307,27 @@ func (app *Application) Start() error {
 	log.Println("   启动求职信息采集系统")
 	log.Println("========================================")
 
-	// 启动Boss直聘任务服务
-	if app.bossJobService != nil {
-		log.Println("启动Boss直聘数据采集任务...")
+	// 启动多平台投递任务
+	if app.orchestrator != nil {
+		log.Println("启动多账号数据采集任务...")
 		// 使用默认的进度回调函数
 		progressCallback := func(message boss.JobProgressMessage) {
-			log.Printf("[%s][%s] %s", message.Platform, message.Type, message.Message)
+			if message.Account != "" {
+				log.Printf("[%s][%s][%s] %s", message.Account, message.Platform, message.Type, message.Message)
+			} else {
+				log.Printf("[%s][%s] %s", message.Platform, message.Type, message.Message)
+			}
 			if message.Current != nil && message.Total != nil {
 				log.Printf("进度: %d/%d", *message.Current, *message.Total)
 			}
 		}
 
-		if err := app.bossJobService.ExecuteDelivery(progressCallback); err != nil {
-			log.Printf("Boss直聘任务执行失败: %v", err)
+		runMode := platform.RunMode(config.GlobalConfig.Accounts.RunMode)
+		if err := app.orchestrator.ExecuteDelivery(runMode, nil, progressCallback); err != nil {
+			log.Printf("多平台任务执行失败: %v", err)
 		}
 	} else {
-		log.Println("⚠️ Boss直聘任务服务未初始化")
+		log.Println("⚠️ 多平台任务编排器未初始化")
 	}
 
 	log.Println("✓ 应用程序已启动")
@@ -186,10 +340,10 @@ func (app *Application) Stop() error {
 	log.Println("   停止应用程序")
 	log.Println("========================================")
 
-	// 停止Boss直聘任务服务
-	if app.bossJobService != nil {
-		log.Println("停止Boss直聘数据采集任务...")
-		app.bossJobService.StopDelivery()
+	// 停止所有平台的任务服务
+	if app.orchestrator != nil {
+		log.Println("停止多平台数据采集任务...")
+		app.orchestrator.StopDelivery()
 	}
 
 	// 关闭Playwright管理器
@@ -198,6 +352,11 @@ func (app *Application) Stop() error {
 		app.playwrightManager.Close()
 	}
 
+	// 关闭本地模拟大模型服务
+	if app.mockLLMServer != nil {
+		app.mockLLMServer.Close()
+	}
+
 	// 关闭数据库连接
 	if app.db != nil {
 		log.Println("关闭数据库连接...")
@@ -241,6 +400,8 @@ func (app *Application) waitForShutdown() {
 }
 
 func main() {
+	flag.Parse()
+
 	// 设置日志格式
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	log.Println("🚀 启动求职信息采集系统...")
@@ -248,6 +409,14 @@ func main() {
 	// 创建应用程序实例
 	app := NewApplication()
 
+	// 试运行过滤规则后直接退出，不启动浏览器与采集任务
+	if *testRule != "" {
+		if err := app.RunRuleTest(*testRule, *testRuleLimit); err != nil {
+			log.Fatalf("❌ 规则试运行失败: %v", err)
+		}
+		return
+	}
+
 	// 初始化服务
 	if err := app.InitServices(); err != nil {
 		log.Fatalf("❌ 服务初始化失败: %v", err)
@@ -0,0 +1,307 @@
+// Package mockllm 本地模拟的 OpenAI 兼容大模型服务，实现 /v1/chat/completions 与 /v1/responses，
+// 支持脚本化回复、错误注入（reasoning 参数错误、429、500）与延迟，用于测试与离线演示。
+package mockllm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// 接口类型
+const (
+	ENDPOINT_CHAT      = "chat"      // /v1/chat/completions
+	ENDPOINT_RESPONSES = "responses" // /v1/responses
+)
+
+// DEFAULT_MODEL 模拟服务返回的默认模型名称
+const DEFAULT_MODEL = "mock-llm"
+
+// Reply 一次脚本化回复
+type Reply struct {
+	Content string            // 回复内容（Status 为0或200时有效）
+	Status  int               // HTTP状态码，非200时返回 Body 作为错误响应
+	Body    string            // 错误响应体
+	Headers map[string]string // 额外响应头（如 Retry-After）
+	Latency time.Duration     // 响应前等待的时间
+}
+
+// Text 返回指定内容的成功回复
+func Text(content string) Reply {
+	return Reply{Content: content}
+}
+
+// ReasoningParamError 推理模型不支持的参数错误（触发 Chat Completions 切换到 Responses API）
+func ReasoningParamError() Reply {
+	return Reply{
+		Status: http.StatusBadRequest,
+		Body:   `{"error":{"message":"Unsupported value: 'reasoning.summary' is not supported with this model.","type":"invalid_request_error","param":"reasoning.summary","code":"unsupported_value"}}`,
+	}
+}
+
+// RateLimited 限流错误，retryAfter 大于0时返回 Retry-After 响应头
+func RateLimited(retryAfter time.Duration) Reply {
+	reply := Reply{
+		Status: http.StatusTooManyRequests,
+		Body:   `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`,
+	}
+	if retryAfter > 0 {
+		reply.Headers = map[string]string{"Retry-After": strconv.FormatFloat(retryAfter.Seconds(), 'f', -1, 64)}
+	}
+	return reply
+}
+
+// ServerError 服务端错误
+func ServerError() Reply {
+	return Reply{
+		Status: http.StatusInternalServerError,
+		Body:   `{"error":{"message":"The server had an error while processing your request.","type":"server_error"}}`,
+	}
+}
+
+// Request 模拟服务收到的请求
+type Request struct {
+	Endpoint string
+	Model    string
+	Prompt   string
+	Header   http.Header
+}
+
+// Responder 脚本队列为空时根据提示词生成回复
+type Responder func(endpoint, prompt string) string
+
+// Server 模拟大模型服务
+type Server struct {
+	mu        sync.Mutex
+	scripts   map[string][]Reply
+	responder Responder
+	latency   time.Duration
+	requests  []Request
+
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewServer 创建模拟服务（未启动），可直接作为 http.Handler 交给 httptest.NewServer 使用
+func NewServer() *Server {
+	return &Server{
+		scripts:   make(map[string][]Reply),
+		responder: DefaultResponder,
+	}
+}
+
+// Start 在指定地址启动模拟服务（如 "127.0.0.1:0"），返回服务地址
+func (s *Server) Start(addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("启动模拟大模型服务失败: %v", err)
+	}
+	s.listener = listener
+	s.server = &http.Server{Handler: s}
+	go s.server.Serve(listener)
+	return s.URL(), nil
+}
+
+// URL 服务地址（未启动时为空）
+func (s *Server) URL() string {
+	if s.listener == nil {
+		return ""
+	}
+	return "http://" + s.listener.Addr().String()
+}
+
+// Close 关闭模拟服务
+func (s *Server) Close() error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Close()
+}
+
+// Enqueue 为指定接口追加脚本化回复，按顺序逐个消费；endpoint 为空表示两个接口共用
+func (s *Server) Enqueue(endpoint string, replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[endpoint] = append(s.scripts[endpoint], replies...)
+}
+
+// SetResponder 设置脚本队列为空时的回复生成函数
+func (s *Server) SetResponder(responder Responder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responder = responder
+}
+
+// SetLatency 设置每次响应的基础延迟
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// Requests 获取已收到的请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// Reset 清空脚本与请求记录
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts = make(map[string][]Reply)
+	s.requests = nil
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var endpoint string
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/chat/completions"):
+		endpoint = ENDPOINT_CHAT
+	case strings.HasSuffix(r.URL.Path, "/responses"):
+		endpoint = ENDPOINT_RESPONSES
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": map[string]string{"message": err.Error()}})
+		return
+	}
+	modelName, prompt := parseRequest(endpoint, body)
+	if modelName == "" {
+		modelName = DEFAULT_MODEL
+	}
+
+	reply, latency := s.nextReply(endpoint, prompt, Request{Endpoint: endpoint, Model: modelName, Prompt: prompt, Header: r.Header.Clone()})
+	if wait := latency + reply.Latency; wait > 0 {
+		time.Sleep(wait)
+	}
+
+	for key, value := range reply.Headers {
+		w.Header().Set(key, value)
+	}
+	if reply.Status != 0 && reply.Status != http.StatusOK {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(reply.Status)
+		w.Write([]byte(reply.Body))
+		return
+	}
+
+	promptTokens := utf8.RuneCountInString(prompt)
+	completionTokens := utf8.RuneCountInString(reply.Content)
+	id := fmt.Sprintf("mock-%d", time.Now().UnixNano())
+	if endpoint == ENDPOINT_CHAT {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":      id,
+			"object":  "chat.completion",
+			"created": time.Now().Unix(),
+			"model":   modelName,
+			"choices": []map[string]interface{}{{
+				"index":         0,
+				"message":       map[string]string{"role": "assistant", "content": reply.Content},
+				"finish_reason": "stop",
+			}},
+			"usage": map[string]int{
+				"prompt_tokens":     promptTokens,
+				"completion_tokens": completionTokens,
+				"total_tokens":      promptTokens + completionTokens,
+			},
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":          id,
+		"object":      "response",
+		"created_at":  time.Now().Unix(),
+		"model":       modelName,
+		"output_text": reply.Content,
+		"output": []map[string]interface{}{{
+			"type":    "message",
+			"role":    "assistant",
+			"content": []map[string]string{{"type": "output_text", "text": reply.Content}},
+		}},
+		"usage": map[string]int{
+			"input_tokens":  promptTokens,
+			"output_tokens": completionTokens,
+			"total_tokens":  promptTokens + completionTokens,
+		},
+	})
+}
+
+// nextReply 记录请求并取出下一条回复：优先使用对应接口的脚本，其次是共用脚本，最后使用 Responder
+func (s *Server) nextReply(endpoint, prompt string, request Request) (Reply, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, request)
+
+	for _, key := range []string{endpoint, ""} {
+		if queue := s.scripts[key]; len(queue) > 0 {
+			s.scripts[key] = queue[1:]
+			return queue[0], s.latency
+		}
+	}
+	return Text(s.responder(endpoint, prompt)), s.latency
+}
+
+// parseRequest 解析请求中的模型与提示词
+func parseRequest(endpoint string, body []byte) (string, string) {
+	var request struct {
+		Model    string          `json:"model"`
+		Input    json.RawMessage `json:"input"`
+		Messages []struct {
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return "", ""
+	}
+
+	if endpoint == ENDPOINT_CHAT {
+		parts := make([]string, 0, len(request.Messages))
+		for _, message := range request.Messages {
+			parts = append(parts, message.Content)
+		}
+		return request.Model, strings.Join(parts, "\n")
+	}
+
+	var input string
+	if err := json.Unmarshal(request.Input, &input); err != nil {
+		input = string(request.Input)
+	}
+	return request.Model, input
+}
+
+// DefaultResponder 默认回复：根据提示词要求的JSON格式返回评分或属性提取结果，否则返回打招呼语
+func DefaultResponder(endpoint, prompt string) string {
+	switch {
+	case strings.Contains(prompt, `"score"`):
+		return `{"score": 80, "matchedSkills": ["Golang", "MySQL"], "missingSkills": ["Kubernetes"], "reason": "模拟评分：技能基本匹配"}`
+	case strings.Contains(prompt, `"techStack"`):
+		return `{"techStack": ["Golang", "MySQL", "Redis"], "requiredYears": 3, "workMode": "onsite", "overtimeSignals": [], "outsourcing": false, "outsourcingHints": [], "education": "本科", "teamSize": ""}`
+	default:
+		return "您好，我对贵司该岗位很感兴趣，相关经验与岗位要求较为匹配，期待与您进一步沟通。"
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
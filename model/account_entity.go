@@ -0,0 +1,35 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// DEFAULT_ACCOUNT_ID 默认账号ID：单账号部署与区分账号之前的历史数据都归属该账号
+const DEFAULT_ACCOUNT_ID int64 = 0
+
+// DEFAULT_ACCOUNT_NAME 默认账号的名称
+const DEFAULT_ACCOUNT_NAME = "default"
+
+// AccountEntity 求职者账号实体类：每个账号拥有独立的浏览器上下文、Cookie、Boss配置、AI简历、黑白名单与投递记录
+type AccountEntity struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Name      string    `gorm:"column:name;size:64;uniqueIndex" json:"name"` // 账号名称（唯一，用于区分进度消息与统计）
+	Profile   string    `gorm:"column:profile;size:64" json:"profile"`       // 浏览器配置目录名（为空时使用 account-<ID>）
+	Enabled   int       `gorm:"column:enabled;default:1" json:"enabled"`     // 是否参与投递（1=是，0=否）
+	Remark    string    `gorm:"column:remark" json:"remark"`                 // 备注
+	CreatedAt time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+func (AccountEntity) TableName() string {
+	return "account"
+}
+
+// BrowserProfile 账号使用的浏览器配置目录名
+func (e *AccountEntity) BrowserProfile() string {
+	if e.Profile != "" {
+		return e.Profile
+	}
+	return fmt.Sprintf("account-%d", e.ID)
+}
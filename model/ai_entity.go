@@ -7,6 +7,7 @@ import (
 // AiEntity AI配置实体类
 type AiEntity struct {
 	ID        int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	AccountId int64     `gorm:"column:account_id;index"` // 所属账号（0=默认账号）
 	Introduce string    `gorm:"column:introduce"`
 	Prompt    string    `gorm:"column:prompt"`
 	CreatedAt time.Time `gorm:"column:created_at"`
@@ -17,4 +18,55 @@ func (AiEntity) TableName() string {
 	return "ai"
 }
 
+// AiCacheEntity AI响应缓存实体类
+type AiCacheEntity struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	CacheKey         string    `gorm:"column:cache_key;size:64;uniqueIndex"` // 缓存键：sha256(提供方, 模型, 提示词)
+	Provider         string    `gorm:"column:provider"`                      // AI服务提供方
+	Model            string    `gorm:"column:model"`                         // 模型名称
+	Purpose          string    `gorm:"column:purpose"`                       // 调用用途（greeting/job_fit_score 等）
+	Prompt           string    `gorm:"column:prompt;type:text"`              // 渲染后的提示词
+	Response         string    `gorm:"column:response;type:text"`            // AI回复内容
+	PromptTokens     int       `gorm:"column:prompt_tokens"`
+	CompletionTokens int       `gorm:"column:completion_tokens"`
+	TotalTokens      int       `gorm:"column:total_tokens"`
+	HitCount         int       `gorm:"column:hit_count"`        // 缓存命中次数
+	ExpiresAt        time.Time `gorm:"column:expires_at;index"` // 过期时间
+	CreatedAt        time.Time `gorm:"column:created_at"`
+	UpdatedAt        time.Time `gorm:"column:updated_at"`
+}
+
+func (AiCacheEntity) TableName() string {
+	return "ai_cache"
+}
+
+// AiUsageEntity AI调用用量记录实体类
+type AiUsageEntity struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	AccountId        int64     `gorm:"column:account_id;index"`     // 所属账号（0=默认账号）
+	RunId            string    `gorm:"column:run_id;size:64;index"` // 运行ID（一次投递任务）
+	Purpose          string    `gorm:"column:purpose"`              // 调用用途（greeting/job_fit_score 等）
+	Provider         string    `gorm:"column:provider"`             // AI服务提供方
+	Model            string    `gorm:"column:model"`                // 模型名称
+	PromptTokens     int       `gorm:"column:prompt_tokens"`
+	CompletionTokens int       `gorm:"column:completion_tokens"`
+	TotalTokens      int       `gorm:"column:total_tokens"`
+	LatencyMs        int64     `gorm:"column:latency_ms"` // 调用耗时（毫秒，含重试）
+	Cost             float64   `gorm:"column:cost"`       // 估算费用（按价格表计算，缓存命中为0）
+	Cached           int       `gorm:"column:cached"`     // 是否命中缓存（1=是，0=否）
+	Success          int       `gorm:"column:success"`    // 是否成功（1=成功，0=失败）
+	CreatedAt        time.Time `gorm:"column:created_at;index"`
+}
+
+func (AiUsageEntity) TableName() string {
+	return "ai_usage"
+}
 
+// AiRunUsage 单次运行的AI用量汇总
+type AiRunUsage struct {
+	RunId       string    `json:"runId"`
+	Calls       int64     `json:"calls"`
+	TotalTokens int64     `json:"totalTokens"`
+	Cost        float64   `json:"cost"`
+	StartedAt   time.Time `json:"startedAt"`
+}
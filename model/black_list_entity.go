@@ -4,15 +4,32 @@ import (
 	"time"
 )
 
+// 黑名单匹配方式
+const (
+	BLACKLIST_MATCH_EXACT    = "exact"    // 完全相同
+	BLACKLIST_MATCH_CONTAINS = "contains" // 包含（默认，兼容旧数据）
+	BLACKLIST_MATCH_PREFIX   = "prefix"   // 前缀相同
+	BLACKLIST_MATCH_REGEX    = "regex"    // 正则表达式
+)
+
 // BlacklistEntity Boss黑名单实体类
 type BlacklistEntity struct {
-	ID        int64     `gorm:"primaryKey;autoIncrement;column:id"`
-	Type      string    `gorm:"column:type"`  // 类型：company(公司), recruiter(招聘者), job(职位)
-	Value     string    `gorm:"column:value"` // 黑名单值
-	CreatedAt time.Time `gorm:"column:created_at"`
-	UpdatedAt time.Time `gorm:"column:updated_at"`
+	ID        int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	AccountId int64      `gorm:"column:account_id;index"`                    // 所属账号（0=默认账号）
+	Type      string     `gorm:"column:type"`                                // 类型：company(公司), recruiter(招聘者), job(职位)
+	Value     string     `gorm:"column:value"`                               // 黑名单值
+	MatchMode string     `gorm:"column:match_mode;size:16;default:contains"` // 匹配方式：exact / contains / prefix / regex（忽略大小写与全半角）
+	ExpiresAt *time.Time `gorm:"column:expires_at"`                          // 过期时间（为空表示永久有效）
+	Reason    string     `gorm:"column:reason"`                              // 拉黑原因/备注
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	UpdatedAt time.Time  `gorm:"column:updated_at"`
 }
 
 func (BlacklistEntity) TableName() string {
 	return "boss_blacklist"
-}
\ No newline at end of file
+}
+
+// IsExpired 判断黑名单在指定时间是否已过期
+func (e *BlacklistEntity) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && !e.ExpiresAt.After(now)
+}
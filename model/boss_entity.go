@@ -7,24 +7,44 @@ import (
 // BossConfigEntity Boss配置实体类
 type BossConfigEntity struct {
 	ID                int64     `gorm:"primaryKey;autoIncrement;column:id"`
-	Debugger          int       `gorm:"column:debugger"`           // 调试模式（1=开启，0=关闭）
-	WaitTime          int       `gorm:"column:wait_time"`          // 页面操作等待时间（秒）
-	Keywords          string    `gorm:"column:keywords"`           // 搜索关键词
-	CityCode          string    `gorm:"column:city_code"`          // 城市（名称或代码，支持列表）
-	Industry          string    `gorm:"column:industry"`           // 行业（名称或代码，支持列表）
-	JobType           string    `gorm:"column:job_type"`           // 职位类型（名称或代码，单值或列表，优先取第一项）
-	Experience        string    `gorm:"column:experience"`         // 工作经验（名称或代码，支持列表）
-	Degree            string    `gorm:"column:degree"`             // 学历要求（名称或代码，支持列表）
-	Salary            string    `gorm:"column:salary"`             // 薪资区间（名称或代码，支持列表）
-	Scale             string    `gorm:"column:scale"`              // 公司规模（名称或代码，支持列表）
-	Stage             string    `gorm:"column:stage"`              // 融资阶段（名称或代码，支持列表）
-	SayHi             string    `gorm:"column:say_hi"`             // 默认打招呼语
-	ExpectedSalaryMin int       `gorm:"column:expected_salary_min"` // 期望薪资下限
-	ExpectedSalaryMax int       `gorm:"column:expected_salary_max"` // 期望薪资上限
-	EnableAi          int       `gorm:"column:enable_ai"`          // 是否启用AI生成打招呼（1=启用，0=关闭）
-	SendImgResume     int       `gorm:"column:send_img_resume"`    // 是否发送图片简历（1=启用，0=关闭）
-	FilterDeadHr      int       `gorm:"column:filter_dead_hr"`     // 是否过滤不在线HR（1=启用，0=关闭）
-	DeadStatus        string    `gorm:"column:dead_status"`        // HR不在线状态列表
+	AccountId         int64     `gorm:"column:account_id;index"`        // 所属账号（0=默认账号）
+	Debugger          int       `gorm:"column:debugger"`                // 调试模式（1=开启，0=关闭）
+	WaitTime          int       `gorm:"column:wait_time"`               // 页面操作等待时间（秒）
+	Keywords          string    `gorm:"column:keywords"`                // 搜索关键词
+	CityCode          string    `gorm:"column:city_code"`               // 城市（名称或代码，支持列表）
+	Industry          string    `gorm:"column:industry"`                // 行业（名称或代码，支持列表）
+	JobType           string    `gorm:"column:job_type"`                // 职位类型（名称或代码，单值或列表，优先取第一项）
+	Experience        string    `gorm:"column:experience"`              // 工作经验（名称或代码，支持列表）
+	Degree            string    `gorm:"column:degree"`                  // 学历要求（名称或代码，支持列表）
+	Salary            string    `gorm:"column:salary"`                  // 薪资区间（名称或代码，支持列表）
+	Scale             string    `gorm:"column:scale"`                   // 公司规模（名称或代码，支持列表）
+	Stage             string    `gorm:"column:stage"`                   // 融资阶段（名称或代码，支持列表）
+	SayHi             string    `gorm:"column:say_hi"`                  // 默认打招呼语
+	ExpectedSalaryMin int       `gorm:"column:expected_salary_min"`     // 期望薪资下限
+	ExpectedSalaryMax int       `gorm:"column:expected_salary_max"`     // 期望薪资上限
+	EnableAi          int       `gorm:"column:enable_ai"`               // 是否启用AI生成打招呼（1=启用，0=关闭）
+	SendImgResume     int       `gorm:"column:send_img_resume"`         // 是否发送图片简历（1=启用，0=关闭）
+	FilterDeadHr      int       `gorm:"column:filter_dead_hr"`          // 是否过滤不在线HR（1=启用，0=关闭）
+	DeadStatus        string    `gorm:"column:dead_status"`             // HR不在线状态列表
+	MaxInactiveDays   int       `gorm:"column:max_inactive_days"`       // HR最长不活跃天数（0=不限）
+	MaxCommuteKm      float64   `gorm:"column:max_commute_km"`          // 最大通勤半径（千米，0=不限）
+	Districts         string    `gorm:"column:business_districts"`      // 商圈列表（区县或商圈名称）
+	JdInclude         string    `gorm:"column:jd_include"`              // 职位描述必需关键词列表
+	JdExclude         string    `gorm:"column:jd_exclude"`              // 职位描述排除关键词列表
+	DetailConcurrency int       `gorm:"column:detail_concurrency"`      // 并发获取岗位详情的页面数（≤1=逐个获取）
+	DetailTimeout     int       `gorm:"column:detail_timeout"`          // 岗位详情获取超时时间（秒）
+	CaptureDir        string    `gorm:"column:capture_dir"`             // 接口原始响应记录目录（为空不记录）
+	EnableAiScore     int       `gorm:"column:enable_ai_score"`         // 是否启用AI岗位匹配度评分（1=启用，0=关闭）
+	AiScoreThreshold  int       `gorm:"column:ai_score_threshold"`      // AI匹配度过滤阈值（0-100）
+	EnableAiExtract   int       `gorm:"column:enable_ai_extract"`       // 是否启用AI提取职位描述结构化属性（1=启用，0=关闭）
+	FilterOvertime    int       `gorm:"column:filter_overtime"`         // 是否过滤有加班信号（大小周、996）的岗位（1=启用，0=关闭）
+	FilterOutsourcing int       `gorm:"column:filter_outsourcing"`      // 是否过滤外包/驻场岗位（1=启用，0=关闭）
+	MaxRequiredYears  int       `gorm:"column:max_required_years"`      // 可接受的最高年限要求（0=不限）
+	GreetingBanWords  string    `gorm:"column:greeting_banned_phrases"` // AI打招呼语禁用语列表
+	FilterHeadhunter  int       `gorm:"column:filter_headhunter"`       // 是否过滤猎头招聘的岗位（1=启用，0=关闭）
+	FilterAgency      int       `gorm:"column:filter_agency"`           // 是否过滤外包/派遣机构招聘的岗位（1=启用，0=关闭）
+	HunterCompanies   string    `gorm:"column:headhunter_companies"`    // 额外的猎头公司名单
+	AgencyCompanies   string    `gorm:"column:agency_companies"`        // 额外的外包/派遣公司名单
 	CreatedAt         time.Time `gorm:"column:created_at"`
 	UpdatedAt         time.Time `gorm:"column:updated_at"`
 }
@@ -46,7 +66,7 @@ func (BossIndustryEntity) TableName() string {
 	return "boss_industry"
 }
 
-// BossJobDataEntity Boss职位数据实体类
+// BossJobDataEntity 旧版Boss职位数据实体类（boss_data 表），职位已统一保存到 Job，仅用于启动时的一次性迁移
 type BossJobDataEntity struct {
 	ID                int64     `gorm:"primaryKey;autoIncrement;column:id"`
 	EncryptId         string    `gorm:"column:encrypt_id"`
@@ -77,6 +97,36 @@ func (BossJobDataEntity) TableName() string {
 	return "boss_data"
 }
 
+// ToJob 将旧版Boss职位数据映射为统一职位（归属默认账号）
+func (e *BossJobDataEntity) ToJob() *Job {
+	return &Job{
+		AccountId:      DEFAULT_ACCOUNT_ID,
+		Platform:       PLATFORM_BOSS,
+		PlatformJobId:  e.EncryptId,
+		PlatformUserId: e.EncryptUserId,
+		Href:           e.JobUrl,
+		JobName:        e.JobName,
+		JobArea:        e.Location,
+		City:           e.Location,
+		Address:        e.CompanyAddress,
+		JobInfo:        e.JobDescription,
+		Salary:         e.Salary,
+		Experience:     e.Experience,
+		Degree:         e.Degree,
+		CompanyName:    e.CompanyName,
+		CompanyInfo:    e.Introduce,
+		Industry:       e.Industry,
+		CompanyScale:   e.CompanyScale,
+		FinancingStage: e.FinancingStage,
+		Recruiter:      e.HrName,
+		RecruiterTitle: e.HrPosition,
+		HrActiveStatus: e.HrActiveStatus,
+		DeliveryStatus: e.DeliveryStatus,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
+
 // BossOptionEntity Boss选项实体类
 type BossOptionEntity struct {
 	ID        int64     `gorm:"primaryKey;autoIncrement;column:id"`
@@ -8,6 +8,7 @@ import (
 // CookieEntity Cookie实体类
 type CookieEntity struct {
 	ID         int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	AccountId  int64     `gorm:"column:account_id;index"` // 所属账号（0=默认账号）
 	Platform   string    `gorm:"column:platform"`    // 平台名称（boss/zhilian/job51/liepin）
 	CookieValue string   `gorm:"column:cookie_value"` // Cookie值
 	Remark     string    `gorm:"column:remark"`      // 备注
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+)
+
+// 过滤规则动作
+const (
+	RULE_ACTION_SKIP     = "skip"     // 跳过岗位，规则名称记录为过滤原因
+	RULE_ACTION_PRIORITY = "priority" // 投递岗位，并按优先级先投递优先级高的岗位
+	RULE_ACTION_TAG      = "tag"      // 为岗位打标签
+)
+
+// FilterRuleEntity 用户自定义过滤规则实体类
+type FilterRuleEntity struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	Name        string    `gorm:"column:name;size:64;uniqueIndex"` // 规则名称（skip 动作记录为过滤原因）
+	Expression  string    `gorm:"column:expression;type:text"`     // 规则表达式
+	Action      string    `gorm:"column:action"`                   // 动作：skip / priority / tag
+	Priority    int       `gorm:"column:priority"`                 // priority 动作的优先级加成（可为负数）
+	Tag         string    `gorm:"column:tag"`                      // tag 动作的标签（为空时使用规则名称）
+	Enabled     int       `gorm:"column:enabled"`                  // 是否启用（1=启用，0=停用）
+	SortOrder   int       `gorm:"column:sort_order"`               // 执行顺序（升序）
+	Description string    `gorm:"column:description"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+func (FilterRuleEntity) TableName() string {
+	return "filter_rule"
+}
@@ -1,13 +1,138 @@
 package model
 
+import (
+	"fmt"
+	"time"
+)
+
+// 平台名称
+const (
+	PLATFORM_BOSS    = "boss"
+	PLATFORM_ZHILIAN = "zhilian"
+	PLATFORM_JOB51   = "job51"
+	PLATFORM_LIEPIN  = "liepin"
+)
+
+// 投递状态
+const (
+	DELIVERY_STATUS_PENDING   = "未投递"
+	DELIVERY_STATUS_DELIVERED = "已投递"
+	DELIVERY_STATUS_FILTERED  = "已过滤"
+	DELIVERY_STATUS_FAILED    = "投递失败"
+	DELIVERY_STATUS_CONTACTED = "已沟通" // 平台上已与该HR沟通过（如Boss详情页按钮为“继续沟通”），不再打招呼
+)
+
+// 过滤原因
+const (
+	FILTER_REASON_JOB_BLACKLIST       = "job_blacklist"
+	FILTER_REASON_COMPANY_BLACKLIST   = "company_blacklist"
+	FILTER_REASON_RECRUITER_BLACKLIST = "recruiter_blacklist"
+	FILTER_REASON_DEAD_HR             = "dead_hr"
+	FILTER_REASON_DUPLICATE           = "cross_platform_duplicate"
+	FILTER_REASON_HR_CONTACTED        = "hr_contacted"
+	FILTER_REASON_AI_LOW_SCORE        = "ai_low_score"
+	FILTER_REASON_OVERTIME            = "overtime"
+	FILTER_REASON_OUTSOURCING         = "outsourcing"
+	FILTER_REASON_REQUIRED_YEARS      = "required_years"
+	FILTER_REASON_HEADHUNTER          = "headhunter"
+	FILTER_REASON_AGENCY              = "outsourcing_agency"
+	FILTER_REASON_COMMUTE             = "commute_distance"
+	FILTER_REASON_DISTRICT            = "business_district"
+	FILTER_REASON_JD_INCLUDE          = "jd_include" // 未命中必需关键词，完整原因为 jd_include:<条目>
+	FILTER_REASON_JD_EXCLUDE          = "jd_exclude" // 命中排除关键词，完整原因为 jd_exclude:<条目>
+)
+
+// 招聘方类型
+const (
+	RECRUITER_TYPE_HEADHUNTER  = "headhunter"  // 猎头
+	RECRUITER_TYPE_OUTSOURCING = "outsourcing" // 外包/派遣机构
+	RECRUITER_TYPE_DIRECT      = "direct"      // 直招
+)
+
+// 打招呼语来源
+const (
+	GREETING_SOURCE_AI        = "ai"        // AI生成并通过校验
+	GREETING_SOURCE_AI_RETRY  = "ai_retry"  // 首次校验未通过，重新生成后通过
+	GREETING_SOURCE_FALLBACK  = "fallback"  // AI生成未通过校验或调用失败，回退到默认打招呼语
+	GREETING_SOURCE_TEMPLATE  = "template"  // 未启用AI，使用默认打招呼语
+	GREETING_SOURCE_WHITELIST = "whitelist" // 使用白名单条目的自定义打招呼语
+)
+
+// 工作模式（AI从职位描述中提取）
+const (
+	WORK_MODE_REMOTE = "remote"
+	WORK_MODE_HYBRID = "hybrid"
+	WORK_MODE_ONSITE = "onsite"
+)
+
+// Job 统一的跨平台职位实体，各平台 worker 解析出的岗位都映射到此结构
 type Job struct {
-	Href        string `json:"href"`        //岗位链接
-	JobName     string `json:"jobName"`     //岗位名称
-	JobArea     string `json:"jobArea"`     //岗位地区
-	JobInfo     string `json:"jobInfo"`     //岗位信息
-	Salary      string `json:"salary"`      //岗位薪水
-	CompanyTag  string `json:"companyTag"`  //公司标签
-	Recruiter   string `json:"recruiter"`   //HR名称
-	CompanyName string `json:"companyName"` //公司名字
-	CompanyInfo string `json:"companyInfo"` //公司信息
+	ID              int64     `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	AccountId       int64     `gorm:"column:account_id;uniqueIndex:idx_job_account_platform_job" json:"accountId"`                   // 所属账号（0=默认账号）
+	Platform        string    `gorm:"column:platform;size:32;uniqueIndex:idx_job_account_platform_job" json:"platform"`              // 平台名称（boss/zhilian/job51/liepin）
+	PlatformJobId   string    `gorm:"column:platform_job_id;size:128;uniqueIndex:idx_job_account_platform_job" json:"platformJobId"` // 平台内职位ID（Boss为encryptId，缺失时为 NULL）
+	PlatformUserId  string    `gorm:"column:platform_user_id" json:"platformUserId"`                                                 // 平台内招聘者ID（Boss为encryptUserId）
+	DedupeKey       string    `gorm:"column:dedupe_key;size:255;index" json:"dedupeKey"`                                             // 去重键：规范化的 公司|职位|城市
+	Href            string    `gorm:"column:href" json:"href"`                                                                       // 岗位链接
+	JobName         string    `gorm:"column:job_name" json:"jobName"`                                                                // 岗位名称
+	JobArea         string    `gorm:"column:job_area" json:"jobArea"`                                                                // 岗位地区
+	City            string    `gorm:"column:city" json:"city"`                                                                       // 城市
+	Address         string    `gorm:"column:address" json:"address"`                                                                 // 工作地址
+	District        string    `gorm:"column:district" json:"district"`                                                               // 区县与商圈（如 南山区·科技园）
+	Longitude       *float64  `gorm:"column:longitude" json:"longitude"`                                                             // 工作地址经度
+	Latitude        *float64  `gorm:"column:latitude" json:"latitude"`                                                               // 工作地址纬度
+	DistanceKm      *float64  `gorm:"column:distance_km" json:"distanceKm"`                                                          // 到最近通勤点的距离（千米，未知为空）
+	CommutePoint    string    `gorm:"column:commute_point" json:"commutePoint"`                                                      // 最近的通勤点名称
+	JobInfo         string    `gorm:"column:job_info;type:text" json:"jobInfo"`                                                      // 岗位信息（职位描述）
+	JobLabels       string    `gorm:"column:job_labels" json:"jobLabels"`                                                            // 职位标签（逗号分隔）
+	JdHighlights    string    `gorm:"column:jd_highlights" json:"jdHighlights"`                                                      // 职位描述与标签中命中的包含/排除关键词（逗号分隔）
+	Salary          string    `gorm:"column:salary" json:"salary"`                                                                   // 岗位薪水
+	Experience      string    `gorm:"column:experience" json:"experience"`                                                           // 经验要求
+	Degree          string    `gorm:"column:degree" json:"degree"`                                                                   // 学历要求
+	CompanyTag      string    `gorm:"column:company_tag" json:"companyTag"`                                                          // 公司标签
+	CompanyName     string    `gorm:"column:company_name" json:"companyName"`                                                        // 公司名字
+	CompanyInfo     string    `gorm:"column:company_info;type:text" json:"companyInfo"`                                              // 公司信息
+	Industry        string    `gorm:"column:industry" json:"industry"`                                                               // 所属行业
+	CompanyScale    string    `gorm:"column:company_scale" json:"companyScale"`                                                      // 公司规模
+	FinancingStage  string    `gorm:"column:financing_stage" json:"financingStage"`                                                  // 融资阶段
+	Recruiter       string    `gorm:"column:recruiter" json:"recruiter"`                                                             // HR名称
+	RecruiterTitle  string    `gorm:"column:recruiter_title" json:"recruiterTitle"`                                                  // HR职位
+	HrActiveStatus  string    `gorm:"column:hr_active_status" json:"hrActiveStatus"`                                                 // HR活跃状态
+	HrActivity      string    `gorm:"column:hr_activity" json:"hrActivity"`                                                          // HR活跃度等级（online/just_now/today/.../over_half_year，无法识别为空）
+	HrInactiveDays  *int      `gorm:"column:hr_inactive_days" json:"hrInactiveDays"`                                                 // HR距上次活跃的大致天数（无法识别为空）
+	RecruiterType   string    `gorm:"column:recruiter_type" json:"recruiterType"`                                                    // 招聘方类型（headhunter/outsourcing/direct）
+	RecruiterReason string    `gorm:"column:recruiter_type_reason" json:"recruiterTypeReason"`                                       // 招聘方类型的判断依据
+	DeliveryStatus  string    `gorm:"column:delivery_status" json:"deliveryStatus"`                                                  // 未投递 / 已投递 / 已过滤 / 投递失败
+	FilterReason    string    `gorm:"column:filter_reason" json:"filterReason"`                                                      // 过滤原因
+	AiScore         *int      `gorm:"column:ai_score" json:"aiScore"`                                                                // AI匹配度评分（0-100，未评分为空）
+	AiMatchedSkills string    `gorm:"column:ai_matched_skills" json:"aiMatchedSkills"`                                               // AI评估的匹配技能（逗号分隔）
+	AiMissingSkills string    `gorm:"column:ai_missing_skills" json:"aiMissingSkills"`                                               // AI评估的缺失技能（逗号分隔）
+	AiScoreReason   string    `gorm:"column:ai_score_reason;type:text" json:"aiScoreReason"`                                         // AI评分理由
+	JdAttributes    string    `gorm:"column:jd_attributes;type:text" json:"jdAttributes"`                                            // AI从职位描述提取的结构化属性（JSON）
+	TechStack       string    `gorm:"column:tech_stack" json:"techStack"`                                                            // 技术栈（逗号分隔）
+	RequiredYears   *int      `gorm:"column:required_years" json:"requiredYears"`                                                    // 要求工作年限（未提及为空）
+	WorkMode        string    `gorm:"column:work_mode" json:"workMode"`                                                              // 工作模式（remote/hybrid/onsite）
+	OvertimeSignals string    `gorm:"column:overtime_signals" json:"overtimeSignals"`                                                // 加班信号（大小周、996等，逗号分隔）
+	Outsourcing     int       `gorm:"column:outsourcing" json:"outsourcing"`                                                         // 是否外包/驻场（1=是，0=否）
+	Education       string    `gorm:"column:education" json:"education"`                                                             // 学历要求（职位描述中提及的）
+	TeamSize        string    `gorm:"column:team_size" json:"teamSize"`                                                              // 团队规模
+	Greeting        string    `gorm:"column:greeting;type:text" json:"greeting"`                                                     // 实际发送的打招呼语
+	GreetingSource  string    `gorm:"column:greeting_source" json:"greetingSource"`                                                  // 打招呼语来源（ai/ai_retry/fallback/template）
+	GreetingReject  string    `gorm:"column:greeting_reject_reason" json:"greetingRejectReason"`                                     // AI打招呼语未通过校验的原因
+	Priority        int       `gorm:"column:priority" json:"priority"`                                                               // 投递优先级（白名单与过滤规则累计，越大越先投递）
+	Tags            string    `gorm:"column:tags" json:"tags"`                                                                       // 过滤规则打的标签（逗号分隔）
+	WhitelistHit    string    `gorm:"column:whitelist_hit" json:"whitelistHit"`                                                      // 命中的白名单条目（类型:值）
+	HitKeywords     string    `gorm:"column:hit_keywords" json:"hitKeywords"`                                                        // 搜到该职位的搜索关键词（逗号分隔）
+	CreatedAt       time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt       time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+func (Job) TableName() string {
+	return "job"
+}
+
+// String 实现 Stringer 接口
+func (j *Job) String() string {
+	return fmt.Sprintf("【%s, %s, %s, %s, %s, %s】",
+		j.CompanyName, j.JobName, j.JobArea, j.Salary, j.CompanyTag, j.Recruiter)
 }
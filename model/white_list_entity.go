@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+)
+
+// 白名单类型
+const (
+	WHITELIST_TYPE_COMPANY = "company" // 匹配公司名称
+	WHITELIST_TYPE_JOB     = "job"     // 匹配职位名称
+	WHITELIST_TYPE_KEYWORD = "keyword" // 匹配职位名称或职位描述
+)
+
+// DEFAULT_WHITELIST_PRIORITY 白名单默认的投递优先级加成
+const DEFAULT_WHITELIST_PRIORITY = 100
+
+// WhitelistEntity Boss白名单（优先投递）实体类
+type WhitelistEntity struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	AccountId int64      `gorm:"column:account_id;index"`                    // 所属账号（0=默认账号）
+	Type      string     `gorm:"column:type"`                                // 类型：company(公司), job(职位), keyword(职位名称或描述中的关键词)
+	Value     string     `gorm:"column:value"`                               // 白名单值
+	MatchMode string     `gorm:"column:match_mode;size:16;default:contains"` // 匹配方式，同黑名单：exact / contains / prefix / regex
+	Priority  int        `gorm:"column:priority"`                            // 投递优先级加成（越大越先投递）
+	Greeting  string     `gorm:"column:greeting;type:text"`                  // 自定义打招呼语（为空时按常规方式生成）
+	ExpiresAt *time.Time `gorm:"column:expires_at"`                          // 过期时间（为空表示永久有效）
+	Reason    string     `gorm:"column:reason"`                              // 备注
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	UpdatedAt time.Time  `gorm:"column:updated_at"`
+}
+
+func (WhitelistEntity) TableName() string {
+	return "boss_whitelist"
+}
+
+// IsExpired 判断白名单在指定时间是否已过期
+func (e *WhitelistEntity) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && !e.ExpiresAt.After(now)
+}
+
+// Label 白名单条目的展示名称（类型:值）
+func (e *WhitelistEntity) Label() string {
+	return e.Type + ":" + e.Value
+}
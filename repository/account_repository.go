@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"get_jobs_go/model"
+
+	"gorm.io/gorm"
+)
+
+// AccountRepository 账号仓储接口
+type AccountRepository interface {
+	FindAll() ([]*model.AccountEntity, error)
+	FindEnabled() ([]*model.AccountEntity, error)
+	FindByID(id int64) (*model.AccountEntity, error)
+	FindByName(name string) (*model.AccountEntity, error)
+	Save(account *model.AccountEntity) error
+	Update(account *model.AccountEntity) error
+	Delete(id int64) error
+}
+
+type accountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepository{db: db}
+}
+
+func (r *accountRepository) FindAll() ([]*model.AccountEntity, error) {
+	var accounts []*model.AccountEntity
+	err := r.db.Order("id ASC").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *accountRepository) FindEnabled() ([]*model.AccountEntity, error) {
+	var accounts []*model.AccountEntity
+	err := r.db.Where("enabled = ?", 1).Order("id ASC").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *accountRepository) FindByID(id int64) (*model.AccountEntity, error) {
+	var account model.AccountEntity
+	err := r.db.First(&account, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &account, err
+}
+
+func (r *accountRepository) FindByName(name string) (*model.AccountEntity, error) {
+	var account model.AccountEntity
+	err := r.db.Where("name = ?", name).First(&account).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &account, err
+}
+
+func (r *accountRepository) Save(account *model.AccountEntity) error {
+	return r.db.Create(account).Error
+}
+
+func (r *accountRepository) Update(account *model.AccountEntity) error {
+	return r.db.Save(account).Error
+}
+
+func (r *accountRepository) Delete(id int64) error {
+	return r.db.Delete(&model.AccountEntity{}, id).Error
+}
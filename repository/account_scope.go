@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ACCOUNT_SCOPE_KEY 数据库会话中保存当前账号ID的键
+const ACCOUNT_SCOPE_KEY = "account_scope:account_id"
+
+// ACCOUNT_ID_FIELD 账号隔离的表共有的字段名
+const ACCOUNT_ID_FIELD = "AccountId"
+
+// RegisterAccountScope 注册账号隔离回调：通过 WithAccount 获得的会话在查询、统计、更新、删除时自动追加 account_id 条件，
+// 创建时自动写入 account_id。只对含 AccountId 字段的表生效，选项、行业、AI缓存等共享表不受影响
+func RegisterAccountScope(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("account_scope:create", assignAccountId); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("account_scope:query", restrictToAccount); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("account_scope:row", restrictToAccount); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("account_scope:update", restrictToAccount); err != nil {
+		return err
+	}
+	return callbacks.Delete().Before("gorm:delete").Register("account_scope:delete", restrictToAccount)
+}
+
+// WithAccount 返回限定在指定账号下的数据库会话（可重复使用），基于该会话创建的仓储只读写该账号的数据
+func WithAccount(db *gorm.DB, accountId int64) *gorm.DB {
+	return db.Set(ACCOUNT_SCOPE_KEY, accountId).Session(&gorm.Session{})
+}
+
+// scopedAccountId 当前会话限定的账号ID（会话未限定账号或表不区分账号时返回 false）
+func scopedAccountId(tx *gorm.DB) (int64, bool) {
+	value, ok := tx.Get(ACCOUNT_SCOPE_KEY)
+	if !ok {
+		return 0, false
+	}
+	if tx.Statement.Schema == nil || tx.Statement.Schema.LookUpField(ACCOUNT_ID_FIELD) == nil {
+		return 0, false
+	}
+	accountId, ok := value.(int64)
+	return accountId, ok
+}
+
+// assignAccountId 创建记录时写入当前账号ID
+func assignAccountId(tx *gorm.DB) {
+	if accountId, ok := scopedAccountId(tx); ok {
+		tx.Statement.SetColumn(ACCOUNT_ID_FIELD, accountId, true)
+	}
+}
+
+// restrictToAccount 查询、更新、删除时只作用于当前账号的数据
+func restrictToAccount(tx *gorm.DB) {
+	accountId, ok := scopedAccountId(tx)
+	if !ok {
+		return
+	}
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "account_id"}, Value: accountId},
+	}})
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"get_jobs_go/model"
 	"log"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -82,4 +83,103 @@ func (r *aiRepository) FindLatest() (*model.AiEntity, error) {
 		return nil, result.Error
 	}
 	return &ai, nil
-}
\ No newline at end of file
+}
+
+// AiCacheRepository AI响应缓存仓储接口
+type AiCacheRepository interface {
+	FindByCacheKey(cacheKey string) (*model.AiCacheEntity, error)
+	Save(cache *model.AiCacheEntity) error
+	IncrementHitCount(id int64) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type aiCacheRepository struct {
+	db *gorm.DB
+}
+
+func NewAiCacheRepository(db *gorm.DB) AiCacheRepository {
+	return &aiCacheRepository{db: db}
+}
+
+func (r *aiCacheRepository) FindByCacheKey(cacheKey string) (*model.AiCacheEntity, error) {
+	var cache model.AiCacheEntity
+	result := r.db.Where("cache_key = ?", cacheKey).First(&cache)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &cache, nil
+}
+
+func (r *aiCacheRepository) Save(cache *model.AiCacheEntity) error {
+	result := r.db.Save(cache)
+	return result.Error
+}
+
+func (r *aiCacheRepository) IncrementHitCount(id int64) error {
+	result := r.db.Model(&model.AiCacheEntity{}).
+		Where("id = ?", id).
+		UpdateColumn("hit_count", gorm.Expr("hit_count + ?", 1))
+	return result.Error
+}
+
+func (r *aiCacheRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&model.AiCacheEntity{})
+	return result.RowsAffected, result.Error
+}
+
+// AiUsageRepository AI调用用量仓储接口
+type AiUsageRepository interface {
+	Save(usage *model.AiUsageEntity) error
+	SumCostSince(since time.Time) (float64, error)
+	SumCostByRunId(runId string) (float64, error)
+	SummarizeRecentRuns(limit int) ([]*model.AiRunUsage, error)
+}
+
+type aiUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewAiUsageRepository(db *gorm.DB) AiUsageRepository {
+	return &aiUsageRepository{db: db}
+}
+
+func (r *aiUsageRepository) Save(usage *model.AiUsageEntity) error {
+	result := r.db.Create(usage)
+	return result.Error
+}
+
+func (r *aiUsageRepository) SumCostSince(since time.Time) (float64, error) {
+	var total float64
+	result := r.db.Model(&model.AiUsageEntity{}).
+		Where("created_at >= ?", since).
+		Select("COALESCE(SUM(cost), 0)").
+		Scan(&total)
+	return total, result.Error
+}
+
+func (r *aiUsageRepository) SumCostByRunId(runId string) (float64, error) {
+	var total float64
+	result := r.db.Model(&model.AiUsageEntity{}).
+		Where("run_id = ?", runId).
+		Select("COALESCE(SUM(cost), 0)").
+		Scan(&total)
+	return total, result.Error
+}
+
+func (r *aiUsageRepository) SummarizeRecentRuns(limit int) ([]*model.AiRunUsage, error) {
+	var runs []*model.AiRunUsage
+	result := r.db.Model(&model.AiUsageEntity{}).
+		Select("run_id, COUNT(*) AS calls, COALESCE(SUM(total_tokens), 0) AS total_tokens, COALESCE(SUM(cost), 0) AS cost, MIN(created_at) AS started_at").
+		Where("run_id <> ''").
+		Group("run_id").
+		Order("started_at DESC").
+		Limit(limit).
+		Scan(&runs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return runs, nil
+}
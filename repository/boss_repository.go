@@ -2,7 +2,6 @@ package repository
 
 import (
 	"get_jobs_go/model"
-	"time"
 
 	"gorm.io/gorm"
 )
@@ -215,7 +214,10 @@ func (r *bossConfigRepository) Delete(id int64) error {
 type BlacklistRepository interface {
 	FindByType(typeStr string) ([]*model.BlacklistEntity, error)
 	FindAll() ([]*model.BlacklistEntity, error)
+	FindByID(id int64) (*model.BlacklistEntity, error)
 	Save(blacklist *model.BlacklistEntity) error
+	Update(blacklist *model.BlacklistEntity) error
+	Delete(id int64) error
 	DeleteByTypeAndValue(typeStr, value string) error
 	CountByTypeAndValue(typeStr, value string) (int64, error)
 }
@@ -246,11 +248,33 @@ func (r *blacklistRepository) FindAll() ([]*model.BlacklistEntity, error) {
 	return blacklists, nil
 }
 
+func (r *blacklistRepository) FindByID(id int64) (*model.BlacklistEntity, error) {
+	var blacklist model.BlacklistEntity
+	result := r.db.First(&blacklist, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &blacklist, nil
+}
+
 func (r *blacklistRepository) Save(blacklist *model.BlacklistEntity) error {
 	result := r.db.Create(blacklist)
 	return result.Error
 }
 
+func (r *blacklistRepository) Update(blacklist *model.BlacklistEntity) error {
+	result := r.db.Save(blacklist)
+	return result.Error
+}
+
+func (r *blacklistRepository) Delete(id int64) error {
+	result := r.db.Delete(&model.BlacklistEntity{}, id)
+	return result.Error
+}
+
 func (r *blacklistRepository) DeleteByTypeAndValue(typeStr, value string) error {
 	result := r.db.Where("type = ? AND value = ?", typeStr, value).Delete(&model.BlacklistEntity{})
 	return result.Error
@@ -262,98 +286,62 @@ func (r *blacklistRepository) CountByTypeAndValue(typeStr, value string) (int64,
 	return count, result.Error
 }
 
-// BossJobDataRepository Boss职位数据仓储接口
-type BossJobDataRepository interface {
-	FindAll() ([]*model.BossJobDataEntity, error)
-	FindByEncryptIdAndUserId(encryptId, encryptUserId string) (*model.BossJobDataEntity, error)
-	FindByEncryptId(encryptId string) (*model.BossJobDataEntity, error)
-	Save(job *model.BossJobDataEntity) error
-	Update(job *model.BossJobDataEntity) error
-	UpdateDeliveryStatus(encryptId, encryptUserId, status string) error
-	CountByCondition(condition string, args ...interface{}) (int64, error)
-	FindByWrapper(wrapper *gorm.DB) ([]*model.BossJobDataEntity, error)
-	CountByWrapper(wrapper *gorm.DB) (int64, error)
+// WhitelistRepository 白名单仓储接口
+type WhitelistRepository interface {
+	FindAll() ([]*model.WhitelistEntity, error)
+	FindByID(id int64) (*model.WhitelistEntity, error)
+	Save(whitelist *model.WhitelistEntity) error
+	Update(whitelist *model.WhitelistEntity) error
+	Delete(id int64) error
+	CountByTypeAndValue(typeStr, value string) (int64, error)
 }
 
-type bossJobDataRepository struct {
+type whitelistRepository struct {
 	db *gorm.DB
 }
 
-func NewBossJobDataRepository(db *gorm.DB) BossJobDataRepository {
-	return &bossJobDataRepository{db: db}
+func NewWhitelistRepository(db *gorm.DB) WhitelistRepository {
+	return &whitelistRepository{db: db}
 }
 
-func (r *bossJobDataRepository) FindAll() ([]*model.BossJobDataEntity, error) {
-	var jobs []*model.BossJobDataEntity
-	result := r.db.Find(&jobs)
+func (r *whitelistRepository) FindAll() ([]*model.WhitelistEntity, error) {
+	var whitelists []*model.WhitelistEntity
+	result := r.db.Order("priority DESC, id ASC").Find(&whitelists)
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	return jobs, nil
+	return whitelists, nil
 }
 
-func (r *bossJobDataRepository) FindByEncryptIdAndUserId(encryptId, encryptUserId string) (*model.BossJobDataEntity, error) {
-	var job model.BossJobDataEntity
-	result := r.db.Where("encrypt_id = ? AND encrypt_user_id = ?", encryptId, encryptUserId).First(&job)
+func (r *whitelistRepository) FindByID(id int64) (*model.WhitelistEntity, error) {
+	var whitelist model.WhitelistEntity
+	result := r.db.First(&whitelist, id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
 		return nil, result.Error
 	}
-	return &job, nil
+	return &whitelist, nil
 }
 
-func (r *bossJobDataRepository) FindByEncryptId(encryptId string) (*model.BossJobDataEntity, error) {
-	var job model.BossJobDataEntity
-	result := r.db.Where("encrypt_id = ?", encryptId).First(&job)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return nil, nil
-		}
-		return nil, result.Error
-	}
-	return &job, nil
-}
-
-func (r *bossJobDataRepository) Save(job *model.BossJobDataEntity) error {
-	result := r.db.Create(job)
+func (r *whitelistRepository) Save(whitelist *model.WhitelistEntity) error {
+	result := r.db.Create(whitelist)
 	return result.Error
 }
 
-func (r *bossJobDataRepository) Update(job *model.BossJobDataEntity) error {
-	result := r.db.Save(job)
+func (r *whitelistRepository) Update(whitelist *model.WhitelistEntity) error {
+	result := r.db.Save(whitelist)
 	return result.Error
 }
 
-func (r *bossJobDataRepository) UpdateDeliveryStatus(encryptId, encryptUserId, status string) error {
-	result := r.db.Model(&model.BossJobDataEntity{}).
-		Where("encrypt_id = ?", encryptId).
-		Where("encrypt_user_id = ?", encryptUserId).
-		Updates(map[string]interface{}{
-			"delivery_status": status,
-			"updated_at":      time.Now(),
-		})
+func (r *whitelistRepository) Delete(id int64) error {
+	result := r.db.Delete(&model.WhitelistEntity{}, id)
 	return result.Error
 }
 
-func (r *bossJobDataRepository) CountByCondition(condition string, args ...interface{}) (int64, error) {
+func (r *whitelistRepository) CountByTypeAndValue(typeStr, value string) (int64, error) {
 	var count int64
-	result := r.db.Model(&model.BossJobDataEntity{}).Where(condition, args...).Count(&count)
+	result := r.db.Model(&model.WhitelistEntity{}).Where("type = ? AND value = ?", typeStr, value).Count(&count)
 	return count, result.Error
 }
-
-func (r *bossJobDataRepository) FindByWrapper(wrapper *gorm.DB) ([]*model.BossJobDataEntity, error) {
-	var jobs []*model.BossJobDataEntity
-	result := wrapper.Find(&jobs)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return jobs, nil
-}
-
-func (r *bossJobDataRepository) CountByWrapper(wrapper *gorm.DB) (int64, error) {
-	var count int64
-	result := wrapper.Count(&count)
-	return count, result.Error
-}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"get_jobs_go/model"
+
+	"gorm.io/gorm"
+)
+
+// FilterRuleRepository 过滤规则仓储接口
+type FilterRuleRepository interface {
+	FindAll() ([]*model.FilterRuleEntity, error)
+	FindEnabled() ([]*model.FilterRuleEntity, error)
+	FindByName(name string) (*model.FilterRuleEntity, error)
+	Save(rule *model.FilterRuleEntity) error
+	Update(rule *model.FilterRuleEntity) error
+	Delete(id int64) error
+}
+
+type filterRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewFilterRuleRepository(db *gorm.DB) FilterRuleRepository {
+	return &filterRuleRepository{db: db}
+}
+
+func (r *filterRuleRepository) FindAll() ([]*model.FilterRuleEntity, error) {
+	var rules []*model.FilterRuleEntity
+	err := r.db.Order("sort_order ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *filterRuleRepository) FindEnabled() ([]*model.FilterRuleEntity, error) {
+	var rules []*model.FilterRuleEntity
+	err := r.db.Where("enabled = ?", 1).Order("sort_order ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *filterRuleRepository) FindByName(name string) (*model.FilterRuleEntity, error) {
+	var rule model.FilterRuleEntity
+	err := r.db.Where("name = ?", name).First(&rule).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &rule, err
+}
+
+func (r *filterRuleRepository) Save(rule *model.FilterRuleEntity) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *filterRuleRepository) Update(rule *model.FilterRuleEntity) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *filterRuleRepository) Delete(id int64) error {
+	return r.db.Delete(&model.FilterRuleEntity{}, id).Error
+}
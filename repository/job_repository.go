@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"get_jobs_go/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// JobRepository 统一职位仓储接口
+type JobRepository interface {
+	FindByID(id int64) (*model.Job, error)
+	FindByPlatformJobId(platform, platformJobId string) (*model.Job, error)
+	FindByDedupeKey(dedupeKey string) ([]*model.Job, error)
+	FindRecent(limit int) ([]*model.Job, error)
+	Save(job *model.Job) error
+	Update(job *model.Job) error
+	UpdateDeliveryStatus(platform, platformJobId, status, filterReason string) error
+	CountByCondition(condition string, args ...interface{}) (int64, error)
+	FindByWrapper(wrapper *gorm.DB) ([]*model.Job, error)
+	CountByWrapper(wrapper *gorm.DB) (int64, error)
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) FindByID(id int64) (*model.Job, error) {
+	var job model.Job
+	result := r.db.First(&job, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) FindByPlatformJobId(platform, platformJobId string) (*model.Job, error) {
+	var job model.Job
+	result := r.db.Where("platform = ? AND platform_job_id = ?", platform, platformJobId).First(&job)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) FindByDedupeKey(dedupeKey string) ([]*model.Job, error) {
+	var jobs []*model.Job
+	result := r.db.Where("dedupe_key = ?", dedupeKey).Order("id ASC").Find(&jobs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return jobs, nil
+}
+
+// FindRecent 按ID倒序查询最近的职位，limit <= 0 时查询全部
+func (r *jobRepository) FindRecent(limit int) ([]*model.Job, error) {
+	var jobs []*model.Job
+	query := r.db.Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	result := query.Find(&jobs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) Save(job *model.Job) error {
+	result := r.withPlatformJobId(job).Create(job)
+	return result.Error
+}
+
+func (r *jobRepository) Update(job *model.Job) error {
+	result := r.withPlatformJobId(job).Save(job)
+	return result.Error
+}
+
+// withPlatformJobId 平台职位ID缺失时不写入该列，使其保持 NULL：
+// 唯一索引 account_id+platform+platform_job_id 中多个 NULL 互不冲突，而多个空字符串会冲突
+func (r *jobRepository) withPlatformJobId(job *model.Job) *gorm.DB {
+	if job.PlatformJobId == "" {
+		return r.db.Omit("PlatformJobId")
+	}
+	return r.db
+}
+
+func (r *jobRepository) UpdateDeliveryStatus(platform, platformJobId, status, filterReason string) error {
+	result := r.db.Model(&model.Job{}).
+		Where("platform = ?", platform).
+		Where("platform_job_id = ?", platformJobId).
+		Updates(map[string]interface{}{
+			"delivery_status": status,
+			"filter_reason":   filterReason,
+			"updated_at":      time.Now(),
+		})
+	return result.Error
+}
+
+func (r *jobRepository) CountByCondition(condition string, args ...interface{}) (int64, error) {
+	var count int64
+	result := r.db.Model(&model.Job{}).Where(condition, args...).Count(&count)
+	return count, result.Error
+}
+
+func (r *jobRepository) FindByWrapper(wrapper *gorm.DB) ([]*model.Job, error) {
+	var jobs []*model.Job
+	result := wrapper.Find(&jobs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) CountByWrapper(wrapper *gorm.DB) (int64, error) {
+	var count int64
+	result := wrapper.Count(&count)
+	return count, result.Error
+}
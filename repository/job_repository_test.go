@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"testing"
+
+	"get_jobs_go/model"
+)
+
+func TestJobRepositorySaveWithoutPlatformJobId(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewJobRepository(db)
+
+	// 早期以空字符串保存的职位在迁移时改为 NULL
+	if err := db.Create(&model.Job{Platform: model.PLATFORM_BOSS, CompanyName: "字节跳动"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := []*model.Job{
+		{Platform: model.PLATFORM_BOSS, CompanyName: "腾讯", JobName: "后端开发"},
+		{Platform: model.PLATFORM_BOSS, CompanyName: "阿里巴巴", JobName: "Java开发"},
+	}
+	for _, job := range jobs {
+		if err := repo.Save(job); err != nil {
+			t.Fatalf("保存缺少职位ID的职位 %s: %v", job.CompanyName, err)
+		}
+	}
+
+	// 更新时同样不能把 NULL 写成空字符串
+	jobs[0].DeliveryStatus = model.DELIVERY_STATUS_DELIVERED
+	if err := repo.Update(jobs[0]); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := repo.Save(&model.Job{Platform: model.PLATFORM_BOSS, CompanyName: "百度", JobName: "测试"}); err != nil {
+		t.Fatalf("更新后再次保存: %v", err)
+	}
+
+	var nullCount int64
+	if err := db.Model(&model.Job{}).Where("platform_job_id IS NULL").Count(&nullCount).Error; err != nil {
+		t.Fatal(err)
+	}
+	if nullCount != 4 {
+		t.Errorf("platform_job_id 为 NULL 的职位数 = %d, 期望 4", nullCount)
+	}
+
+	saved, err := repo.FindByID(jobs[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved == nil || saved.PlatformJobId != "" || saved.DeliveryStatus != model.DELIVERY_STATUS_DELIVERED {
+		t.Errorf("读取的职位 = %+v", saved)
+	}
+}
+
+func TestJobRepositorySaveDuplicatePlatformJobId(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewJobRepository(db)
+
+	if err := repo.Save(&model.Job{Platform: model.PLATFORM_BOSS, PlatformJobId: "job-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(&model.Job{Platform: model.PLATFORM_BOSS, PlatformJobId: "job-1"}); err == nil {
+		t.Error("同一账号同一平台的职位ID重复时应违反唯一索引")
+	}
+	other := &model.Job{AccountId: 2, Platform: model.PLATFORM_BOSS, PlatformJobId: "job-1"}
+	if err := repo.Save(other); err != nil {
+		t.Errorf("不同账号可保存同一职位: %v", err)
+	}
+}
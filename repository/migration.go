@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"fmt"
+	"get_jobs_go/model"
+	"get_jobs_go/utils"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LEGACY_JOB_PLATFORM_INDEX 多账号之前的职位唯一索引（平台, 平台职位ID）
+const LEGACY_JOB_PLATFORM_INDEX = "idx_job_platform_job"
+
+// 旧版Boss职位数据表：迁移到统一职位表后重命名保留，之后启动不再重复迁移
+const (
+	LEGACY_BOSS_DATA_TABLE          = "boss_data"
+	LEGACY_BOSS_DATA_MIGRATED_TABLE = "boss_data_migrated"
+	LEGACY_MIGRATE_BATCH_SIZE       = 500
+)
+
+// Migrate 自动迁移全部表结构，并执行一次性的数据迁移
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&model.AccountEntity{},
+		&model.AiEntity{},
+		&model.AiCacheEntity{},
+		&model.AiUsageEntity{},
+		&model.BlacklistEntity{},
+		&model.WhitelistEntity{},
+		&model.BossConfigEntity{},
+		&model.BossIndustryEntity{},
+		&model.BossOptionEntity{},
+		&model.ConfigEntity{},
+		&model.CookieEntity{},
+		&model.FilterRuleEntity{},
+		&model.Job{},
+	); err != nil {
+		return fmt.Errorf("数据库迁移失败: %v", err)
+	}
+
+	// 职位唯一索引加入账号ID后，删除旧的（平台, 平台职位ID）唯一索引，否则不同账号无法保存同一职位。
+	// 仅用于从多账号之前的版本升级，可在多账号支持发布后的下一个版本中删除
+	if db.Migrator().HasIndex(&model.Job{}, LEGACY_JOB_PLATFORM_INDEX) {
+		if err := db.Migrator().DropIndex(&model.Job{}, LEGACY_JOB_PLATFORM_INDEX); err != nil {
+			return fmt.Errorf("删除旧职位索引失败: %v", err)
+		}
+	}
+
+	// 早期缺失的平台职位ID以空字符串保存，改为 NULL 以免与之后的职位冲突
+	if err := db.Model(&model.Job{}).Where("platform_job_id = ?", "").
+		Update("platform_job_id", gorm.Expr("NULL")).Error; err != nil {
+		return fmt.Errorf("数据库迁移失败: %v", err)
+	}
+
+	if err := migrateBossData(db); err != nil {
+		return fmt.Errorf("迁移旧版Boss职位数据失败: %v", err)
+	}
+	return nil
+}
+
+// migrateBossData 将旧版 boss_data 表中的职位复制到统一职位表（归属默认账号，统一职位表中已有的同一职位不覆盖），
+// 复制完成后重命名旧表
+func migrateBossData(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(LEGACY_BOSS_DATA_TABLE) {
+		return nil
+	}
+
+	migrated := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		jobRepo := NewJobRepository(tx)
+		var legacy []*model.BossJobDataEntity
+		return tx.FindInBatches(&legacy, LEGACY_MIGRATE_BATCH_SIZE, func(_ *gorm.DB, _ int) error {
+			for _, entity := range legacy {
+				job := entity.ToJob()
+				if job.PlatformJobId != "" {
+					var count int64
+					if err := tx.Model(&model.Job{}).
+						Where("account_id = ? AND platform = ? AND platform_job_id = ?", job.AccountId, job.Platform, job.PlatformJobId).
+						Count(&count).Error; err != nil {
+						return err
+					}
+					if count > 0 {
+						continue
+					}
+				}
+
+				job.DedupeKey = utils.JobDedupeKey(job.CompanyName, job.JobName, job.City)
+				if job.DeliveryStatus == "" {
+					job.DeliveryStatus = model.DELIVERY_STATUS_PENDING
+				}
+				if err := jobRepo.Save(job); err != nil {
+					return err
+				}
+				migrated++
+			}
+			return nil
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// 备份表已存在（此前迁移过，之后旧版程序又创建了 boss_data）时加上时间后缀
+	backup := LEGACY_BOSS_DATA_MIGRATED_TABLE
+	if migrator.HasTable(backup) {
+		backup = fmt.Sprintf("%s_%s", backup, time.Now().Format("20060102150405"))
+	}
+	if err := migrator.RenameTable(LEGACY_BOSS_DATA_TABLE, backup); err != nil {
+		return err
+	}
+	log.Printf("已将旧版Boss职位数据 %d 条迁移到统一职位表，旧表已重命名为 %s", migrated, backup)
+	return nil
+}
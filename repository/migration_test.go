@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"testing"
+
+	"get_jobs_go/model"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB 打开内存数据库（SQLite 与 MySQL 一样允许唯一索引中出现多个 NULL，但不允许重复的空字符串）
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存数据库随连接关闭而销毁，只使用一个连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := RegisterAccountScope(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMigrateCopiesLegacyBossData(t *testing.T) {
+	db := openTestDB(t)
+
+	// 旧版程序创建的 boss_data 表
+	if err := db.AutoMigrate(&model.BossJobDataEntity{}); err != nil {
+		t.Fatal(err)
+	}
+	legacy := []*model.BossJobDataEntity{
+		{EncryptId: "job-1", EncryptUserId: "hr-1", CompanyName: "字节跳动有限公司", JobName: "Go开发", Location: "北京", HrName: "张三", DeliveryStatus: model.DELIVERY_STATUS_DELIVERED},
+		{EncryptId: "job-2", EncryptUserId: "hr-2", CompanyName: "腾讯", JobName: "后端开发", Location: "深圳", DeliveryStatus: model.DELIVERY_STATUS_PENDING},
+		{CompanyName: "阿里巴巴", JobName: "Java开发", Location: "杭州"},
+	}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 统一职位表中已有 job-2 的最新状态
+	if err := db.AutoMigrate(&model.Job{}); err != nil {
+		t.Fatal(err)
+	}
+	existing := &model.Job{Platform: model.PLATFORM_BOSS, PlatformJobId: "job-2", CompanyName: "腾讯", JobName: "后端开发", DeliveryStatus: model.DELIVERY_STATUS_CONTACTED}
+	if err := db.Create(existing).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	var jobs []*model.Job
+	if err := db.Order("id ASC").Find(&jobs).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 3 {
+		t.Fatalf("职位数 = %d, 期望 3（job-2 不重复迁移）", len(jobs))
+	}
+
+	byCompany := make(map[string]*model.Job)
+	for _, job := range jobs {
+		byCompany[job.CompanyName] = job
+	}
+	migrated := byCompany["字节跳动有限公司"]
+	if migrated == nil || migrated.PlatformJobId != "job-1" || migrated.PlatformUserId != "hr-1" ||
+		migrated.City != "北京" || migrated.Recruiter != "张三" || migrated.AccountId != model.DEFAULT_ACCOUNT_ID ||
+		migrated.DeliveryStatus != model.DELIVERY_STATUS_DELIVERED || migrated.DedupeKey != "字节跳动|go开发|北京" {
+		t.Errorf("迁移的职位 = %+v", migrated)
+	}
+	if kept := byCompany["腾讯"]; kept.DeliveryStatus != model.DELIVERY_STATUS_CONTACTED {
+		t.Errorf("已有职位的状态被覆盖: %s", kept.DeliveryStatus)
+	}
+	if noId := byCompany["阿里巴巴"]; noId == nil || noId.DeliveryStatus != model.DELIVERY_STATUS_PENDING {
+		t.Errorf("缺少职位ID的旧数据 = %+v", noId)
+	}
+
+	migrator := db.Migrator()
+	if migrator.HasTable(LEGACY_BOSS_DATA_TABLE) || !migrator.HasTable(LEGACY_BOSS_DATA_MIGRATED_TABLE) {
+		t.Error("迁移后旧表应重命名为备份表")
+	}
+
+	// 再次启动不重复迁移
+	if err := Migrate(db); err != nil {
+		t.Fatalf("再次 Migrate: %v", err)
+	}
+	var count int64
+	db.Model(&model.Job{}).Count(&count)
+	if count != 3 {
+		t.Errorf("再次迁移后职位数 = %d", count)
+	}
+}
+
+func TestMigrateWithoutLegacyTable(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if db.Migrator().HasTable(LEGACY_BOSS_DATA_TABLE) || db.Migrator().HasTable(LEGACY_BOSS_DATA_MIGRATED_TABLE) {
+		t.Error("不应创建旧版 boss_data 表")
+	}
+}
+
+func TestMigrateDropsLegacyJobIndex(t *testing.T) {
+	db := openTestDB(t)
+	if err := db.AutoMigrate(&model.Job{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("CREATE UNIQUE INDEX " + LEGACY_JOB_PLATFORM_INDEX + " ON job (platform, platform_job_id)").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if db.Migrator().HasIndex(&model.Job{}, LEGACY_JOB_PLATFORM_INDEX) {
+		t.Fatal("迁移后应删除旧的职位唯一索引")
+	}
+
+	// 不同账号可以保存同一职位
+	for _, accountId := range []int64{model.DEFAULT_ACCOUNT_ID, 2} {
+		job := &model.Job{AccountId: accountId, Platform: model.PLATFORM_BOSS, PlatformJobId: "job-1"}
+		if err := NewJobRepository(db).Save(job); err != nil {
+			t.Errorf("账号 %d 保存职位: %v", accountId, err)
+		}
+	}
+}
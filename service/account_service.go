@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"get_jobs_go/model"
+	"get_jobs_go/repository"
+	"strings"
+	"time"
+)
+
+// AccountService 求职者账号服务：维护共享部署中的多个账号（各账号的数据通过 repository.WithAccount 隔离）
+type AccountService struct {
+	accountRepo repository.AccountRepository
+}
+
+func NewAccountService(accountRepo repository.AccountRepository) *AccountService {
+	return &AccountService{
+		accountRepo: accountRepo,
+	}
+}
+
+// GetAllAccounts 获取全部账号（不含默认账号）
+func (s *AccountService) GetAllAccounts() ([]*model.AccountEntity, error) {
+	return s.accountRepo.FindAll()
+}
+
+// GetEnabledAccounts 获取参与投递的账号（不含默认账号）
+func (s *AccountService) GetEnabledAccounts() ([]*model.AccountEntity, error) {
+	return s.accountRepo.FindEnabled()
+}
+
+// GetAccountById 根据ID获取账号
+func (s *AccountService) GetAccountById(id int64) (*model.AccountEntity, error) {
+	return s.accountRepo.FindByID(id)
+}
+
+// GetAccountByName 根据名称获取账号
+func (s *AccountService) GetAccountByName(name string) (*model.AccountEntity, error) {
+	return s.accountRepo.FindByName(strings.TrimSpace(name))
+}
+
+// SaveAccount 校验并保存账号（ID 为 0 时新建）
+func (s *AccountService) SaveAccount(account *model.AccountEntity) error {
+	account.Name = strings.TrimSpace(account.Name)
+	account.Profile = strings.TrimSpace(account.Profile)
+	if account.Name == "" {
+		return fmt.Errorf("账号名称不能为空")
+	}
+	if account.Name == model.DEFAULT_ACCOUNT_NAME {
+		return fmt.Errorf("账号名称 %s 已被默认账号使用", model.DEFAULT_ACCOUNT_NAME)
+	}
+	if strings.ContainsAny(account.Profile, `/\`) || account.Profile == "." || account.Profile == ".." {
+		return fmt.Errorf("浏览器配置目录名无效: %s", account.Profile)
+	}
+
+	existing, err := s.accountRepo.FindByName(account.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != account.ID {
+		return fmt.Errorf("账号名称已存在: %s", account.Name)
+	}
+
+	account.UpdatedAt = time.Now()
+	if account.ID == 0 {
+		account.CreatedAt = account.UpdatedAt
+		return s.accountRepo.Save(account)
+	}
+	return s.accountRepo.Update(account)
+}
+
+// SetAccountEnabled 设置账号是否参与投递
+func (s *AccountService) SetAccountEnabled(id int64, enabled bool) error {
+	account, err := s.accountRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return fmt.Errorf("账号不存在: %d", id)
+	}
+	account.Enabled = 0
+	if enabled {
+		account.Enabled = 1
+	}
+	account.UpdatedAt = time.Now()
+	return s.accountRepo.Update(account)
+}
+
+// DeleteAccount 删除账号（该账号的Cookie、配置与投递记录保留在数据库中）
+func (s *AccountService) DeleteAccount(id int64) error {
+	return s.accountRepo.Delete(id)
+}
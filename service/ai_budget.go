@@ -0,0 +1,161 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"get_jobs_go/model"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrAiBudgetExhausted AI预算已用尽
+var ErrAiBudgetExhausted = errors.New("AI预算已用尽")
+
+// AiModelPrice 模型单价（每百万Token）
+type AiModelPrice struct {
+	Input  float64 `json:"input"`  // 输入Token单价
+	Output float64 `json:"output"` // 输出Token单价
+}
+
+// BeginRun 开始一次新的运行：记录运行ID用于用量统计与单次预算，运行ID变化时重置熔断器
+func (s *AiService) BeginRun(runId string) {
+	s.runMu.Lock()
+	changed := s.runId != runId
+	s.runId = runId
+	s.runMu.Unlock()
+	if changed {
+		s.breaker.Reset()
+	}
+}
+
+// currentRunId 当前运行ID
+func (s *AiService) currentRunId() string {
+	s.runMu.RLock()
+	defer s.runMu.RUnlock()
+	return s.runId
+}
+
+// GetRunCost 获取指定运行的AI费用
+func (s *AiService) GetRunCost(runId string) (float64, error) {
+	return s.aiUsageRepo.SumCostByRunId(runId)
+}
+
+// hasBudget 是否配置了每日预算或本次运行的单次预算
+func (s *AiService) hasBudget(runId string) bool {
+	return s.floatConfig("AI_DAILY_BUDGET") > 0 || (runId != "" && s.floatConfig("AI_RUN_BUDGET") > 0)
+}
+
+// checkBudget 检查每日预算（AI_DAILY_BUDGET）与单次运行预算（AI_RUN_BUDGET），未配置或小于等于0表示不限制
+func (s *AiService) checkBudget(runId string) error {
+	if dailyBudget := s.floatConfig("AI_DAILY_BUDGET"); dailyBudget > 0 {
+		now := time.Now()
+		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		spent, err := s.aiUsageRepo.SumCostSince(startOfDay)
+		if err != nil {
+			log.Printf("统计今日AI费用失败: %v", err)
+		} else if spent >= dailyBudget {
+			log.Printf("今日AI费用 %.4f 已达到每日预算 %.4f", spent, dailyBudget)
+			return ErrAiBudgetExhausted
+		}
+	}
+
+	if runBudget := s.floatConfig("AI_RUN_BUDGET"); runBudget > 0 && runId != "" {
+		spent, err := s.aiUsageRepo.SumCostByRunId(runId)
+		if err != nil {
+			log.Printf("统计本次运行AI费用失败: %v", err)
+		} else if spent >= runBudget {
+			log.Printf("本次运行AI费用 %.4f 已达到单次预算 %.4f", spent, runBudget)
+			return ErrAiBudgetExhausted
+		}
+	}
+	return nil
+}
+
+// recordUsage 记录一次AI调用的用量
+func (s *AiService) recordUsage(runId, purpose, provider, modelName string, completion *AiCompletion, latency time.Duration, callErr error) {
+	usage := &model.AiUsageEntity{
+		RunId:     runId,
+		Purpose:   purpose,
+		Provider:  provider,
+		Model:     modelName,
+		LatencyMs: latency.Milliseconds(),
+		CreatedAt: time.Now(),
+	}
+	if callErr == nil && completion != nil {
+		usage.Success = 1
+		usage.PromptTokens = completion.Usage.PromptTokens
+		usage.CompletionTokens = completion.Usage.CompletionTokens
+		usage.TotalTokens = completion.Usage.TotalTokens
+		if completion.Cached {
+			usage.Cached = 1
+		} else {
+			usage.Cost = s.estimateCost(modelName, completion)
+		}
+	}
+
+	if err := s.aiUsageRepo.Save(usage); err != nil {
+		log.Printf("记录AI用量失败: %v", err)
+	}
+}
+
+// estimateCost 按价格表（配置 AI_PRICE_TABLE）估算费用
+func (s *AiService) estimateCost(modelName string, completion *AiCompletion) float64 {
+	price, ok := lookupModelPrice(s.loadPriceTable(), modelName)
+	if !ok && completion.Model != "" {
+		price, ok = lookupModelPrice(s.loadPriceTable(), completion.Model)
+	}
+	if !ok {
+		return 0
+	}
+	return (float64(completion.Usage.PromptTokens)*price.Input +
+		float64(completion.Usage.CompletionTokens)*price.Output) / 1_000_000
+}
+
+// loadPriceTable 读取价格表，格式：{"gpt-4o-mini": {"input": 0.15, "output": 0.6}}
+func (s *AiService) loadPriceTable() map[string]AiModelPrice {
+	raw, err := s.configService.GetConfigValue("AI_PRICE_TABLE")
+	if err != nil || strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var table map[string]AiModelPrice
+	if err := json.Unmarshal([]byte(raw), &table); err != nil {
+		log.Printf("AI_PRICE_TABLE 配置无效: %v", err)
+		return nil
+	}
+	return table
+}
+
+// lookupModelPrice 查找模型单价：优先精确匹配，否则取最长的前缀匹配（如 gpt-4o-mini 匹配 gpt-4o-mini-2024-07-18）
+func lookupModelPrice(table map[string]AiModelPrice, modelName string) (AiModelPrice, bool) {
+	modelName = strings.ToLower(strings.TrimSpace(modelName))
+	var best string
+	for name := range table {
+		key := strings.ToLower(name)
+		if key == modelName {
+			return table[name], true
+		}
+		if strings.HasPrefix(modelName, key) && len(key) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return AiModelPrice{}, false
+	}
+	return table[best], true
+}
+
+// floatConfig 读取数值配置，缺失或无效时返回0
+func (s *AiService) floatConfig(configKey string) float64 {
+	raw, err := s.configService.GetConfigValue(configKey)
+	if err != nil || strings.TrimSpace(raw) == "" {
+		return 0
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		log.Printf("%s 配置无效: %s", configKey, raw)
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"get_jobs_go/model"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AI调用用途
+const (
+	AI_PURPOSE_GREETING   = "greeting"      // 生成打招呼语
+	AI_PURPOSE_JOB_SCORE  = "job_fit_score" // 岗位匹配度评分
+	AI_PURPOSE_JD_EXTRACT = "jd_extract"    // 提取职位描述结构化属性
+)
+
+// DEFAULT_AI_CACHE_TTL_HOURS AI响应缓存默认有效期（小时），可通过配置 AI_CACHE_TTL_HOURS 调整，小于等于0表示不缓存
+const DEFAULT_AI_CACHE_TTL_HOURS = 168
+
+// AiRequestOptions AI请求选项
+type AiRequestOptions struct {
+	Purpose     string // 调用用途，记录在缓存中便于排查
+	BypassCache bool   // 跳过缓存读取，强制重新生成（结果仍会写入缓存）
+}
+
+// AiCacheStats AI响应缓存命中统计
+type AiCacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// GetCacheStats 获取AI响应缓存命中统计（自服务启动以来）
+func (s *AiService) GetCacheStats() AiCacheStats {
+	return AiCacheStats{
+		Hits:   s.cacheHits.Load(),
+		Misses: s.cacheMisses.Load(),
+	}
+}
+
+// PurgeExpiredCache 清理已过期的AI响应缓存
+func (s *AiService) PurgeExpiredCache() (int64, error) {
+	return s.aiCacheRepo.DeleteExpired(time.Now())
+}
+
+// buildCacheKey 构建缓存键：sha256(提供方, 模型, 提示词)
+func buildCacheKey(provider, model, prompt string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{provider, model, prompt}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheTTL 读取AI响应缓存有效期
+func (s *AiService) cacheTTL() time.Duration {
+	raw, err := s.configService.GetConfigValue("AI_CACHE_TTL_HOURS")
+	if err != nil || strings.TrimSpace(raw) == "" {
+		return DEFAULT_AI_CACHE_TTL_HOURS * time.Hour
+	}
+	hours, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		log.Printf("AI_CACHE_TTL_HOURS 配置无效(%s)，使用默认值 %d 小时", raw, DEFAULT_AI_CACHE_TTL_HOURS)
+		return DEFAULT_AI_CACHE_TTL_HOURS * time.Hour
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// loadCache 查找未过期的缓存，未命中时返回 nil
+func (s *AiService) loadCache(cacheKey string) *model.AiCacheEntity {
+	cache, err := s.aiCacheRepo.FindByCacheKey(cacheKey)
+	if err != nil {
+		log.Printf("读取AI缓存失败: %v", err)
+		return nil
+	}
+	if cache == nil || time.Now().After(cache.ExpiresAt) {
+		return nil
+	}
+	if err := s.aiCacheRepo.IncrementHitCount(cache.ID); err != nil {
+		log.Printf("更新AI缓存命中次数失败: %v", err)
+	}
+	return cache
+}
+
+// storeCache 写入或刷新缓存
+func (s *AiService) storeCache(cacheKey, provider, modelName, prompt, purpose string, completion *AiCompletion, ttl time.Duration) {
+	cache, err := s.aiCacheRepo.FindByCacheKey(cacheKey)
+	if err != nil {
+		log.Printf("读取AI缓存失败: %v", err)
+		return
+	}
+
+	now := time.Now()
+	if cache == nil {
+		cache = &model.AiCacheEntity{CacheKey: cacheKey, CreatedAt: now}
+	}
+	cache.Provider = provider
+	cache.Model = modelName
+	cache.Purpose = purpose
+	cache.Prompt = prompt
+	cache.Response = completion.Content
+	cache.PromptTokens = completion.Usage.PromptTokens
+	cache.CompletionTokens = completion.Usage.CompletionTokens
+	cache.TotalTokens = completion.Usage.TotalTokens
+	cache.ExpiresAt = now.Add(ttl)
+	cache.UpdatedAt = now
+
+	if err := s.aiCacheRepo.Save(cache); err != nil {
+		log.Printf("写入AI缓存失败: %v", err)
+	}
+}
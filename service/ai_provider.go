@@ -0,0 +1,470 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AI服务提供方
+const (
+	AI_PROVIDER_OPENAI_CHAT      = "openai_chat"      // OpenAI Chat Completions API
+	AI_PROVIDER_OPENAI_RESPONSES = "openai_responses" // OpenAI Responses API
+	AI_PROVIDER_ANTHROPIC        = "anthropic"        // Anthropic Messages API
+	AI_PROVIDER_OLLAMA           = "ollama"           // Ollama 等兼容 OpenAI 接口的本地服务
+	AI_PROVIDER_AZURE_OPENAI     = "azure_openai"     // Azure OpenAI
+)
+
+// AI_PROVIDERS 支持的全部服务提供方（PROVIDER 配置的可选值）
+var AI_PROVIDERS = []string{
+	AI_PROVIDER_OPENAI_CHAT,
+	AI_PROVIDER_OPENAI_RESPONSES,
+	AI_PROVIDER_ANTHROPIC,
+	AI_PROVIDER_OLLAMA,
+	AI_PROVIDER_AZURE_OPENAI,
+}
+
+const (
+	DEFAULT_AI_TEMPERATURE           = 0.5
+	DEFAULT_ANTHROPIC_VERSION        = "2023-06-01"
+	DEFAULT_ANTHROPIC_MAX_TOKENS     = 1024
+	DEFAULT_AZURE_OPENAI_API_VERSION = "2024-06-01"
+)
+
+// AiProviderConfig AI服务提供方配置
+type AiProviderConfig struct {
+	Provider   string // 提供方（openai_chat/openai_responses/anthropic/ollama/azure_openai）
+	BaseUrl    string // 接口地址
+	ApiKey     string // 密钥（ollama 可为空）
+	Model      string // 模型名称（Azure 为部署名称）
+	ApiVersion string // 接口版本（Azure 使用）
+}
+
+// AiUsage AI调用的Token用量
+type AiUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// AiCompletion AI调用结果
+type AiCompletion struct {
+	ID      string
+	Model   string
+	Content string
+	Usage   AiUsage
+	Cached  bool // 是否来自缓存（缓存命中时不产生Token用量）
+}
+
+// AiProvider AI服务提供方接口
+type AiProvider interface {
+	// Name 提供方名称
+	Name() string
+	// Complete 发送单轮对话请求并返回回复
+	Complete(prompt string) (*AiCompletion, error)
+}
+
+// NewAiProvider 根据配置创建AI服务提供方
+func NewAiProvider(cfg AiProviderConfig, httpClient *http.Client) (AiProvider, error) {
+	provider := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	if err := ValidateAiProvider(provider); err != nil {
+		return nil, err
+	}
+	cfg.Provider = provider
+	cfg.BaseUrl = normalizeBaseUrl(cfg.BaseUrl)
+
+	if cfg.BaseUrl == "" || cfg.Model == "" {
+		return nil, fmt.Errorf("AI配置不完整: PROVIDER=%s, BASE_URL=%s, MODEL=%s", provider, cfg.BaseUrl, cfg.Model)
+	}
+	if cfg.ApiKey == "" && provider != AI_PROVIDER_OLLAMA {
+		return nil, fmt.Errorf("AI配置不完整: PROVIDER=%s 需要配置 API_KEY", provider)
+	}
+
+	switch provider {
+	case AI_PROVIDER_OPENAI_CHAT:
+		return &openAIChatProvider{cfg: cfg, httpClient: httpClient}, nil
+	case AI_PROVIDER_OPENAI_RESPONSES:
+		return &openAIResponsesProvider{cfg: cfg, httpClient: httpClient}, nil
+	case AI_PROVIDER_ANTHROPIC:
+		return &anthropicProvider{cfg: cfg, httpClient: httpClient}, nil
+	case AI_PROVIDER_OLLAMA:
+		return &ollamaProvider{cfg: cfg, httpClient: httpClient}, nil
+	case AI_PROVIDER_AZURE_OPENAI:
+		if cfg.ApiVersion == "" {
+			cfg.ApiVersion = DEFAULT_AZURE_OPENAI_API_VERSION
+		}
+		return &azureOpenAIProvider{cfg: cfg, httpClient: httpClient}, nil
+	default:
+		return nil, fmt.Errorf("不支持的AI服务提供方: %s", cfg.Provider)
+	}
+}
+
+// ValidateAiProvider 校验服务提供方名称，未配置或不支持时返回列出可选值的配置错误
+func ValidateAiProvider(provider string) error {
+	if provider == "" {
+		return &ConfigRequiredError{ConfigKey: "PROVIDER", Options: AI_PROVIDERS}
+	}
+	for _, name := range AI_PROVIDERS {
+		if provider == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的AI服务提供方: %s（可选值: %s）", provider, strings.Join(AI_PROVIDERS, ", "))
+}
+
+// ================= OpenAI Chat Completions =================
+
+type chatCompletionRequest struct {
+	Model       string        `json:"model,omitempty"`
+	Temperature float64       `json:"temperature,omitempty"`
+	Messages    []chatMessage `json:"messages"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// openAIChatProvider OpenAI Chat Completions API
+type openAIChatProvider struct {
+	cfg        AiProviderConfig
+	httpClient *http.Client
+}
+
+func (p *openAIChatProvider) Name() string {
+	return AI_PROVIDER_OPENAI_CHAT
+}
+
+func (p *openAIChatProvider) Complete(prompt string) (*AiCompletion, error) {
+	completion, body, err := sendChatCompletion(p.httpClient, withV1(p.cfg.BaseUrl, "/chat/completions"),
+		bearerHeaders(p.cfg.ApiKey), newChatCompletionRequest(p.cfg.Model, prompt))
+	if err != nil && containsReasoningParamError(body) {
+		// 推理模型不支持 Chat Completions 的部分参数，自动切换到 Responses API 重试
+		log.Printf("检测到 reasoning 相关参数错误，自动切换到 Responses API 重试")
+		fallback := &openAIResponsesProvider{cfg: p.cfg, httpClient: p.httpClient}
+		return fallback.Complete(prompt)
+	}
+	return completion, err
+}
+
+// ================= OpenAI Responses =================
+
+type responsesRequest struct {
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature,omitempty"`
+	Input       string  `json:"input"`
+}
+
+type responsesResponse struct {
+	ID         string `json:"id"`
+	Model      string `json:"model"`
+	OutputText string `json:"output_text"`
+	Output     []struct {
+		Type    string `json:"type"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"output"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// openAIResponsesProvider OpenAI Responses API
+type openAIResponsesProvider struct {
+	cfg        AiProviderConfig
+	httpClient *http.Client
+}
+
+func (p *openAIResponsesProvider) Name() string {
+	return AI_PROVIDER_OPENAI_RESPONSES
+}
+
+func (p *openAIResponsesProvider) Complete(prompt string) (*AiCompletion, error) {
+	requestData := responsesRequest{
+		Model:       p.cfg.Model,
+		Temperature: DEFAULT_AI_TEMPERATURE,
+		Input:       prompt,
+	}
+
+	body, err := postJSON(p.httpClient, withV1(p.cfg.BaseUrl, "/responses"), bearerHeaders(p.cfg.ApiKey), requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseObj responsesResponse
+	if err := json.Unmarshal(body, &responseObj); err != nil {
+		return nil, fmt.Errorf("解析响应JSON失败: %v", err)
+	}
+
+	content := responseObj.OutputText
+	if content == "" {
+		var parts []string
+		for _, output := range responseObj.Output {
+			for _, item := range output.Content {
+				if item.Text != "" {
+					parts = append(parts, item.Text)
+				}
+			}
+		}
+		content = strings.Join(parts, "")
+	}
+	if content == "" {
+		return nil, fmt.Errorf("响应中没有输出内容")
+	}
+
+	return &AiCompletion{
+		ID:      responseObj.ID,
+		Model:   responseObj.Model,
+		Content: content,
+		Usage: AiUsage{
+			PromptTokens:     responseObj.Usage.InputTokens,
+			CompletionTokens: responseObj.Usage.OutputTokens,
+			TotalTokens:      responseObj.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// ================= Anthropic Messages =================
+
+type anthropicRequest struct {
+	Model       string        `json:"model"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature float64       `json:"temperature,omitempty"`
+	Messages    []chatMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// anthropicProvider Anthropic Messages API
+type anthropicProvider struct {
+	cfg        AiProviderConfig
+	httpClient *http.Client
+}
+
+func (p *anthropicProvider) Name() string {
+	return AI_PROVIDER_ANTHROPIC
+}
+
+func (p *anthropicProvider) Complete(prompt string) (*AiCompletion, error) {
+	requestData := anthropicRequest{
+		Model:       p.cfg.Model,
+		MaxTokens:   DEFAULT_ANTHROPIC_MAX_TOKENS,
+		Temperature: DEFAULT_AI_TEMPERATURE,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+	}
+	headers := map[string]string{
+		"x-api-key":         p.cfg.ApiKey,
+		"anthropic-version": DEFAULT_ANTHROPIC_VERSION,
+	}
+
+	body, err := postJSON(p.httpClient, withV1(p.cfg.BaseUrl, "/messages"), headers, requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseObj anthropicResponse
+	if err := json.Unmarshal(body, &responseObj); err != nil {
+		return nil, fmt.Errorf("解析响应JSON失败: %v", err)
+	}
+
+	var parts []string
+	for _, item := range responseObj.Content {
+		if item.Type == "text" {
+			parts = append(parts, item.Text)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("响应中没有文本内容")
+	}
+
+	return &AiCompletion{
+		ID:      responseObj.ID,
+		Model:   responseObj.Model,
+		Content: strings.Join(parts, ""),
+		Usage: AiUsage{
+			PromptTokens:     responseObj.Usage.InputTokens,
+			CompletionTokens: responseObj.Usage.OutputTokens,
+			TotalTokens:      responseObj.Usage.InputTokens + responseObj.Usage.OutputTokens,
+		},
+	}, nil
+}
+
+// ================= Ollama（兼容 OpenAI 接口的本地服务） =================
+
+// ollamaProvider 使用 OpenAI 兼容的 /v1/chat/completions 接口，API_KEY 可为空
+type ollamaProvider struct {
+	cfg        AiProviderConfig
+	httpClient *http.Client
+}
+
+func (p *ollamaProvider) Name() string {
+	return AI_PROVIDER_OLLAMA
+}
+
+func (p *ollamaProvider) Complete(prompt string) (*AiCompletion, error) {
+	headers := map[string]string{}
+	if p.cfg.ApiKey != "" {
+		headers = bearerHeaders(p.cfg.ApiKey)
+	}
+	completion, _, err := sendChatCompletion(p.httpClient, withV1(p.cfg.BaseUrl, "/chat/completions"),
+		headers, newChatCompletionRequest(p.cfg.Model, prompt))
+	return completion, err
+}
+
+// ================= Azure OpenAI =================
+
+// azureOpenAIProvider Azure OpenAI，MODEL 配置为部署名称
+type azureOpenAIProvider struct {
+	cfg        AiProviderConfig
+	httpClient *http.Client
+}
+
+func (p *azureOpenAIProvider) Name() string {
+	return AI_PROVIDER_AZURE_OPENAI
+}
+
+func (p *azureOpenAIProvider) Complete(prompt string) (*AiCompletion, error) {
+	endpoint := fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		p.cfg.BaseUrl, url.PathEscape(p.cfg.Model), url.QueryEscape(p.cfg.ApiVersion))
+
+	// Azure 通过部署名称确定模型，请求体中无需 model 字段
+	requestData := newChatCompletionRequest("", prompt)
+	completion, _, err := sendChatCompletion(p.httpClient, endpoint, map[string]string{"api-key": p.cfg.ApiKey}, requestData)
+	return completion, err
+}
+
+// ================= 工具方法 =================
+
+// newChatCompletionRequest 构建 Chat Completions 请求体
+func newChatCompletionRequest(model, prompt string) chatCompletionRequest {
+	return chatCompletionRequest{
+		Model:       model,
+		Temperature: DEFAULT_AI_TEMPERATURE,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+	}
+}
+
+// sendChatCompletion 发送 Chat Completions 请求，失败时一并返回响应体便于判断错误类型
+func sendChatCompletion(httpClient *http.Client, endpoint string, headers map[string]string, requestData chatCompletionRequest) (*AiCompletion, string, error) {
+	body, err := postJSON(httpClient, endpoint, headers, requestData)
+	if err != nil {
+		return nil, string(body), err
+	}
+
+	var responseObj chatCompletionResponse
+	if err := json.Unmarshal(body, &responseObj); err != nil {
+		return nil, string(body), fmt.Errorf("解析响应JSON失败: %v", err)
+	}
+	if len(responseObj.Choices) == 0 {
+		return nil, string(body), fmt.Errorf("响应中没有choices字段")
+	}
+
+	return &AiCompletion{
+		ID:      responseObj.ID,
+		Model:   responseObj.Model,
+		Content: responseObj.Choices[0].Message.Content,
+		Usage: AiUsage{
+			PromptTokens:     responseObj.Usage.PromptTokens,
+			CompletionTokens: responseObj.Usage.CompletionTokens,
+			TotalTokens:      responseObj.Usage.TotalTokens,
+		},
+	}, "", nil
+}
+
+// postJSON 发送JSON POST请求，非200状态码返回 *AiHTTPError（同时返回响应体）
+func postJSON(httpClient *http.Client, endpoint string, headers map[string]string, requestData interface{}) ([]byte, error) {
+	jsonData, err := json.Marshal(requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("AI请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("AI请求失败: endpoint=%s, status=%d, body=%s", endpoint, resp.StatusCode, string(body))
+		return body, &AiHTTPError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header, time.Now()),
+		}
+	}
+	return body, nil
+}
+
+// bearerHeaders 构建 Bearer 认证请求头
+func bearerHeaders(apiKey string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + apiKey}
+}
+
+// normalizeBaseUrl 去除接口地址首尾空白及末尾斜杠
+func normalizeBaseUrl(baseUrl string) string {
+	return strings.TrimRight(strings.TrimSpace(baseUrl), "/")
+}
+
+// withV1 拼接接口路径，地址中未包含 /v1 时自动补全
+func withV1(baseUrl, path string) string {
+	if strings.Contains(baseUrl, "/v1") {
+		return baseUrl + path
+	}
+	return baseUrl + "/v1" + path
+}
+
+// containsReasoningParamError 判断是否为推理模型不支持的参数错误
+func containsReasoningParamError(body string) bool {
+	bodyLower := strings.ToLower(body)
+	return (strings.Contains(bodyLower, "reasoning") && strings.Contains(bodyLower, "unsupported_value")) ||
+		strings.Contains(bodyLower, "reasoning.summary")
+}
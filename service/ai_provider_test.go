@@ -0,0 +1,378 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// capturedRequest 模拟服务收到的请求
+type capturedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// newStandIn 启动按路径返回固定响应的模拟服务，记录收到的请求
+func newStandIn(t *testing.T, responses map[string]func(w http.ResponseWriter)) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var requests []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("请求体不是JSON: %s", raw)
+		}
+		requests = append(requests, capturedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+
+		respond, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respond(w)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// jsonResponse 返回固定状态码与响应体
+func jsonResponse(status int, body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}
+}
+
+const chatCompletionBody = `{"id":"chatcmpl-1","model":"gpt-test","choices":[{"message":{"role":"assistant","content":"你好"}}],"usage":{"prompt_tokens":11,"completion_tokens":7,"total_tokens":18}}`
+
+// firstMessage 取出请求体中 messages 的第一条消息
+func firstMessage(t *testing.T, body map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	messages, ok := body["messages"].([]interface{})
+	if !ok || len(messages) != 1 {
+		t.Fatalf("messages = %v, 期望只有一条消息", body["messages"])
+	}
+	message, _ := messages[0].(map[string]interface{})
+	return message
+}
+
+func TestAiProviders(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    string
+		apiKey      string
+		apiVersion  string
+		path        string
+		response    string
+		checkHeader func(t *testing.T, header http.Header)
+		checkBody   func(t *testing.T, request capturedRequest)
+		wantContent string
+		wantUsage   AiUsage
+	}{
+		{
+			name:     "openai_chat",
+			provider: AI_PROVIDER_OPENAI_CHAT,
+			apiKey:   "sk-chat",
+			path:     "/v1/chat/completions",
+			response: chatCompletionBody,
+			checkHeader: func(t *testing.T, header http.Header) {
+				if got := header.Get("Authorization"); got != "Bearer sk-chat" {
+					t.Errorf("Authorization = %q", got)
+				}
+			},
+			checkBody: func(t *testing.T, request capturedRequest) {
+				if request.Body["model"] != "gpt-test" {
+					t.Errorf("model = %v", request.Body["model"])
+				}
+				message := firstMessage(t, request.Body)
+				if message["role"] != "user" || message["content"] != "写一句问候" {
+					t.Errorf("message = %v", message)
+				}
+			},
+			wantContent: "你好",
+			wantUsage:   AiUsage{PromptTokens: 11, CompletionTokens: 7, TotalTokens: 18},
+		},
+		{
+			name:     "openai_responses",
+			provider: AI_PROVIDER_OPENAI_RESPONSES,
+			apiKey:   "sk-resp",
+			path:     "/v1/responses",
+			response: `{"id":"resp-1","model":"gpt-test","output":[{"type":"message","content":[{"type":"output_text","text":"你"},{"type":"output_text","text":"好"}]}],"usage":{"input_tokens":5,"output_tokens":3,"total_tokens":8}}`,
+			checkHeader: func(t *testing.T, header http.Header) {
+				if got := header.Get("Authorization"); got != "Bearer sk-resp" {
+					t.Errorf("Authorization = %q", got)
+				}
+			},
+			checkBody: func(t *testing.T, request capturedRequest) {
+				if request.Body["model"] != "gpt-test" || request.Body["input"] != "写一句问候" {
+					t.Errorf("body = %v", request.Body)
+				}
+				if _, ok := request.Body["messages"]; ok {
+					t.Errorf("Responses API 请求体不应包含 messages")
+				}
+			},
+			wantContent: "你好",
+			wantUsage:   AiUsage{PromptTokens: 5, CompletionTokens: 3, TotalTokens: 8},
+		},
+		{
+			name:     "anthropic",
+			provider: AI_PROVIDER_ANTHROPIC,
+			apiKey:   "sk-ant",
+			path:     "/v1/messages",
+			response: `{"id":"msg-1","model":"claude-test","content":[{"type":"text","text":"你好"},{"type":"tool_use","text":"忽略"}],"usage":{"input_tokens":9,"output_tokens":4}}`,
+			checkHeader: func(t *testing.T, header http.Header) {
+				if got := header.Get("x-api-key"); got != "sk-ant" {
+					t.Errorf("x-api-key = %q", got)
+				}
+				if got := header.Get("anthropic-version"); got != DEFAULT_ANTHROPIC_VERSION {
+					t.Errorf("anthropic-version = %q", got)
+				}
+				if got := header.Get("Authorization"); got != "" {
+					t.Errorf("Anthropic 不应发送 Authorization: %q", got)
+				}
+			},
+			checkBody: func(t *testing.T, request capturedRequest) {
+				if request.Body["max_tokens"] != float64(DEFAULT_ANTHROPIC_MAX_TOKENS) {
+					t.Errorf("max_tokens = %v", request.Body["max_tokens"])
+				}
+				if message := firstMessage(t, request.Body); message["content"] != "写一句问候" {
+					t.Errorf("message = %v", message)
+				}
+			},
+			wantContent: "你好",
+			wantUsage:   AiUsage{PromptTokens: 9, CompletionTokens: 4, TotalTokens: 13},
+		},
+		{
+			name:     "ollama 无密钥",
+			provider: AI_PROVIDER_OLLAMA,
+			path:     "/v1/chat/completions",
+			response: chatCompletionBody,
+			checkHeader: func(t *testing.T, header http.Header) {
+				if got := header.Get("Authorization"); got != "" {
+					t.Errorf("未配置 API_KEY 时不应发送 Authorization: %q", got)
+				}
+			},
+			checkBody: func(t *testing.T, request capturedRequest) {
+				if request.Body["model"] != "gpt-test" {
+					t.Errorf("model = %v", request.Body["model"])
+				}
+			},
+			wantContent: "你好",
+			wantUsage:   AiUsage{PromptTokens: 11, CompletionTokens: 7, TotalTokens: 18},
+		},
+		{
+			name:       "azure_openai",
+			provider:   AI_PROVIDER_AZURE_OPENAI,
+			apiKey:     "az-key",
+			apiVersion: "2024-10-21",
+			path:       "/openai/deployments/gpt-test/chat/completions",
+			response:   chatCompletionBody,
+			checkHeader: func(t *testing.T, header http.Header) {
+				if got := header.Get("api-key"); got != "az-key" {
+					t.Errorf("api-key = %q", got)
+				}
+				if got := header.Get("Authorization"); got != "" {
+					t.Errorf("Azure 不应发送 Authorization: %q", got)
+				}
+			},
+			checkBody: func(t *testing.T, request capturedRequest) {
+				if request.Query != "api-version=2024-10-21" {
+					t.Errorf("query = %q", request.Query)
+				}
+				if _, ok := request.Body["model"]; ok {
+					t.Errorf("Azure 请求体不应包含 model: %v", request.Body)
+				}
+			},
+			wantContent: "你好",
+			wantUsage:   AiUsage{PromptTokens: 11, CompletionTokens: 7, TotalTokens: 18},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newStandIn(t, map[string]func(w http.ResponseWriter){
+				tt.path: jsonResponse(http.StatusOK, tt.response),
+			})
+
+			provider, err := NewAiProvider(AiProviderConfig{
+				Provider:   tt.provider,
+				BaseUrl:    server.URL + "/",
+				ApiKey:     tt.apiKey,
+				Model:      "gpt-test",
+				ApiVersion: tt.apiVersion,
+			}, server.Client())
+			if err != nil {
+				t.Fatalf("NewAiProvider: %v", err)
+			}
+			if provider.Name() != tt.provider {
+				t.Errorf("Name() = %q", provider.Name())
+			}
+
+			completion, err := provider.Complete("写一句问候")
+			if err != nil {
+				t.Fatalf("Complete: %v", err)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("请求次数 = %d", len(*requests))
+			}
+			request := (*requests)[0]
+			if request.Method != http.MethodPost || request.Path != tt.path {
+				t.Errorf("请求 = %s %s, 期望 POST %s", request.Method, request.Path, tt.path)
+			}
+			if got := request.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			tt.checkHeader(t, request.Header)
+			tt.checkBody(t, request)
+
+			if completion.Content != tt.wantContent {
+				t.Errorf("Content = %q, 期望 %q", completion.Content, tt.wantContent)
+			}
+			if completion.Usage != tt.wantUsage {
+				t.Errorf("Usage = %+v, 期望 %+v", completion.Usage, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestOpenAIChatFallsBackToResponsesOnReasoningParamError(t *testing.T) {
+	server, requests := newStandIn(t, map[string]func(w http.ResponseWriter){
+		"/v1/chat/completions": jsonResponse(http.StatusBadRequest,
+			`{"error":{"message":"Unsupported value: 'reasoning.effort' is not supported with this model.","type":"invalid_request_error","code":"unsupported_value"}}`),
+		"/v1/responses": jsonResponse(http.StatusOK,
+			`{"id":"resp-2","model":"o-test","output_text":"回退成功","usage":{"input_tokens":4,"output_tokens":2,"total_tokens":6}}`),
+	})
+
+	provider, err := NewAiProvider(AiProviderConfig{
+		Provider: AI_PROVIDER_OPENAI_CHAT,
+		BaseUrl:  server.URL + "/v1",
+		ApiKey:   "sk-chat",
+		Model:    "o-test",
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("NewAiProvider: %v", err)
+	}
+
+	completion, err := provider.Complete("写一句问候")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if completion.Content != "回退成功" || completion.Usage.TotalTokens != 6 {
+		t.Errorf("completion = %+v", completion)
+	}
+
+	var paths []string
+	for _, request := range *requests {
+		paths = append(paths, request.Path)
+		if got := request.Header.Get("Authorization"); got != "Bearer sk-chat" {
+			t.Errorf("%s Authorization = %q", request.Path, got)
+		}
+	}
+	if strings.Join(paths, ",") != "/v1/chat/completions,/v1/responses" {
+		t.Errorf("请求顺序 = %v", paths)
+	}
+}
+
+func TestOpenAIChatDoesNotFallBackOnOtherErrors(t *testing.T) {
+	server, requests := newStandIn(t, map[string]func(w http.ResponseWriter){
+		"/v1/chat/completions": jsonResponse(http.StatusBadRequest, `{"error":{"message":"invalid model"}}`),
+	})
+
+	provider, err := NewAiProvider(AiProviderConfig{Provider: AI_PROVIDER_OPENAI_CHAT, BaseUrl: server.URL, ApiKey: "sk", Model: "gpt-test"}, server.Client())
+	if err != nil {
+		t.Fatalf("NewAiProvider: %v", err)
+	}
+
+	_, err = provider.Complete("写一句问候")
+	httpErr, ok := err.(*AiHTTPError)
+	if !ok || httpErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("err = %v, 期望 400 的 *AiHTTPError", err)
+	}
+	if len(*requests) != 1 {
+		t.Errorf("请求次数 = %d, 非 reasoning 错误不应回退", len(*requests))
+	}
+}
+
+func TestNewAiProviderValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  AiProviderConfig
+	}{
+		{"缺少地址", AiProviderConfig{Provider: AI_PROVIDER_OPENAI_CHAT, ApiKey: "sk", Model: "m"}},
+		{"缺少模型", AiProviderConfig{Provider: AI_PROVIDER_OPENAI_CHAT, BaseUrl: "http://localhost", ApiKey: "sk"}},
+		{"未配置提供方", AiProviderConfig{BaseUrl: "http://localhost", ApiKey: "sk", Model: "m"}},
+		{"缺少密钥", AiProviderConfig{Provider: AI_PROVIDER_ANTHROPIC, BaseUrl: "http://localhost", Model: "m"}},
+		{"未知提供方", AiProviderConfig{Provider: "unknown", BaseUrl: "http://localhost", ApiKey: "sk", Model: "m"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAiProvider(tt.cfg, http.DefaultClient); err == nil {
+				t.Errorf("期望返回配置错误")
+			}
+		})
+	}
+}
+
+func TestGetAiConfigsRequiresProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		required bool // 期望返回 *ConfigRequiredError
+	}{
+		{"未配置", "", true},
+		{"空白", "  ", true},
+		{"不支持", "openai", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configService := ConfigService{configRepo: &memoryConfigRepository{values: map[string]string{
+				"PROVIDER": tt.provider,
+				"BASE_URL": "http://localhost",
+				"API_KEY":  "sk",
+				"MODEL":    "m",
+			}}}
+			_, err := configService.GetAiConfigs()
+			if err == nil {
+				t.Fatal("期望返回配置错误")
+			}
+			var required *ConfigRequiredError
+			if errors.As(err, &required) != tt.required {
+				t.Errorf("err = %T, 期望 *ConfigRequiredError: %v", err, tt.required)
+			}
+			for _, name := range AI_PROVIDERS {
+				if !strings.Contains(err.Error(), name) {
+					t.Errorf("错误信息 %q 未列出可选值 %s", err.Error(), name)
+				}
+			}
+		})
+	}
+
+	configService := ConfigService{configRepo: &memoryConfigRepository{values: map[string]string{
+		"PROVIDER": " Anthropic ",
+		"BASE_URL": "http://localhost",
+		"API_KEY":  "sk",
+		"MODEL":    "m",
+	}}}
+	configs, err := configService.GetAiConfigs()
+	if err != nil {
+		t.Fatalf("GetAiConfigs: %v", err)
+	}
+	if configs["PROVIDER"] != AI_PROVIDER_ANTHROPIC {
+		t.Errorf("PROVIDER = %q", configs["PROVIDER"])
+	}
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AI_MAX_RETRIES               = 3                // 单次请求失败后的最大重试次数
+	AI_RETRY_BASE_DELAY          = 1 * time.Second  // 指数退避的初始等待时间
+	AI_RETRY_MAX_DELAY           = 30 * time.Second // 单次退避的最大等待时间
+	AI_RETRY_AFTER_MAX_DELAY     = 2 * time.Minute  // 服务端要求等待时间的上限，超过则不再重试
+	AI_CIRCUIT_FAILURE_THRESHOLD = 5                // 连续失败达到该次数后熔断，本次运行不再调用AI
+)
+
+// ErrAiCircuitOpen AI调用连续失败已熔断
+var ErrAiCircuitOpen = errors.New("AI服务连续调用失败，已熔断")
+
+// AiHTTPError AI接口返回的非200响应
+type AiHTTPError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // 服务端要求的等待时间（Retry-After 或限流重置头），未提供为0
+}
+
+func (e *AiHTTPError) Error() string {
+	return fmt.Sprintf("AI请求失败，状态码: %d，详情: %s", e.StatusCode, e.Body)
+}
+
+// isRetryableAiError 429、5xx 与网络超时可重试，其余错误（如鉴权失败、参数错误）直接返回
+func isRetryableAiError(err error) bool {
+	var httpErr *AiHTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryDelay 计算第 attempt 次重试前的等待时间：优先使用服务端要求的等待时间，否则使用带抖动的指数退避
+func retryDelay(err error, attempt int) time.Duration {
+	var httpErr *AiHTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		return httpErr.RetryAfter
+	}
+
+	backoff := AI_RETRY_BASE_DELAY << attempt
+	if backoff <= 0 || backoff > AI_RETRY_MAX_DELAY {
+		backoff = AI_RETRY_MAX_DELAY
+	}
+	// 等待时间在 [backoff/2, backoff) 之间随机，避免并发请求同时重试
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// completeWithRetry 调用AI服务提供方，对可重试的错误按退避策略重试
+func completeWithRetry(provider AiProvider, prompt string, sleep func(time.Duration)) (*AiCompletion, error) {
+	var lastErr error
+	for attempt := 0; attempt <= AI_MAX_RETRIES; attempt++ {
+		completion, err := provider.Complete(prompt)
+		if err == nil {
+			return completion, nil
+		}
+		lastErr = err
+
+		if !isRetryableAiError(err) || attempt == AI_MAX_RETRIES {
+			break
+		}
+
+		delay := retryDelay(err, attempt)
+		if delay > AI_RETRY_AFTER_MAX_DELAY {
+			log.Printf("AI服务要求等待 %s，超过上限，放弃重试", delay)
+			break
+		}
+		log.Printf("AI请求失败，%s 后进行第 %d 次重试: %v", delay.Round(time.Millisecond), attempt+1, err)
+		sleep(delay)
+	}
+	return nil, lastErr
+}
+
+// parseRetryAfter 解析服务端要求的等待时间，支持 Retry-After、retry-after-ms 以及 OpenAI/Anthropic 的限流重置头
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if ms := header.Get("retry-after-ms"); ms != "" {
+		if value, err := strconv.ParseFloat(ms, 64); err == nil && value > 0 {
+			return time.Duration(value * float64(time.Millisecond))
+		}
+	}
+
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.ParseFloat(retryAfter, 64); err == nil && seconds > 0 {
+			return time.Duration(seconds * float64(time.Second))
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil && at.After(now) {
+			return at.Sub(now)
+		}
+	}
+
+	// OpenAI：x-ratelimit-reset-requests / x-ratelimit-reset-tokens，格式如 "1s"、"6m0s"、"20ms"
+	var wait time.Duration
+	for _, key := range []string{"x-ratelimit-reset-requests", "x-ratelimit-reset-tokens"} {
+		if value := header.Get(key); value != "" {
+			if d, err := time.ParseDuration(strings.TrimSpace(value)); err == nil && d > wait {
+				wait = d
+			}
+		}
+	}
+
+	// Anthropic：anthropic-ratelimit-*-reset，RFC 3339 时间
+	for _, key := range []string{"anthropic-ratelimit-requests-reset", "anthropic-ratelimit-tokens-reset",
+		"anthropic-ratelimit-input-tokens-reset", "anthropic-ratelimit-output-tokens-reset"} {
+		if value := header.Get(key); value != "" {
+			if at, err := time.Parse(time.RFC3339, value); err == nil && at.Sub(now) > wait {
+				wait = at.Sub(now)
+			}
+		}
+	}
+	return wait
+}
+
+// aiCircuitBreaker AI调用熔断器：连续失败达到阈值后打开，本次运行剩余时间内不再调用AI，下次运行开始时重置
+type aiCircuitBreaker struct {
+	mu                  sync.Mutex
+	threshold           int
+	consecutiveFailures int
+	open                bool
+}
+
+func newAiCircuitBreaker(threshold int) *aiCircuitBreaker {
+	return &aiCircuitBreaker{threshold: threshold}
+}
+
+// IsOpen 熔断器是否已打开
+func (c *aiCircuitBreaker) IsOpen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.open
+}
+
+// RecordSuccess 调用成功，清零连续失败次数
+func (c *aiCircuitBreaker) RecordSuccess() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.consecutiveFailures = 0
+}
+
+// RecordFailure 调用失败，连续失败达到阈值时打开熔断器，返回本次是否触发熔断
+func (c *aiCircuitBreaker) RecordFailure() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.open {
+		return false
+	}
+	c.consecutiveFailures++
+	if c.consecutiveFailures >= c.threshold {
+		c.open = true
+		return true
+	}
+	return false
+}
+
+// Reset 重置熔断器
+func (c *aiCircuitBreaker) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.consecutiveFailures = 0
+	c.open = false
+}
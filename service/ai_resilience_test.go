@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   time.Duration
+	}{
+		{"未提供", nil, 0},
+		{"秒数", map[string]string{"Retry-After": "7"}, 7 * time.Second},
+		{"小数秒", map[string]string{"Retry-After": "1.5"}, 1500 * time.Millisecond},
+		{"HTTP 日期", map[string]string{"Retry-After": now.Add(90 * time.Second).Format(http.TimeFormat)}, 90 * time.Second},
+		{"已过去的 HTTP 日期", map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)}, 0},
+		{"无法解析", map[string]string{"Retry-After": "soon"}, 0},
+		{"毫秒优先", map[string]string{"retry-after-ms": "250", "Retry-After": "7"}, 250 * time.Millisecond},
+		{"OpenAI 重置头取较大值", map[string]string{"x-ratelimit-reset-requests": "1s", "x-ratelimit-reset-tokens": "6m0s"}, 6 * time.Minute},
+		{"Anthropic 重置头", map[string]string{"anthropic-ratelimit-tokens-reset": now.Add(20 * time.Second).Format(time.RFC3339)}, 20 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, value := range tt.header {
+				header.Set(key, value)
+			}
+			if got := parseRetryAfter(header, now); got != tt.want {
+				t.Errorf("parseRetryAfter = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	// 服务端要求的等待时间原样使用
+	if got := retryDelay(&AiHTTPError{StatusCode: 429, RetryAfter: 3 * time.Second}, 0); got != 3*time.Second {
+		t.Errorf("Retry-After 等待时间 = %v", got)
+	}
+
+	// 指数退避带抖动，落在 [backoff/2, backoff)
+	for attempt, backoff := range []time.Duration{AI_RETRY_BASE_DELAY, 2 * AI_RETRY_BASE_DELAY, 4 * AI_RETRY_BASE_DELAY} {
+		for i := 0; i < 50; i++ {
+			if got := retryDelay(errors.New("timeout"), attempt); got < backoff/2 || got >= backoff {
+				t.Fatalf("第 %d 次重试等待 %v，超出 [%v, %v)", attempt, got, backoff/2, backoff)
+			}
+		}
+	}
+
+	// 退避时间封顶（包括移位溢出的情况）
+	for _, attempt := range []int{10, 62, 100} {
+		if got := retryDelay(errors.New("timeout"), attempt); got < AI_RETRY_MAX_DELAY/2 || got >= AI_RETRY_MAX_DELAY {
+			t.Errorf("第 %d 次重试等待 %v，应封顶在 %v 以内", attempt, got, AI_RETRY_MAX_DELAY)
+		}
+	}
+}
+
+// scriptedProvider 依次返回预设结果的AI服务提供方
+type scriptedProvider struct {
+	errs  []error
+	calls int
+}
+
+func (p *scriptedProvider) Name() string {
+	return "scripted"
+}
+
+func (p *scriptedProvider) Complete(prompt string) (*AiCompletion, error) {
+	p.calls++
+	if p.calls <= len(p.errs) && p.errs[p.calls-1] != nil {
+		return nil, p.errs[p.calls-1]
+	}
+	return &AiCompletion{Content: "ok"}, nil
+}
+
+func TestCompleteWithRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		errs       []error
+		wantErr    bool
+		wantCalls  int
+		wantSleeps []time.Duration
+	}{
+		{
+			name:       "429 按 Retry-After 等待后成功",
+			errs:       []error{&AiHTTPError{StatusCode: 429, RetryAfter: 2 * time.Second}},
+			wantCalls:  2,
+			wantSleeps: []time.Duration{2 * time.Second},
+		},
+		{
+			name:       "5xx 重试到上限",
+			errs:       []error{&AiHTTPError{StatusCode: 500, RetryAfter: time.Second}, &AiHTTPError{StatusCode: 502, RetryAfter: time.Second}, &AiHTTPError{StatusCode: 503, RetryAfter: time.Second}, &AiHTTPError{StatusCode: 500, RetryAfter: time.Second}},
+			wantErr:    true,
+			wantCalls:  AI_MAX_RETRIES + 1,
+			wantSleeps: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:      "401 不重试",
+			errs:      []error{&AiHTTPError{StatusCode: 401}},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "400 不重试",
+			errs:      []error{&AiHTTPError{StatusCode: 400, RetryAfter: time.Second}},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "要求等待超过上限时放弃",
+			errs:      []error{&AiHTTPError{StatusCode: 429, RetryAfter: AI_RETRY_AFTER_MAX_DELAY + time.Second}},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &scriptedProvider{errs: tt.errs}
+			var sleeps []time.Duration
+			_, err := completeWithRetry(provider, "prompt", func(d time.Duration) {
+				sleeps = append(sleeps, d)
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, 期望出错: %v", err, tt.wantErr)
+			}
+			if provider.calls != tt.wantCalls {
+				t.Errorf("调用次数 = %d, 期望 %d", provider.calls, tt.wantCalls)
+			}
+			if len(sleeps) != len(tt.wantSleeps) {
+				t.Fatalf("等待 = %v, 期望 %v", sleeps, tt.wantSleeps)
+			}
+			for i := range sleeps {
+				if sleeps[i] != tt.wantSleeps[i] {
+					t.Errorf("第 %d 次等待 = %v, 期望 %v", i+1, sleeps[i], tt.wantSleeps[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAiCircuitBreakerTransitions(t *testing.T) {
+	breaker := newAiCircuitBreaker(3)
+
+	// 未达到阈值的失败不熔断，成功清零计数
+	breaker.RecordFailure()
+	breaker.RecordFailure()
+	breaker.RecordSuccess()
+	breaker.RecordFailure()
+	breaker.RecordFailure()
+	if breaker.IsOpen() {
+		t.Fatal("未连续失败 3 次时不应熔断")
+	}
+
+	// 第 3 次连续失败触发熔断，之后的失败不再重复触发
+	if !breaker.RecordFailure() || !breaker.IsOpen() {
+		t.Fatal("连续失败 3 次应触发熔断")
+	}
+	if breaker.RecordFailure() {
+		t.Fatal("已熔断时不应再次触发")
+	}
+
+	// 打开后一直保持，成功也不会关闭（本次运行不再调用AI）
+	breaker.RecordSuccess()
+	if !breaker.IsOpen() {
+		t.Fatal("熔断后应保持打开直到重置")
+	}
+
+	// Reset：新的运行开始时关闭并重新累计
+	breaker.Reset()
+	if breaker.IsOpen() {
+		t.Fatal("Reset 后应关闭熔断器")
+	}
+	if breaker.RecordFailure() || breaker.RecordFailure() {
+		t.Fatal("Reset 后应重新累计失败次数")
+	}
+	if !breaker.RecordFailure() {
+		t.Fatal("Reset 后再次连续失败 3 次应熔断")
+	}
+}
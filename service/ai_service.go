@@ -1,310 +1,257 @@
 package service
 
 import (
-	"bytes"
 	"encoding/json"
+	"get_jobs_go/config"
 	"get_jobs_go/model"
 	"get_jobs_go/repository"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// ConfigProvider 配置提供者接口（模拟Java中的ConfigService）
-type ConfigProvider interface {
-	GetAiConfigs() map[string]string
-}
-
 // AiService AI服务
 type AiService struct {
 	aiRepo        repository.AiRepository
+	aiCacheRepo   repository.AiCacheRepository
+	aiUsageRepo   repository.AiUsageRepository
+	jobRepo       repository.JobRepository
 	configService  ConfigService
 	httpClient    *http.Client
+	cacheHits     atomic.Int64
+	cacheMisses   atomic.Int64
+	breaker       *aiCircuitBreaker
+	sleep         func(time.Duration) // 重试前的等待（测试中替换以免真实等待）
+	runMu         sync.RWMutex
+	runId         string
+	budgetMu      sync.Mutex // 配置了预算时串行执行“检查预算-调用-记录费用”
+	configOverrides map[string]string
 }
 
-func NewAiService(aiRepo repository.AiRepository, configService ConfigService) *AiService {
+func NewAiService(
+	aiRepo repository.AiRepository,
+	aiCacheRepo repository.AiCacheRepository,
+	aiUsageRepo repository.AiUsageRepository,
+	jobRepo repository.JobRepository,
+	configService ConfigService,
+) *AiService {
 	return &AiService{
 		aiRepo:     aiRepo,
+		aiCacheRepo: aiCacheRepo,
+		aiUsageRepo: aiUsageRepo,
+		jobRepo: jobRepo,
 		configService: configService,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
+		breaker: newAiCircuitBreaker(AI_CIRCUIT_FAILURE_THRESHOLD),
+		sleep:   time.Sleep,
 	}
 }
 
-// AI请求结构体
-type aiRequest struct {
-	Model       string      `json:"model"`
-	Temperature float64     `json:"temperature,omitempty"`
-	Input       string      `json:"input,omitempty"`
-	Messages    []aiMessage `json:"messages,omitempty"`
-}
-
-type aiMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-// AI响应结构体
-type aiResponse struct {
-	ID      string    `json:"id"`
-	Model   string    `json:"model"`
-	Created int64     `json:"created"`
-	Choices []aiChoice `json:"choices,omitempty"`
-	Usage   aiUsage   `json:"usage,omitempty"`
-	OutputText string `json:"output_text,omitempty"` // Responses API 字段
-}
-
-type aiChoice struct {
-	Message aiMessage `json:"message"`
-}
-
-type aiUsage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
-}
-
 // SendRequest 发送AI请求并返回回复内容
 func (s *AiService) SendRequest(content string) (string, error) {
-	// 读取并校验配置
-	cfg,_ := s.configService.GetAiConfigs()
-	baseUrl := cfg["BASE_URL"]
-	apiKey := cfg["API_KEY"]
-	model := cfg["MODEL"]
-	
-	if baseUrl == "" || apiKey == "" || model == "" {
-		return "", fmt.Errorf("AI配置不完整: BASE_URL=%s, API_KEY=%s, MODEL=%s", baseUrl, apiKey, model)
-	}
-
-	// 根据模型类型选择端点
-	endpoint := s.buildEndpoint(baseUrl, model)
-
-	// 构建请求体
-	requestData := s.buildRequestData(model, content, endpoint)
+	return s.SendRequestWithOptions(content, AiRequestOptions{})
+}
 
-	// 创建HTTP请求
-	req, err := s.createHttpRequest(endpoint, apiKey, requestData)
+// SendRequestWithOptions 按指定选项（用途、是否跳过缓存）发送AI请求并返回回复内容
+func (s *AiService) SendRequestWithOptions(content string, opts AiRequestOptions) (string, error) {
+	completion, err := s.Complete(content, opts)
 	if err != nil {
-		return "", fmt.Errorf("创建HTTP请求失败: %v", err)
+		return "", err
 	}
+	return completion.Content, nil
+}
 
-	// 发送请求
-	resp, err := s.httpClient.Do(req)
+// Complete 使用配置的AI服务提供方发送请求，返回回复内容及Token用量；相同提供方、模型与提示词的结果会被缓存
+func (s *AiService) Complete(content string, opts AiRequestOptions) (*AiCompletion, error) {
+	cfg, err := s.aiConfigs()
 	if err != nil {
-		return "", fmt.Errorf("AI请求失败: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	provider, err := s.buildProvider(cfg)
 	if err != nil {
-		return "", fmt.Errorf("读取响应体失败: %v", err)
-	}
-
-	if resp.StatusCode == http.StatusOK {
-		return s.parseSuccessResponse(body, endpoint)
-	} else {
-		return s.handleErrorResponse(body, endpoint, content, apiKey, model)
+		return nil, err
 	}
-}
 
-// buildEndpoint 构建API端点
-func (s *AiService) buildEndpoint(baseUrl, model string) string {
-	normalized := s.normalizeBaseUrl(baseUrl)
-	
-	if s.isResponsesModel(model) {
-		if strings.Contains(normalized, "/v1") {
-			return normalized + "/responses"
-		}
-		return normalized + "/v1/responses"
-	} else {
-		if strings.Contains(normalized, "/v1") {
-			return normalized + "/chat/completions"
+	runId := s.currentRunId()
+	ttl := s.cacheTTL()
+	cacheKey := buildCacheKey(provider.Name(), cfg["MODEL"], content)
+	if ttl > 0 && !opts.BypassCache {
+		if cache := s.loadCache(cacheKey); cache != nil {
+			s.cacheHits.Add(1)
+			log.Printf("AI缓存命中: purpose=%s, key=%s", opts.Purpose, cacheKey[:12])
+			completion := &AiCompletion{
+				Model:   cache.Model,
+				Content: cache.Response,
+				Cached:  true,
+			}
+			s.recordUsage(runId, opts.Purpose, provider.Name(), cfg["MODEL"], completion, 0, nil)
+			return completion, nil
 		}
-		return normalized + "/v1/chat/completions"
+		// 只统计实际查询了缓存的请求，缓存关闭或跳过缓存时不计入未命中
+		s.cacheMisses.Add(1)
 	}
-}
 
-// buildRequestData 构建请求数据
-func (s *AiService) buildRequestData(model, content, endpoint string) aiRequest {
-	requestData := aiRequest{
-		Model:       model,
-		Temperature: 0.5,
+	// 并发请求（如并发筛选岗位）若同时检查预算，会在费用记录前一起通过检查而超出预算，
+	// 因此配置了预算时串行调用，上一个请求的费用记录后再检查下一个
+	if s.hasBudget(runId) {
+		s.budgetMu.Lock()
+		defer s.budgetMu.Unlock()
 	}
-
-	if strings.HasSuffix(endpoint, "/responses") {
-		requestData.Input = content
-	} else {
-		requestData.Messages = []aiMessage{
-			{Role: "user", Content: content},
-		}
+	if err := s.checkBudget(runId); err != nil {
+		return nil, err
+	}
+	if s.breaker.IsOpen() {
+		return nil, ErrAiCircuitOpen
 	}
 
-	return requestData
-}
-
-// createHttpRequest 创建HTTP请求
-func (s *AiService) createHttpRequest(endpoint, apiKey string, requestData aiRequest) (*http.Request, error) {
-	jsonData, err := json.Marshal(requestData)
+	start := time.Now()
+	completion, err := completeWithRetry(provider, content, s.sleep)
+	s.recordUsage(runId, opts.Purpose, provider.Name(), cfg["MODEL"], completion, time.Since(start), err)
 	if err != nil {
+		if s.breaker.RecordFailure() {
+			log.Printf("AI服务连续调用失败，已熔断，本次运行不再调用AI")
+			return nil, fmt.Errorf("%w: %v", ErrAiCircuitOpen, err)
+		}
 		return nil, err
 	}
+	s.breaker.RecordSuccess()
 
-	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
+	log.Printf("AI响应: provider=%s, purpose=%s, id=%s, model=%s, promptTokens=%d, completionTokens=%d, totalTokens=%d",
+		provider.Name(), opts.Purpose, completion.ID, completion.Model,
+		completion.Usage.PromptTokens, completion.Usage.CompletionTokens, completion.Usage.TotalTokens)
+
+	if ttl > 0 {
+		s.storeCache(cacheKey, provider.Name(), cfg["MODEL"], content, opts.Purpose, completion, ttl)
 	}
+	return completion, nil
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("api-key", apiKey) // 兼容Azure OpenAI
+// IsCircuitOpen AI熔断器是否已打开
+func (s *AiService) IsCircuitOpen() bool {
+	return s.breaker.IsOpen()
+}
 
-	return req, nil
+// SetConfigOverrides 覆盖AI调用配置（PROVIDER/BASE_URL/API_KEY/MODEL/API_VERSION），用于连接本地模拟服务
+func (s *AiService) SetConfigOverrides(overrides map[string]string) {
+	s.configOverrides = overrides
 }
 
-// parseSuccessResponse 解析成功响应
-func (s *AiService) parseSuccessResponse(body []byte, endpoint string) (string, error) {
-	var responseObj aiResponse
-	if err := json.Unmarshal(body, &responseObj); err != nil {
-		return "", fmt.Errorf("解析响应JSON失败: %v", err)
-	}
-
-	var responseContent string
-	if strings.HasSuffix(endpoint, "/responses") {
-		// Responses API
-		responseContent = responseObj.OutputText
-		if responseContent == "" {
-			// 兜底解析
-			if len(responseObj.Choices) > 0 {
-				responseContent = responseObj.Choices[0].Message.Content
-			} else {
-				responseContent = string(body) // 最后兜底
-			}
-		}
-	} else {
-		// Chat Completions API
-		if len(responseObj.Choices) > 0 {
-			responseContent = responseObj.Choices[0].Message.Content
-		} else {
-			return "", fmt.Errorf("响应中没有choices字段")
-		}
+// aiConfigs 获取AI调用配置，存在覆盖配置时优先使用覆盖值
+func (s *AiService) aiConfigs() (map[string]string, error) {
+	if len(s.configOverrides) == 0 {
+		return s.configService.GetAiConfigs()
 	}
 
-	// 记录日志
-	createdTime := time.Now()
-	if responseObj.Created > 0 {
-		createdTime = time.Unix(responseObj.Created, 0)
+	cfg, err := s.configService.GetAiConfigs()
+	if err != nil {
+		cfg = make(map[string]string)
 	}
-
-	log.Printf("AI响应: id=%s, time=%s, model=%s, promptTokens=%d, completionTokens=%d, totalTokens=%d",
-		responseObj.ID, createdTime.Format("2006-01-02 15:04:05"), responseObj.Model,
-		responseObj.Usage.PromptTokens, responseObj.Usage.CompletionTokens, responseObj.Usage.TotalTokens)
-
-	return responseContent, nil
+	for key, value := range s.configOverrides {
+		cfg[key] = value
+	}
+	return cfg, nil
 }
 
-// handleErrorResponse 处理错误响应
-func (s *AiService) handleErrorResponse(body []byte, endpoint, content, apiKey, model string) (string, error) {
-	bodyStr := string(body)
-	log.Printf("AI请求失败: endpoint=%s, body=%s", endpoint, bodyStr)
-
-	// 检查是否需要重试到Responses API
-	if !strings.HasSuffix(endpoint, "/responses") && s.containsReasoningParamError(bodyStr) {
-		log.Printf("检测到 reasoning 相关参数错误，自动切换到 Responses API 重试")
-		cfg,_ := s.configService.GetAiConfigs()
-		fallbackEndpoint := s.buildResponsesEndpoint(s.normalizeBaseUrl(cfg["BASE_URL"]))
-		return s.sendRequestViaResponses(content, apiKey, model, fallbackEndpoint)
-	}
+// buildProvider 根据AI配置创建对应的服务提供方
+func (s *AiService) buildProvider(cfg map[string]string) (AiProvider, error) {
+	return NewAiProvider(AiProviderConfig{
+		Provider:   cfg["PROVIDER"],
+		BaseUrl:    cfg["BASE_URL"],
+		ApiKey:     cfg["API_KEY"],
+		Model:      cfg["MODEL"],
+		ApiVersion: cfg["API_VERSION"],
+	}, s.httpClient)
+}
 
-	return "", fmt.Errorf("AI请求失败，状态码，详情: %s", bodyStr)
+// JobFitScore AI岗位匹配度评估结果
+type JobFitScore struct {
+	Score         int      `json:"score"`         // 匹配度评分（0-100）
+	MatchedSkills []string `json:"matchedSkills"` // 匹配的技能
+	MissingSkills []string `json:"missingSkills"` // 缺失的技能
+	Reason        string   `json:"reason"`        // 评分理由
 }
 
-// sendRequestViaResponses 使用Responses API发送请求（用于重试）
-func (s *AiService) sendRequestViaResponses(content, apiKey, model, endpoint string) (string, error) {
-	requestData := aiRequest{
-		Model:       model,
-		Temperature: 0.5,
-		Input:       content,
-	}
+// ScoreJobFit 根据职位描述与个人介绍评估岗位匹配度
+func (s *AiService) ScoreJobFit(jobInfo, introduce string) (*JobFitScore, error) {
+	prompt := fmt.Sprintf(`请评估求职者与以下岗位的匹配程度，只返回严格的JSON，不要包含任何其他内容。
+JSON格式：{"score": 0-100的整数, "matchedSkills": ["匹配的技能"], "missingSkills": ["缺失的技能"], "reason": "不超过100字的评分理由"}
+个人介绍：%s
+职位描述：%s`, introduce, jobInfo)
 
-	req, err := s.createHttpRequest(endpoint, apiKey, requestData)
+	reply, err := s.SendRequestWithOptions(prompt, AiRequestOptions{Purpose: AI_PURPOSE_JOB_SCORE})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return parseJobFitScore(reply)
+}
 
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return "", err
+// parseJobFitScore 从AI回复中解析匹配度评估结果
+func parseJobFitScore(reply string) (*JobFitScore, error) {
+	var score JobFitScore
+	if err := unmarshalReplyJSON(reply, &score); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+	if score.Score < 0 {
+		score.Score = 0
+	} else if score.Score > 100 {
+		score.Score = 100
 	}
+	return &score, nil
+}
 
-	if resp.StatusCode == http.StatusOK {
-		var responseObj aiResponse
-		if err := json.Unmarshal(body, &responseObj); err != nil {
-			return "", err
-		}
-
-		if responseObj.OutputText != "" {
-			return responseObj.OutputText, nil
-		}
+// JdAttributes AI从职位描述中提取的结构化属性
+type JdAttributes struct {
+	TechStack        []string `json:"techStack"`        // 技术栈
+	RequiredYears    *int     `json:"requiredYears"`    // 要求工作年限（未提及为空）
+	WorkMode         string   `json:"workMode"`         // 工作模式：remote/hybrid/onsite
+	OvertimeSignals  []string `json:"overtimeSignals"`  // 加班信号，如 大小周、996、单休
+	Outsourcing      bool     `json:"outsourcing"`      // 是否外包/驻场
+	OutsourcingHints []string `json:"outsourcingHints"` // 外包/驻场的依据
+	Education        string   `json:"education"`        // 学历要求
+	TeamSize         string   `json:"teamSize"`         // 团队规模
+}
 
-		// 兜底解析
-		if len(responseObj.Choices) > 0 {
-			return responseObj.Choices[0].Message.Content, nil
-		}
+// ExtractJdAttributes 使用AI从职位描述中提取结构化属性
+func (s *AiService) ExtractJdAttributes(jobInfo string) (*JdAttributes, error) {
+	prompt := fmt.Sprintf(`请从以下职位描述中提取结构化信息，只返回严格的JSON，不要包含任何其他内容，未提及的字段使用空值。
+JSON格式：{"techStack": ["技术栈"], "requiredYears": 要求的最低工作年限整数或null, "workMode": "remote/hybrid/onsite之一，未提及为空", "overtimeSignals": ["大小周、996、单休、加班等信号"], "outsourcing": 是否外包或驻场(true/false), "outsourcingHints": ["判断外包/驻场的依据"], "education": "学历要求", "teamSize": "团队规模"}
+职位描述：%s`, jobInfo)
 
-		return string(body), nil // 最后兜底
+	reply, err := s.SendRequestWithOptions(prompt, AiRequestOptions{Purpose: AI_PURPOSE_JD_EXTRACT})
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("Responses API 调用失败: endpoint=%s, body=%s", endpoint, string(body))
-	return "", fmt.Errorf("AI请求失败，状态码，详情: %s", string(body))
-}
-
-// ================= 工具方法 =================
-
-func (s *AiService) normalizeBaseUrl(baseUrl string) string {
-	if baseUrl == "" {
-		return ""
+	var attributes JdAttributes
+	if err := unmarshalReplyJSON(reply, &attributes); err != nil {
+		return nil, err
 	}
-	trimmed := strings.TrimSpace(baseUrl)
-	if strings.HasSuffix(trimmed, "/") {
-		return trimmed[:len(trimmed)-1]
+	attributes.WorkMode = strings.ToLower(strings.TrimSpace(attributes.WorkMode))
+	switch attributes.WorkMode {
+	case model.WORK_MODE_REMOTE, model.WORK_MODE_HYBRID, model.WORK_MODE_ONSITE:
+	default:
+		attributes.WorkMode = ""
 	}
-	return trimmed
+	return &attributes, nil
 }
 
-func (s *AiService) buildResponsesEndpoint(baseUrl string) string {
-	normalized := s.normalizeBaseUrl(baseUrl)
-	if strings.Contains(normalized, "/v1") {
-		return normalized + "/responses"
+// unmarshalReplyJSON 解析AI回复中的第一个JSON对象（兼容回复中包含Markdown代码块等多余内容）
+func unmarshalReplyJSON(reply string, v interface{}) error {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end <= start {
+		return fmt.Errorf("AI回复不是JSON: %s", reply)
 	}
-	return normalized + "/v1/responses"
-}
-
-func (s *AiService) isResponsesModel(model string) bool {
-	if model == "" {
-		return false
+	if err := json.Unmarshal([]byte(reply[start:end+1]), v); err != nil {
+		return fmt.Errorf("解析AI回复JSON失败: %v", err)
 	}
-	modelLower := strings.ToLower(model)
-	return strings.Contains(modelLower, "o1") || strings.Contains(modelLower, "o3") || 
-	       strings.Contains(modelLower, "o4") || strings.Contains(modelLower, "4.1") ||
-	       strings.Contains(modelLower, "reasoner") || strings.Contains(modelLower, "4o-mini") ||
-	       strings.Contains(modelLower, "gpt-4o-mini")
-}
-
-func (s *AiService) containsReasoningParamError(body string) bool {
-	bodyLower := strings.ToLower(body)
-	return (strings.Contains(bodyLower, "reasoning") && strings.Contains(bodyLower, "unsupported_value")) ||
-	       strings.Contains(bodyLower, "reasoning.summary")
+	return nil
 }
 
 // ================= AI配置管理方法 =================
@@ -335,6 +282,10 @@ func (s *AiService) GetAiConfigById(id int64) (*model.AiEntity, error) {
 
 // SaveOrUpdateAiConfig 保存或更新AI配置
 func (s *AiService) SaveOrUpdateAiConfig(introduce, prompt string) (*model.AiEntity, error) {
+	if err := ValidatePromptTemplate(prompt); err != nil {
+		return nil, err
+	}
+
 	aiEntity, err := s.aiRepo.FindLatest()
 	if err != nil {
 		return nil, err
@@ -363,6 +314,53 @@ func (s *AiService) SaveOrUpdateAiConfig(introduce, prompt string) (*model.AiEnt
 	return aiEntity, nil
 }
 
+// BuildGreetingPrompt 使用AI配置中的提示词模板渲染打招呼语提示词，未配置或模板无效时使用默认模板
+func (s *AiService) BuildGreetingPrompt(data *PromptTemplateData) (string, error) {
+	prompt := DefaultGreetingPromptTemplate
+	aiConfig, err := s.GetAiConfig()
+	if err != nil {
+		log.Printf("获取AI配置失败，使用默认提示词模板: %v", err)
+	} else if aiConfig != nil {
+		data.Introduce = aiConfig.Introduce
+		if IsPromptTemplateConfigured(aiConfig.Prompt) {
+			prompt = aiConfig.Prompt
+		}
+	}
+
+	rendered, err := RenderPromptTemplate(prompt, data)
+	if err != nil && prompt != DefaultGreetingPromptTemplate {
+		log.Printf("提示词模板渲染失败，使用默认提示词模板: %v", err)
+		return RenderPromptTemplate(DefaultGreetingPromptTemplate, data)
+	}
+	return rendered, err
+}
+
+// PreviewPrompt 使用已保存的职位预览提示词模板渲染结果，prompt 为空时使用当前AI配置中的模板
+func (s *AiService) PreviewPrompt(prompt string, jobId int64, keyword string) (string, error) {
+	job, err := s.jobRepo.FindByID(jobId)
+	if err != nil {
+		return "", err
+	}
+	if job == nil {
+		return "", fmt.Errorf("职位不存在: %d", jobId)
+	}
+
+	aiConfig, err := s.GetAiConfig()
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(prompt) == "" {
+		prompt = aiConfig.Prompt
+	}
+	if !IsPromptTemplateConfigured(prompt) {
+		prompt = DefaultGreetingPromptTemplate
+	}
+
+	data := NewPromptTemplateData(job, keyword, config.GlobalConfig.Boss.SayHi)
+	data.Introduce = aiConfig.Introduce
+	return RenderPromptTemplate(prompt, data)
+}
+
 // DeleteAiConfig 删除AI配置
 func (s *AiService) DeleteAiConfig(id int64) (bool, error) {
 	err := s.aiRepo.Delete(id)
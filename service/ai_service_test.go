@@ -0,0 +1,460 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"get_jobs_go/mockllm"
+	"get_jobs_go/model"
+)
+
+// memoryConfigRepository 内存配置仓储
+type memoryConfigRepository struct {
+	values map[string]string
+}
+
+func (r *memoryConfigRepository) FindAll() ([]*model.ConfigEntity, error) {
+	var configs []*model.ConfigEntity
+	for key, value := range r.values {
+		configs = append(configs, &model.ConfigEntity{ConfigKey: key, ConfigValue: value})
+	}
+	return configs, nil
+}
+
+func (r *memoryConfigRepository) FindByKey(configKey string) (*model.ConfigEntity, error) {
+	value, ok := r.values[configKey]
+	if !ok {
+		return nil, nil
+	}
+	return &model.ConfigEntity{ConfigKey: configKey, ConfigValue: value}, nil
+}
+
+func (r *memoryConfigRepository) FindByCategory(category string) ([]*model.ConfigEntity, error) {
+	return nil, nil
+}
+
+func (r *memoryConfigRepository) Save(config *model.ConfigEntity) error {
+	r.values[config.ConfigKey] = config.ConfigValue
+	return nil
+}
+
+func (r *memoryConfigRepository) Update(config *model.ConfigEntity) error {
+	return r.Save(config)
+}
+
+func (r *memoryConfigRepository) Delete(id int64) error {
+	return nil
+}
+
+// memoryAiCacheRepository 内存AI缓存仓储
+type memoryAiCacheRepository struct {
+	mu      sync.Mutex
+	entries map[string]*model.AiCacheEntity
+}
+
+func (r *memoryAiCacheRepository) FindByCacheKey(cacheKey string) (*model.AiCacheEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry, ok := r.entries[cacheKey]; ok {
+		copied := *entry
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryAiCacheRepository) Save(cache *model.AiCacheEntity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *cache
+	r.entries[cache.CacheKey] = &copied
+	return nil
+}
+
+func (r *memoryAiCacheRepository) IncrementHitCount(id int64) error {
+	return nil
+}
+
+func (r *memoryAiCacheRepository) DeleteExpired(now time.Time) (int64, error) {
+	return 0, nil
+}
+
+// memoryAiUsageRepository 内存AI用量仓储
+type memoryAiUsageRepository struct {
+	mu     sync.Mutex
+	usages []*model.AiUsageEntity
+}
+
+func (r *memoryAiUsageRepository) Save(usage *model.AiUsageEntity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usages = append(r.usages, usage)
+	return nil
+}
+
+func (r *memoryAiUsageRepository) SumCostSince(since time.Time) (float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total float64
+	for _, usage := range r.usages {
+		if !usage.CreatedAt.Before(since) {
+			total += usage.Cost
+		}
+	}
+	return total, nil
+}
+
+func (r *memoryAiUsageRepository) SumCostByRunId(runId string) (float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total float64
+	for _, usage := range r.usages {
+		if usage.RunId == runId {
+			total += usage.Cost
+		}
+	}
+	return total, nil
+}
+
+func (r *memoryAiUsageRepository) SummarizeRecentRuns(limit int) ([]*model.AiRunUsage, error) {
+	return nil, nil
+}
+
+func (r *memoryAiUsageRepository) all() []*model.AiUsageEntity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*model.AiUsageEntity{}, r.usages...)
+}
+
+// aiServiceFixture 连接模拟大模型服务的AI服务
+type aiServiceFixture struct {
+	service *AiService
+	llm     *mockllm.Server
+	usage   *memoryAiUsageRepository
+	sleeps  []time.Duration
+}
+
+// newAiServiceFixture 创建连接 mockllm 的AI服务，configs 覆盖默认配置（默认关闭缓存）
+func newAiServiceFixture(t *testing.T, configs map[string]string) *aiServiceFixture {
+	t.Helper()
+	llm := mockllm.NewServer()
+	server := httptest.NewServer(llm)
+	t.Cleanup(server.Close)
+
+	values := map[string]string{
+		"PROVIDER":           AI_PROVIDER_OPENAI_CHAT,
+		"BASE_URL":           server.URL,
+		"API_KEY":            "sk-test",
+		"MODEL":              mockllm.DEFAULT_MODEL,
+		"AI_CACHE_TTL_HOURS": "0",
+	}
+	for key, value := range configs {
+		values[key] = value
+	}
+
+	fixture := &aiServiceFixture{
+		llm:   llm,
+		usage: &memoryAiUsageRepository{},
+	}
+	fixture.service = NewAiService(nil,
+		&memoryAiCacheRepository{entries: make(map[string]*model.AiCacheEntity)},
+		fixture.usage, nil,
+		ConfigService{configRepo: &memoryConfigRepository{values: values}})
+	fixture.service.httpClient = server.Client()
+	fixture.service.sleep = func(d time.Duration) {
+		fixture.sleeps = append(fixture.sleeps, d)
+	}
+	fixture.service.BeginRun("run-test")
+	return fixture
+}
+
+// endpoints 模拟服务收到的请求依次对应的接口
+func (f *aiServiceFixture) endpoints() string {
+	var endpoints []string
+	for _, request := range f.llm.Requests() {
+		endpoints = append(endpoints, request.Endpoint)
+	}
+	return strings.Join(endpoints, ",")
+}
+
+func TestAiServiceSelectsEndpointByProvider(t *testing.T) {
+	tests := []struct {
+		provider string
+		endpoint string
+	}{
+		{AI_PROVIDER_OPENAI_CHAT, mockllm.ENDPOINT_CHAT},
+		{AI_PROVIDER_OPENAI_RESPONSES, mockllm.ENDPOINT_RESPONSES},
+		{AI_PROVIDER_OLLAMA, mockllm.ENDPOINT_CHAT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			fixture := newAiServiceFixture(t, map[string]string{"PROVIDER": tt.provider})
+			fixture.llm.Enqueue(tt.endpoint, mockllm.Text("您好，期待沟通"))
+
+			completion, err := fixture.service.Complete("写一句打招呼语", AiRequestOptions{Purpose: "greeting"})
+			if err != nil {
+				t.Fatalf("Complete: %v", err)
+			}
+			if completion.Content != "您好，期待沟通" {
+				t.Errorf("Content = %q", completion.Content)
+			}
+			if got := fixture.endpoints(); got != tt.endpoint {
+				t.Errorf("请求接口 = %s, 期望 %s", got, tt.endpoint)
+			}
+			if request := fixture.llm.Requests()[0]; request.Prompt != "写一句打招呼语" || request.Model != mockllm.DEFAULT_MODEL {
+				t.Errorf("请求 = %+v", request)
+			}
+		})
+	}
+}
+
+func TestAiServiceFallsBackToResponsesAPI(t *testing.T) {
+	fixture := newAiServiceFixture(t, nil)
+	fixture.llm.Enqueue(mockllm.ENDPOINT_CHAT, mockllm.ReasoningParamError())
+	fixture.llm.Enqueue(mockllm.ENDPOINT_RESPONSES, mockllm.Text("来自Responses"))
+
+	completion, err := fixture.service.Complete("写一句打招呼语", AiRequestOptions{Purpose: "greeting"})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	// output_text 解析为回复内容，用量按 input/output tokens 统计
+	if completion.Content != "来自Responses" {
+		t.Errorf("Content = %q", completion.Content)
+	}
+	if completion.Usage.PromptTokens != len([]rune("写一句打招呼语")) || completion.Usage.CompletionTokens != len([]rune("来自Responses")) {
+		t.Errorf("Usage = %+v", completion.Usage)
+	}
+	if got := fixture.endpoints(); got != "chat,responses" {
+		t.Errorf("请求接口 = %s, 期望先 chat 后 responses", got)
+	}
+	if len(fixture.sleeps) != 0 {
+		t.Errorf("参数错误回退不应等待重试: %v", fixture.sleeps)
+	}
+}
+
+func TestAiServiceRetriesWithRetryAfter(t *testing.T) {
+	fixture := newAiServiceFixture(t, nil)
+	fixture.llm.Enqueue(mockllm.ENDPOINT_CHAT, mockllm.RateLimited(2*time.Second), mockllm.ServerError(), mockllm.Text("重试成功"))
+
+	completion, err := fixture.service.Complete("写一句打招呼语", AiRequestOptions{})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if completion.Content != "重试成功" {
+		t.Errorf("Content = %q", completion.Content)
+	}
+	if got := fixture.endpoints(); got != "chat,chat,chat" {
+		t.Errorf("请求接口 = %s", got)
+	}
+
+	// 429 按 Retry-After 等待，500 按第 2 次重试的退避时间 [1s, 2s) 等待
+	if len(fixture.sleeps) != 2 || fixture.sleeps[0] != 2*time.Second ||
+		fixture.sleeps[1] < AI_RETRY_BASE_DELAY || fixture.sleeps[1] >= 2*AI_RETRY_BASE_DELAY {
+		t.Errorf("等待 = %v", fixture.sleeps)
+	}
+}
+
+func TestAiServiceDoesNotRetryClientErrors(t *testing.T) {
+	fixture := newAiServiceFixture(t, nil)
+	fixture.llm.Enqueue(mockllm.ENDPOINT_CHAT, mockllm.Reply{Status: http.StatusUnauthorized, Body: `{"error":{"message":"invalid api key"}}`})
+
+	if _, err := fixture.service.Complete("写一句打招呼语", AiRequestOptions{}); err == nil {
+		t.Fatal("期望返回鉴权错误")
+	}
+	if len(fixture.llm.Requests()) != 1 || len(fixture.sleeps) != 0 {
+		t.Errorf("401 不应重试：请求 %d 次，等待 %v", len(fixture.llm.Requests()), fixture.sleeps)
+	}
+}
+
+func TestAiServiceCircuitBreaker(t *testing.T) {
+	fixture := newAiServiceFixture(t, nil)
+
+	// 每次调用重试到上限仍失败，连续失败达到阈值后熔断
+	fixture.llm.SetResponder(func(endpoint, prompt string) string { return "" })
+	for i := 0; i < AI_CIRCUIT_FAILURE_THRESHOLD*(AI_MAX_RETRIES+1); i++ {
+		fixture.llm.Enqueue(mockllm.ENDPOINT_CHAT, mockllm.ServerError())
+	}
+	var err error
+	for i := 0; i < AI_CIRCUIT_FAILURE_THRESHOLD; i++ {
+		_, err = fixture.service.Complete("写一句打招呼语", AiRequestOptions{})
+		if err == nil {
+			t.Fatalf("第 %d 次调用期望失败", i+1)
+		}
+		if i < AI_CIRCUIT_FAILURE_THRESHOLD-1 && errors.Is(err, ErrAiCircuitOpen) {
+			t.Fatalf("第 %d 次失败不应熔断", i+1)
+		}
+	}
+	if !errors.Is(err, ErrAiCircuitOpen) || !fixture.service.IsCircuitOpen() {
+		t.Fatalf("连续失败 %d 次后应熔断, err = %v", AI_CIRCUIT_FAILURE_THRESHOLD, err)
+	}
+
+	// 熔断期间不再发出请求
+	requests := len(fixture.llm.Requests())
+	if _, err := fixture.service.Complete("写一句打招呼语", AiRequestOptions{}); !errors.Is(err, ErrAiCircuitOpen) {
+		t.Errorf("熔断期间 err = %v", err)
+	}
+	if len(fixture.llm.Requests()) != requests {
+		t.Error("熔断期间不应请求AI服务")
+	}
+
+	// 同一运行重复开始（运行ID不变）时保持熔断
+	fixture.service.BeginRun("run-test")
+	if !fixture.service.IsCircuitOpen() {
+		t.Fatal("运行ID不变时不应重置熔断器")
+	}
+
+	// 新的运行开始时重置，恢复调用
+	fixture.service.BeginRun("run-next")
+	if fixture.service.IsCircuitOpen() {
+		t.Fatal("新的运行开始时应重置熔断器")
+	}
+	fixture.llm.Enqueue(mockllm.ENDPOINT_CHAT, mockllm.Text("恢复"))
+	completion, err := fixture.service.Complete("写一句打招呼语", AiRequestOptions{})
+	if err != nil || completion.Content != "恢复" {
+		t.Fatalf("新运行的请求 = %v, %v", completion, err)
+	}
+}
+
+func TestAiServiceRecordsUsage(t *testing.T) {
+	fixture := newAiServiceFixture(t, map[string]string{
+		"AI_PRICE_TABLE": `{"mock-llm": {"input": 1000000, "output": 2000000}}`,
+	})
+	fixture.llm.SetLatency(30 * time.Millisecond)
+	fixture.llm.Enqueue(mockllm.ENDPOINT_CHAT, mockllm.Text("你好"), mockllm.Reply{Status: http.StatusBadRequest, Body: `{"error":{"message":"bad"}}`})
+
+	if _, err := fixture.service.Complete("问候", AiRequestOptions{Purpose: "greeting"}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if _, err := fixture.service.Complete("再问候", AiRequestOptions{Purpose: "score"}); err == nil {
+		t.Fatal("期望第二次调用失败")
+	}
+
+	usages := fixture.usage.all()
+	if len(usages) != 2 {
+		t.Fatalf("用量记录数 = %d", len(usages))
+	}
+	ok, failed := usages[0], usages[1]
+	if ok.RunId != "run-test" || ok.Purpose != "greeting" || ok.Provider != AI_PROVIDER_OPENAI_CHAT || ok.Model != mockllm.DEFAULT_MODEL {
+		t.Errorf("成功记录 = %+v", ok)
+	}
+	if ok.Success != 1 || ok.PromptTokens != 2 || ok.CompletionTokens != 2 || ok.TotalTokens != 4 {
+		t.Errorf("成功记录用量 = %+v", ok)
+	}
+	// 2 个输入Token × 1 + 2 个输出Token × 2（每百万Token单价）
+	if ok.Cost != 6 {
+		t.Errorf("费用 = %v, 期望 6", ok.Cost)
+	}
+	if ok.LatencyMs < 30 {
+		t.Errorf("耗时 = %dms, 应包含模拟服务的延迟", ok.LatencyMs)
+	}
+	if failed.Success != 0 || failed.Purpose != "score" || failed.TotalTokens != 0 || failed.Cost != 0 {
+		t.Errorf("失败记录 = %+v", failed)
+	}
+
+	if cost, _ := fixture.service.GetRunCost("run-test"); cost != 6 {
+		t.Errorf("本次运行费用 = %v", cost)
+	}
+}
+
+func TestAiServiceRunBudget(t *testing.T) {
+	fixture := newAiServiceFixture(t, map[string]string{
+		"AI_PRICE_TABLE": `{"mock-llm": {"input": 1000000, "output": 1000000}}`,
+		"AI_RUN_BUDGET":  "3",
+	})
+	fixture.llm.Enqueue(mockllm.ENDPOINT_CHAT, mockllm.Text("你好"))
+
+	if _, err := fixture.service.Complete("问候", AiRequestOptions{}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if _, err := fixture.service.Complete("问候", AiRequestOptions{}); !errors.Is(err, ErrAiBudgetExhausted) {
+		t.Errorf("超出单次预算 err = %v", err)
+	}
+	if len(fixture.llm.Requests()) != 1 {
+		t.Errorf("预算用尽后不应请求AI服务")
+	}
+}
+
+func TestAiServiceRunBudgetConcurrent(t *testing.T) {
+	fixture := newAiServiceFixture(t, map[string]string{
+		"AI_PRICE_TABLE": `{"mock-llm": {"input": 1000000, "output": 1000000}}`,
+		"AI_RUN_BUDGET":  "3",
+	})
+	fixture.llm.SetLatency(20 * time.Millisecond)
+
+	// 并发请求不能在首个请求的费用记录前一起通过预算检查
+	const workers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := fixture.service.Complete("问候", AiRequestOptions{})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		} else if !errors.Is(err, ErrAiBudgetExhausted) {
+			t.Errorf("err = %v, 期望预算用尽", err)
+		}
+	}
+	if succeeded != 1 || len(fixture.llm.Requests()) != 1 {
+		t.Errorf("成功 %d 次、请求 %d 次, 期望预算内只请求 1 次", succeeded, len(fixture.llm.Requests()))
+	}
+}
+
+func TestAiServiceCache(t *testing.T) {
+	fixture := newAiServiceFixture(t, map[string]string{"AI_CACHE_TTL_HOURS": "24"})
+	fixture.llm.Enqueue(mockllm.ENDPOINT_CHAT, mockllm.Text("第一次"), mockllm.Text("跳过缓存"))
+
+	first, err := fixture.service.Complete("问候", AiRequestOptions{Purpose: "greeting"})
+	if err != nil || first.Cached {
+		t.Fatalf("首次调用 = %+v, %v", first, err)
+	}
+	cached, err := fixture.service.Complete("问候", AiRequestOptions{Purpose: "greeting"})
+	if err != nil || !cached.Cached || cached.Content != "第一次" {
+		t.Fatalf("缓存命中 = %+v, %v", cached, err)
+	}
+	bypass, err := fixture.service.Complete("问候", AiRequestOptions{Purpose: "greeting", BypassCache: true})
+	if err != nil || bypass.Cached || bypass.Content != "跳过缓存" {
+		t.Fatalf("跳过缓存 = %+v, %v", bypass, err)
+	}
+
+	// 跳过缓存的请求不计入未命中
+	if stats := fixture.service.GetCacheStats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("缓存统计 = %+v, 期望命中 1 次、未命中 1 次", stats)
+	}
+	if len(fixture.llm.Requests()) != 2 {
+		t.Errorf("请求次数 = %d", len(fixture.llm.Requests()))
+	}
+	if usages := fixture.usage.all(); len(usages) != 3 || usages[1].Cached != 1 || usages[1].Cost != 0 {
+		t.Errorf("缓存命中应记录为 cached 且不计费: %+v", usages[1])
+	}
+}
+
+func TestAiServiceCacheDisabledCountsNoMisses(t *testing.T) {
+	fixture := newAiServiceFixture(t, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := fixture.service.Complete("问候", AiRequestOptions{}); err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+	}
+	if stats := fixture.service.GetCacheStats(); stats.Hits != 0 || stats.Misses != 0 {
+		t.Errorf("缓存关闭时统计 = %+v, 期望均为 0", stats)
+	}
+	if len(fixture.llm.Requests()) != 2 {
+		t.Errorf("缓存关闭时每次都应请求AI服务")
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"get_jobs_go/model"
+	"get_jobs_go/utils"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// BlacklistMatcher 黑名单匹配器：按匹配方式比较规范化（忽略大小写与全半角）后的值，已过期的条目不参与匹配
+type BlacklistMatcher struct {
+	entries []*blacklistMatchEntry
+}
+
+type blacklistMatchEntry struct {
+	entity *model.BlacklistEntity
+	rule   *textMatchRule
+}
+
+// textMatchRule 按匹配方式比较规范化文本的规则（黑名单与白名单共用）
+type textMatchRule struct {
+	mode  string
+	value string
+	regex *regexp.Regexp
+}
+
+// newTextMatchRule 创建文本匹配规则，值为空时返回 nil
+func newTextMatchRule(value, mode string) (*textMatchRule, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	rule := &textMatchRule{mode: normalizeMatchMode(mode), value: utils.NormalizeText(value)}
+	if rule.mode == model.BLACKLIST_MATCH_REGEX {
+		re, err := compileBlacklistRegex(value)
+		if err != nil {
+			return nil, err
+		}
+		rule.regex = re
+	}
+	return rule, nil
+}
+
+// match 判断规范化后的文本是否命中规则
+func (r *textMatchRule) match(normalized string) bool {
+	switch r.mode {
+	case model.BLACKLIST_MATCH_EXACT:
+		return normalized == r.value
+	case model.BLACKLIST_MATCH_PREFIX:
+		return strings.HasPrefix(normalized, r.value)
+	case model.BLACKLIST_MATCH_REGEX:
+		return r.regex.MatchString(normalized)
+	default:
+		return strings.Contains(normalized, r.value)
+	}
+}
+
+// NewBlacklistMatcher 根据黑名单条目创建匹配器，正则无效的条目记录日志后忽略
+func NewBlacklistMatcher(entities []*model.BlacklistEntity, now time.Time) *BlacklistMatcher {
+	matcher := &BlacklistMatcher{}
+	for _, entity := range entities {
+		if entity.IsExpired(now) {
+			continue
+		}
+
+		rule, err := newTextMatchRule(entity.Value, entity.MatchMode)
+		if err != nil {
+			log.Printf("黑名单正则无效，已忽略 | 类型：%s | 值：%s | 错误：%v", entity.Type, entity.Value, err)
+			continue
+		}
+		if rule != nil {
+			matcher.entries = append(matcher.entries, &blacklistMatchEntry{entity: entity, rule: rule})
+		}
+	}
+	return matcher
+}
+
+// Match 返回第一个命中的黑名单条目，未命中返回 nil
+func (m *BlacklistMatcher) Match(value string) *model.BlacklistEntity {
+	if m == nil || strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	normalized := utils.NormalizeText(value)
+	for _, entry := range m.entries {
+		if entry.rule.match(normalized) {
+			return entry.entity
+		}
+	}
+	return nil
+}
+
+// Len 参与匹配的条目数量
+func (m *BlacklistMatcher) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.entries)
+}
+
+// normalizeMatchMode 空匹配方式按包含处理（兼容旧数据）
+func normalizeMatchMode(mode string) string {
+	if mode == "" {
+		return model.BLACKLIST_MATCH_CONTAINS
+	}
+	return mode
+}
+
+// validateMatchEntry 校验黑名单/白名单条目的值、匹配方式与正则
+func validateMatchEntry(value, mode string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("名单值不能为空")
+	}
+	switch normalizeMatchMode(mode) {
+	case model.BLACKLIST_MATCH_EXACT, model.BLACKLIST_MATCH_CONTAINS, model.BLACKLIST_MATCH_PREFIX:
+		return nil
+	case model.BLACKLIST_MATCH_REGEX:
+		if _, err := compileBlacklistRegex(value); err != nil {
+			return fmt.Errorf("名单正则无效: %v", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("不支持的名单匹配方式: %s（可选 exact / contains / prefix / regex）", mode)
+}
+
+// compileBlacklistRegex 编译忽略大小写的正则（匹配对象为规范化后的值，因此模式同样转为半角）
+func compileBlacklistRegex(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + utils.ToHalfWidth(pattern))
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"get_jobs_go/model"
+)
+
+func TestBlacklistMatcherMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		value string
+		input string
+		want  bool
+	}{
+		// exact
+		{"完全相同", model.BLACKLIST_MATCH_EXACT, "字节跳动", "字节跳动", true},
+		{"完全相同忽略大小写", model.BLACKLIST_MATCH_EXACT, "ByteDance", "BYTEDANCE", true},
+		{"完全相同忽略全半角", model.BLACKLIST_MATCH_EXACT, "ABC科技", "ＡＢＣ科技", true},
+		{"完全相同合并多余空白", model.BLACKLIST_MATCH_EXACT, "Open AI", "  open　　ai ", true},
+		{"完全相同不匹配子串", model.BLACKLIST_MATCH_EXACT, "字节", "字节跳动", false},
+
+		// contains
+		{"包含", model.BLACKLIST_MATCH_CONTAINS, "外包", "某某外包服务有限公司", true},
+		{"包含忽略大小写与全半角", model.BLACKLIST_MATCH_CONTAINS, "ｈｒ", "Senior HR Manager", true},
+		{"全角值匹配半角输入", model.BLACKLIST_MATCH_CONTAINS, "ＩＴ外包", "it外包项目", true},
+		{"包含未命中", model.BLACKLIST_MATCH_CONTAINS, "外包", "字节跳动", false},
+		{"空匹配方式按包含处理", "", "Ａｃｍｅ", "Beijing ACME Inc.", true},
+
+		// prefix
+		{"前缀", model.BLACKLIST_MATCH_PREFIX, "北京", "北京某某科技有限公司", true},
+		{"前缀忽略大小写与全半角", model.BLACKLIST_MATCH_PREFIX, "abc", "ＡＢＣ科技", true},
+		{"前缀不匹配中间位置", model.BLACKLIST_MATCH_PREFIX, "科技", "北京某某科技有限公司", false},
+
+		// regex
+		{"正则", model.BLACKLIST_MATCH_REGEX, "^.+(外包|派遣)", "某某外包服务", true},
+		{"正则忽略大小写", model.BLACKLIST_MATCH_REGEX, "^hr|猎头", "HRBP", true},
+		{"正则匹配全角输入", model.BLACKLIST_MATCH_REGEX, `^abc\d+$`, "ＡＢＣ１２３", true},
+		{"正则中的全角字符转为半角", model.BLACKLIST_MATCH_REGEX, "ＡＢＣ.*科技", "abc智能科技", true},
+		{"正则中的全角括号作为分组", model.BLACKLIST_MATCH_REGEX, "（外包｜派遣）公司$", "某某派遣公司", true},
+		{"正则未命中", model.BLACKLIST_MATCH_REGEX, "^外包", "某某外包", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity := &model.BlacklistEntity{Type: "company", Value: tt.value, MatchMode: tt.mode}
+			matcher := NewBlacklistMatcher([]*model.BlacklistEntity{entity}, time.Now())
+			if matcher.Len() != 1 {
+				t.Fatalf("Len = %d, 期望 1", matcher.Len())
+			}
+			if got := matcher.Match(tt.input) == entity; got != tt.want {
+				t.Errorf("%s %q 匹配 %q = %v, 期望 %v", tt.mode, tt.value, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBlacklistMatcherSkipsEntries(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	expired := &model.BlacklistEntity{Value: "过期公司", ExpiresAt: &past}
+	expiringNow := &model.BlacklistEntity{Value: "刚到期公司", ExpiresAt: &now}
+	invalid := &model.BlacklistEntity{Value: "(外包", MatchMode: model.BLACKLIST_MATCH_REGEX}
+	blank := &model.BlacklistEntity{Value: "  "}
+	active := &model.BlacklistEntity{Value: "有效公司", ExpiresAt: &future}
+	permanent := &model.BlacklistEntity{Value: "公司", MatchMode: model.BLACKLIST_MATCH_CONTAINS}
+
+	matcher := NewBlacklistMatcher([]*model.BlacklistEntity{expired, expiringNow, invalid, blank, active, permanent}, now)
+	if matcher.Len() != 2 {
+		t.Fatalf("Len = %d, 期望只保留未过期且有效的 2 个条目", matcher.Len())
+	}
+
+	tests := []struct {
+		input string
+		want  *model.BlacklistEntity
+	}{
+		{"过期公司", permanent},
+		{"刚到期公司", permanent},
+		{"(外包", nil},
+		{"有效公司", active},
+		{"其他公司", permanent},
+		{"  ", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := matcher.Match(tt.input); got != tt.want {
+			t.Errorf("Match(%q) = %+v, 期望 %+v", tt.input, got, tt.want)
+		}
+	}
+
+	// 空匹配器
+	var empty *BlacklistMatcher
+	if empty.Match("公司") != nil || empty.Len() != 0 {
+		t.Error("nil 匹配器不应命中任何值")
+	}
+}
+
+func TestValidateMatchEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		mode    string
+		wantErr string
+	}{
+		{"包含", "外包", model.BLACKLIST_MATCH_CONTAINS, ""},
+		{"空匹配方式", "外包", "", ""},
+		{"完全相同", "外包", model.BLACKLIST_MATCH_EXACT, ""},
+		{"前缀", "外包", model.BLACKLIST_MATCH_PREFIX, ""},
+		{"有效正则", "^(外包|派遣)", model.BLACKLIST_MATCH_REGEX, ""},
+		{"含全角字符的有效正则", "（外包）", model.BLACKLIST_MATCH_REGEX, ""},
+		{"无效正则", "(外包", model.BLACKLIST_MATCH_REGEX, "名单正则无效"},
+		{"全角括号不成对的无效正则", "（外包", model.BLACKLIST_MATCH_REGEX, "名单正则无效"},
+		{"空值", " ", model.BLACKLIST_MATCH_CONTAINS, "名单值不能为空"},
+		{"未知匹配方式", "外包", "fuzzy", "不支持的名单匹配方式"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMatchEntry(tt.value, tt.mode)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateMatchEntry(%q, %q) = %v", tt.value, tt.mode, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateMatchEntry(%q, %q) err = %v, 期望包含 %q", tt.value, tt.mode, err, tt.wantErr)
+			}
+		})
+	}
+}
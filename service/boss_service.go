@@ -2,9 +2,11 @@ package service
 
 import (
 	"database/sql"
+	"fmt"
 	"get_jobs_go/model"
 	"get_jobs_go/config"
 	"get_jobs_go/repository"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,7 +17,8 @@ import (
 
 // 常量定义
 const (
-	UNLIMITED_CODE = "0"
+	UNLIMITED_CODE    = "0"
+	AI_RUN_COST_LIMIT = 20 // 统计中展示的最近运行数量
 )
 
 // 统计相关结构体
@@ -33,6 +36,8 @@ type Kpi struct {
 	Pending     int64    `json:"pending"`
 	Filtered    int64    `json:"filtered"`
 	Failed      int64    `json:"failed"`
+	Contacted   int64    `json:"contacted"`   // 平台上已沟通过的岗位数
+	Whitelisted int64    `json:"whitelisted"` // 命中白名单的岗位数
 	AvgMonthlyK *float64 `json:"avgMonthlyK"`
 }
 
@@ -46,28 +51,70 @@ type BucketValue struct {
 	Value  int64  `json:"value"`
 }
 
+// KeywordStat 搜索关键词命中统计
+type KeywordStat struct {
+	Keyword     string  `json:"keyword"`
+	Total       int64   `json:"total"`       // 该关键词搜到的岗位数
+	Unique      int64   `json:"unique"`      // 只有该关键词搜到的岗位数
+	OverlapRate float64 `json:"overlapRate"` // 与其他关键词重叠的比例（0-1），越高越可能是冗余关键词
+}
+
 type Charts struct {
-	ByStatus      []NameValue   `json:"byStatus"`
-	ByCity        []NameValue   `json:"byCity"`
-	ByIndustry    []NameValue   `json:"byIndustry"`
-	ByCompany     []NameValue   `json:"byCompany"`
-	ByExperience  []NameValue   `json:"byExperience"`
-	ByDegree      []NameValue   `json:"byDegree"`
-	SalaryBuckets []BucketValue `json:"salaryBuckets"`
-	DailyTrend    []NameValue   `json:"dailyTrend"`
-	HrActivity    []NameValue   `json:"hrActivity"`
+	ByStatus        []NameValue   `json:"byStatus"`
+	ByCity          []NameValue   `json:"byCity"`
+	ByIndustry      []NameValue   `json:"byIndustry"`
+	ByCompany       []NameValue   `json:"byCompany"`
+	ByExperience    []NameValue   `json:"byExperience"`
+	ByDegree        []NameValue   `json:"byDegree"`
+	SalaryBuckets   []BucketValue `json:"salaryBuckets"`
+	DailyTrend      []NameValue   `json:"dailyTrend"`
+	HrActivity      []NameValue   `json:"hrActivity"`
+	ByTechStack     []NameValue   `json:"byTechStack"`
+	ByWorkMode      []NameValue   `json:"byWorkMode"`
+	ByOvertime      []NameValue   `json:"byOvertime"`
+	ByRequiredYears []NameValue   `json:"byRequiredYears"`
+	ByWhitelist     []NameValue   `json:"byWhitelist"`     // 白名单条目命中数（Top10）
+	ByRecruiterType []NameValue   `json:"byRecruiterType"` // 招聘方类型（猎头/外包/直招）
+	DistanceBuckets []BucketValue `json:"distanceBuckets"` // 到最近通勤点的距离分布
+	ByDistrict      []NameValue   `json:"byDistrict"`      // 区县与商圈（Top10）
+	KeywordHits     []KeywordStat `json:"keywordHits"`     // 各搜索关键词命中与重叠情况
+	KeywordOverlap  []NameValue   `json:"keywordOverlap"`  // 关键词两两重叠的岗位数（Top10）
+}
+
+// BossJobQuery Boss职位统计与列表的筛选条件
+type BossJobQuery struct {
+	AccountId          *int64   `json:"accountId"` // 所属账号（为空不限，0=默认账号）
+	Statuses           []string `json:"statuses"`
+	Location           string   `json:"location"`
+	Experience         string   `json:"experience"`
+	Degree             string   `json:"degree"`
+	MinK               *float64 `json:"minK"`
+	MaxK               *float64 `json:"maxK"`
+	Keyword            string   `json:"keyword"`
+	FilterHeadhunter   bool     `json:"filterHeadhunter"`
+	ExcludeAgency      bool     `json:"excludeAgency"`      // 排除外包/派遣机构招聘的岗位
+	TechStack          string   `json:"techStack"`          // 技术栈包含
+	WorkMode           string   `json:"workMode"`           // 工作模式（remote/hybrid/onsite）
+	ExcludeOvertime    bool     `json:"excludeOvertime"`    // 排除有加班信号的岗位
+	ExcludeOutsourcing bool     `json:"excludeOutsourcing"` // 排除外包/驻场岗位
+	MaxRequiredYears   *int     `json:"maxRequiredYears"`   // 年限要求不高于该值
+	MaxInactiveDays    *int     `json:"maxInactiveDays"`    // HR不活跃天数不高于该值
+	MaxDistanceKm      *float64 `json:"maxDistanceKm"`      // 到最近通勤点的距离不高于该值（千米）
+	District           string   `json:"district"`           // 区县或商圈包含
+	HitKeyword         string   `json:"hitKeyword"`         // 搜索关键词包含
 }
 
 type StatsResponse struct {
-	Kpi    *Kpi    `json:"kpi"`
-	Charts *Charts `json:"charts"`
+	Kpi        *Kpi                `json:"kpi"`
+	Charts     *Charts             `json:"charts"`
+	AiRunCosts []*model.AiRunUsage `json:"aiRunCosts"` // 最近运行的AI用量与费用
 }
 
 type PagedResult struct {
-	Items []*model.BossJobDataEntity `json:"items"`
-	Total int64                      `json:"total"`
-	Page  int                        `json:"page"`
-	Size  int                        `json:"size"`
+	Items []*model.Job `json:"items"`
+	Total int64        `json:"total"`
+	Page  int          `json:"page"`
+	Size  int          `json:"size"`
 }
 
 // BossService Boss数据服务
@@ -76,7 +123,9 @@ type BossService struct {
 	industryRepo   repository.BossIndustryRepository
 	configRepo     repository.BossConfigRepository
 	blacklistRepo  repository.BlacklistRepository
-	jobDataRepo    repository.BossJobDataRepository
+	whitelistRepo  repository.WhitelistRepository
+	jobRepo        repository.JobRepository
+	aiUsageRepo    repository.AiUsageRepository
 	db             *gorm.DB
 }
 
@@ -85,7 +134,9 @@ func NewBossService(
 	industryRepo repository.BossIndustryRepository,
 	configRepo repository.BossConfigRepository,
 	blacklistRepo repository.BlacklistRepository,
-	jobDataRepo repository.BossJobDataRepository,
+	whitelistRepo repository.WhitelistRepository,
+	jobRepo repository.JobRepository,
+	aiUsageRepo repository.AiUsageRepository,
 	db *gorm.DB,
 ) *BossService {
 	return &BossService{
@@ -93,7 +144,9 @@ func NewBossService(
 		industryRepo:  industryRepo,
 		configRepo:    configRepo,
 		blacklistRepo: blacklistRepo,
-		jobDataRepo:   jobDataRepo,
+		whitelistRepo: whitelistRepo,
+		jobRepo:       jobRepo,
+		aiUsageRepo:   aiUsageRepo,
 		db:            db,
 	}
 }
@@ -293,6 +346,64 @@ func (s *BossService) SaveOrUpdateFirstSelective(partial *model.BossConfigEntity
 	if partial.DeadStatus != "" {
 		existing.DeadStatus = partial.DeadStatus
 	}
+	if partial.MaxInactiveDays != 0 {
+		existing.MaxInactiveDays = partial.MaxInactiveDays
+	}
+	if partial.MaxCommuteKm != 0 {
+		existing.MaxCommuteKm = partial.MaxCommuteKm
+	}
+	if partial.Districts != "" {
+		existing.Districts = partial.Districts
+	}
+	if partial.JdInclude != "" {
+		existing.JdInclude = partial.JdInclude
+	}
+	if partial.JdExclude != "" {
+		existing.JdExclude = partial.JdExclude
+	}
+	if partial.DetailConcurrency != 0 {
+		existing.DetailConcurrency = partial.DetailConcurrency
+	}
+	if partial.DetailTimeout != 0 {
+		existing.DetailTimeout = partial.DetailTimeout
+	}
+	if partial.CaptureDir != "" {
+		existing.CaptureDir = partial.CaptureDir
+	}
+
+	if partial.EnableAiScore != 0 {
+		existing.EnableAiScore = partial.EnableAiScore
+	}
+	if partial.AiScoreThreshold != 0 {
+		existing.AiScoreThreshold = partial.AiScoreThreshold
+	}
+	if partial.EnableAiExtract != 0 {
+		existing.EnableAiExtract = partial.EnableAiExtract
+	}
+	if partial.FilterOvertime != 0 {
+		existing.FilterOvertime = partial.FilterOvertime
+	}
+	if partial.FilterOutsourcing != 0 {
+		existing.FilterOutsourcing = partial.FilterOutsourcing
+	}
+	if partial.MaxRequiredYears != 0 {
+		existing.MaxRequiredYears = partial.MaxRequiredYears
+	}
+	if partial.GreetingBanWords != "" {
+		existing.GreetingBanWords = partial.GreetingBanWords
+	}
+	if partial.FilterHeadhunter != 0 {
+		existing.FilterHeadhunter = partial.FilterHeadhunter
+	}
+	if partial.FilterAgency != 0 {
+		existing.FilterAgency = partial.FilterAgency
+	}
+	if partial.HunterCompanies != "" {
+		existing.HunterCompanies = partial.HunterCompanies
+	}
+	if partial.AgencyCompanies != "" {
+		existing.AgencyCompanies = partial.AgencyCompanies
+	}
 	
 	existing.UpdatedAt = now
 	if err := s.configRepo.Update(existing); err != nil {
@@ -336,6 +447,25 @@ func (s *BossService) LoadBossConfig() (*config.BossConfig, error) {
 		Stage: s.ToCodes("stage", s.ParseListString(entity.Stage)),
 		Salary: s.ToCodes("salary", s.ParseListString(entity.Salary)),
 		DeadStatus: s.ParseListString(entity.DeadStatus),
+		MaxInactiveDays: entity.MaxInactiveDays,
+		EnableAIScore: entity.EnableAiScore == 1,
+		AIScoreThreshold: entity.AiScoreThreshold,
+		EnableAIExtract: entity.EnableAiExtract == 1,
+		FilterOvertime: entity.FilterOvertime == 1,
+		FilterOutsourcing: entity.FilterOutsourcing == 1,
+		MaxRequiredYears: entity.MaxRequiredYears,
+		GreetingBanWords: s.ParseListString(entity.GreetingBanWords),
+		FilterHeadhunter: entity.FilterHeadhunter == 1,
+		FilterAgency: entity.FilterAgency == 1,
+		HunterCompanies: s.ParseListString(entity.HunterCompanies),
+		AgencyCompanies: s.ParseListString(entity.AgencyCompanies),
+		MaxCommuteKm: entity.MaxCommuteKm,
+		Districts: s.ParseListString(entity.Districts),
+		JdInclude: s.ParseListString(entity.JdInclude),
+		JdExclude: s.ParseListString(entity.JdExclude),
+		DetailConcurrency: entity.DetailConcurrency,
+		DetailTimeout: entity.DetailTimeout,
+		CaptureDir: entity.CaptureDir,
 	}
 
 	// 处理职位类型
@@ -472,58 +602,76 @@ func (s *BossService) NormalizeCityToName(raw string) string {
 
 // ==================== Blacklist相关方法 ====================
 
-// GetBlacklistByType 根据类型获取黑名单
-func (s *BossService) GetBlacklistByType(typeStr string) (map[string]bool, error) {
+// GetBlacklistByType 根据类型获取黑名单匹配器（已过期的条目不参与匹配）
+func (s *BossService) GetBlacklistByType(typeStr string) (*BlacklistMatcher, error) {
 	blacklists, err := s.blacklistRepo.FindByType(typeStr)
 	if err != nil {
 		return nil, err
 	}
-	
-	result := make(map[string]bool)
-	for _, blacklist := range blacklists {
-		result[blacklist.Value] = true
-	}
-	
-	return result, nil
+
+	return NewBlacklistMatcher(blacklists, time.Now()), nil
 }
 
 // GetBlackCompanies 获取公司黑名单
-func (s *BossService) GetBlackCompanies() (map[string]bool, error) {
+func (s *BossService) GetBlackCompanies() (*BlacklistMatcher, error) {
 	return s.GetBlacklistByType("company")
 }
 
 // GetBlackRecruiters 获取招聘者黑名单
-func (s *BossService) GetBlackRecruiters() (map[string]bool, error) {
+func (s *BossService) GetBlackRecruiters() (*BlacklistMatcher, error) {
 	return s.GetBlacklistByType("recruiter")
 }
 
 // GetBlackJobs 获取职位黑名单
-func (s *BossService) GetBlackJobs() (map[string]bool, error) {
+func (s *BossService) GetBlackJobs() (*BlacklistMatcher, error) {
 	return s.GetBlacklistByType("job")
 }
 
-// AddBlacklist 添加黑名单
+// AddBlacklist 添加黑名单（包含匹配、永久有效）
 func (s *BossService) AddBlacklist(typeStr, value string) (bool, error) {
-	count, err := s.blacklistRepo.CountByTypeAndValue(typeStr, value)
+	return s.SaveBlacklist(&model.BlacklistEntity{
+		Type:      typeStr,
+		Value:     value,
+		MatchMode: model.BLACKLIST_MATCH_CONTAINS,
+	})
+}
+
+// SaveBlacklist 校验并保存黑名单条目：ID 为 0 时新建（同类型同值已存在时返回 false），否则按ID更新匹配方式、过期时间与原因
+func (s *BossService) SaveBlacklist(entry *model.BlacklistEntity) (bool, error) {
+	entry.Value = strings.TrimSpace(entry.Value)
+	entry.MatchMode = normalizeMatchMode(entry.MatchMode)
+	if err := validateMatchEntry(entry.Value, entry.MatchMode); err != nil {
+		return false, err
+	}
+
+	entry.UpdatedAt = time.Now()
+	if entry.ID != 0 {
+		existing, err := s.blacklistRepo.FindByID(entry.ID)
+		if err != nil {
+			return false, err
+		}
+		if existing == nil {
+			return false, fmt.Errorf("黑名单不存在: %d", entry.ID)
+		}
+		entry.CreatedAt = existing.CreatedAt
+		if err := s.blacklistRepo.Update(entry); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	count, err := s.blacklistRepo.CountByTypeAndValue(entry.Type, entry.Value)
 	if err != nil {
 		return false, err
 	}
-	
 	if count > 0 {
 		return false, nil // 已存在
 	}
-	
-	model := &model.BlacklistEntity{
-		Type:      typeStr,
-		Value:     value,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	
-	if err := s.blacklistRepo.Save(model); err != nil {
+
+	entry.CreatedAt = entry.UpdatedAt
+	if err := s.blacklistRepo.Save(entry); err != nil {
 		return false, err
 	}
-	
 	return true, nil
 }
 
@@ -546,16 +694,91 @@ func (s *BossService) RemoveBlacklist(typeStr, value string) (bool, error) {
 	return true, nil
 }
 
-// GetAllBlacklist 获取所有黑名单
+// RemoveBlacklistById 按ID删除黑名单
+func (s *BossService) RemoveBlacklistById(id int64) error {
+	return s.blacklistRepo.Delete(id)
+}
+
+// GetAllBlacklist 获取所有黑名单（包括已过期的条目）
 func (s *BossService) GetAllBlacklist() ([]*model.BlacklistEntity, error) {
 	return s.blacklistRepo.FindAll()
 }
 
+// ==================== Whitelist相关方法 ====================
+
+// GetWhitelist 获取白名单匹配器（按优先级从高到低匹配，已过期的条目不参与匹配）
+func (s *BossService) GetWhitelist() (*WhitelistMatcher, error) {
+	whitelists, err := s.whitelistRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWhitelistMatcher(whitelists, time.Now()), nil
+}
+
+// SaveWhitelist 校验并保存白名单条目：ID 为 0 时新建（同类型同值已存在时返回 false），否则按ID更新
+// 未设置优先级时使用默认优先级，保证白名单岗位先于普通岗位投递
+func (s *BossService) SaveWhitelist(entry *model.WhitelistEntity) (bool, error) {
+	switch entry.Type {
+	case model.WHITELIST_TYPE_COMPANY, model.WHITELIST_TYPE_JOB, model.WHITELIST_TYPE_KEYWORD:
+	default:
+		return false, fmt.Errorf("不支持的白名单类型: %s（可选 company / job / keyword）", entry.Type)
+	}
+	entry.Value = strings.TrimSpace(entry.Value)
+	entry.MatchMode = normalizeMatchMode(entry.MatchMode)
+	if err := validateMatchEntry(entry.Value, entry.MatchMode); err != nil {
+		return false, err
+	}
+	if entry.Priority == 0 {
+		entry.Priority = model.DEFAULT_WHITELIST_PRIORITY
+	}
+
+	entry.UpdatedAt = time.Now()
+	if entry.ID != 0 {
+		existing, err := s.whitelistRepo.FindByID(entry.ID)
+		if err != nil {
+			return false, err
+		}
+		if existing == nil {
+			return false, fmt.Errorf("白名单不存在: %d", entry.ID)
+		}
+		entry.CreatedAt = existing.CreatedAt
+		if err := s.whitelistRepo.Update(entry); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	count, err := s.whitelistRepo.CountByTypeAndValue(entry.Type, entry.Value)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil // 已存在
+	}
+
+	entry.CreatedAt = entry.UpdatedAt
+	if err := s.whitelistRepo.Save(entry); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveWhitelistById 按ID删除白名单
+func (s *BossService) RemoveWhitelistById(id int64) error {
+	return s.whitelistRepo.Delete(id)
+}
+
+// GetAllWhitelist 获取所有白名单（包括已过期的条目）
+func (s *BossService) GetAllWhitelist() ([]*model.WhitelistEntity, error) {
+	return s.whitelistRepo.FindAll()
+}
+
 // ==================== 职位数据相关方法 ====================
 
-// EnsureBossDataColumnOrder 确保表列顺序正确
+// EnsureBossDataColumnOrder 确保职位表结构最新（Boss职位保存在统一职位表中）
 func (s *BossService) EnsureBossDataColumnOrder() error {
-	return s.db.AutoMigrate(&model.BossJobDataEntity{})
+	return s.db.AutoMigrate(&model.Job{})
 }
 
 // ExistsBossJob 判断职位是否存在
@@ -563,12 +786,13 @@ func (s *BossService) ExistsBossJob(encryptId, encryptUserId string) (bool, erro
 	if encryptId == "" || encryptUserId == "" {
 		return false, nil
 	}
-	
-	job, err := s.jobDataRepo.FindByEncryptIdAndUserId(encryptId, encryptUserId)
+
+	count, err := s.jobRepo.CountByCondition("platform = ? AND platform_job_id = ? AND platform_user_id = ?",
+		model.PLATFORM_BOSS, encryptId, encryptUserId)
 	if err != nil {
 		return false, err
 	}
-	return job != nil, nil
+	return count > 0, nil
 }
 
 // ExistsBossJobByEncryptId 根据encryptId判断职位是否存在
@@ -576,25 +800,43 @@ func (s *BossService) ExistsBossJobByEncryptId(encryptId string) (bool, error) {
 	if encryptId == "" {
 		return false, nil
 	}
-	
-	job, err := s.jobDataRepo.FindByEncryptId(encryptId)
+
+	count, err := s.jobRepo.CountByCondition("platform = ? AND platform_job_id = ?", model.PLATFORM_BOSS, encryptId)
 	if err != nil {
 		return false, err
 	}
-	return job != nil, nil
+	return count > 0, nil
 }
 
-// InsertBossJob 插入职位数据
-func (s *BossService) InsertBossJob(job *model.BossJobDataEntity) error {
-	now := time.Now()
-	job.CreatedAt = now
-	job.UpdatedAt = now
-	return s.jobDataRepo.Save(job)
+// contactedStatuses 视为已联系过HR的投递状态
+var contactedStatuses = []string{model.DELIVERY_STATUS_DELIVERED, model.DELIVERY_STATUS_CONTACTED}
+
+// IsBossJobContacted 根据encryptId判断职位是否已投递或已沟通（用于打开详情前跳过，重复运行时保持幂等）
+func (s *BossService) IsBossJobContacted(encryptId string) (bool, error) {
+	if encryptId == "" {
+		return false, nil
+	}
+
+	count, err := s.jobRepo.CountByCondition("platform = ? AND platform_job_id = ? AND delivery_status IN ?",
+		model.PLATFORM_BOSS, encryptId, contactedStatuses)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
-// UpdateDeliveryStatus 更新投递状态
-func (s *BossService) UpdateDeliveryStatus(encryptId, encryptUserId, status string) error {
-	return s.jobDataRepo.UpdateDeliveryStatus(encryptId, encryptUserId, status)
+// IsRecruiterContacted 判断是否已与该招聘者投递或沟通过（Boss上同一HR只有一个会话）
+func (s *BossService) IsRecruiterContacted(encryptUserId string) (bool, error) {
+	if encryptUserId == "" {
+		return false, nil
+	}
+
+	count, err := s.jobRepo.CountByCondition("platform = ? AND platform_user_id = ? AND delivery_status IN ?",
+		model.PLATFORM_BOSS, encryptUserId, contactedStatuses)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
 // ==================== 薪资解析方法 ====================
@@ -682,20 +924,11 @@ func (s *BossService) ParseSalary(salary string) *SalaryInfo {
 
 // GetBossStats 获取统计数据
 func (s *BossService) GetBossStats() (*StatsResponse, error) {
-	return s.GetBossStatsWithFilter(nil, "", "", "", nil, nil, "", false)
+	return s.GetBossStatsWithFilter(&BossJobQuery{})
 }
 
 // GetBossStatsWithFilter 获取统计数据（带筛选条件）
-func (s *BossService) GetBossStatsWithFilter(
-	statuses []string,
-	location string,
-	experience string,
-	degree string,
-	minK *float64,
-	maxK *float64,
-	keyword string,
-	filterHeadhunter bool,
-) (*StatsResponse, error) {
+func (s *BossService) GetBossStatsWithFilter(query *BossJobQuery) (*StatsResponse, error) {
 	resp := &StatsResponse{
 		Kpi: &Kpi{},
 		Charts: &Charts{
@@ -708,61 +941,29 @@ func (s *BossService) GetBossStatsWithFilter(
 			SalaryBuckets: []BucketValue{},
 			DailyTrend:   []NameValue{},
 			HrActivity:   []NameValue{},
+			ByTechStack:  []NameValue{},
+			ByWorkMode:   []NameValue{},
+			ByOvertime:   []NameValue{},
+			ByRequiredYears: []NameValue{},
 		},
 	}
 
 	// 构建查询条件
-	wrapper := s.db.Model(&model.BossJobDataEntity{})
-	
-	if len(statuses) > 0 {
-		wrapper = wrapper.Where("delivery_status IN ?", statuses)
-	}
-	if location != "" {
-		wrapper = wrapper.Where("location = ?", location)
-	}
-	if experience != "" {
-		wrapper = wrapper.Where("experience = ?", experience)
-	}
-	if degree != "" {
-		wrapper = wrapper.Where("degree = ?", degree)
-	}
-	if keyword != "" {
-		wrapper = wrapper.Where("company_name LIKE ? OR job_name LIKE ? OR hr_name LIKE ?", 
-			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
-	}
-	if filterHeadhunter {
-		wrapper = wrapper.Where("hr_position IS NULL OR hr_position NOT LIKE ?", "%猎头%")
-	}
+	wrapper := s.buildJobQuery(query)
 
 	// 获取基础数据
-	jobs, err := s.jobDataRepo.FindByWrapper(wrapper)
+	jobs, err := s.jobRepo.FindByWrapper(wrapper)
 	if err != nil {
 		return nil, err
 	}
 
 	// 内存薪资过滤
-	filteredJobs := make([]*model.BossJobDataEntity, 0)
+	filteredJobs := make([]*model.Job, 0)
 	var sumMedian float64
 	var countMedian int64
 
 	for _, job := range jobs {
-		// 薪资过滤
-		passSalary := true
-		if minK != nil || maxK != nil {
-			info := s.ParseSalary(job.Salary)
-			if info == nil || info.MedianK == nil {
-				passSalary = false
-			} else {
-				if minK != nil && *info.MedianK < *minK {
-					passSalary = false
-				}
-				if maxK != nil && *info.MedianK > *maxK {
-					passSalary = false
-				}
-			}
-		}
-
-		if passSalary {
+		if s.matchSalary(job, query) {
 			filteredJobs = append(filteredJobs, job)
 			// 计算平均薪资
 			info := s.ParseSalary(job.Salary)
@@ -785,6 +986,11 @@ func (s *BossService) GetBossStatsWithFilter(
 			resp.Kpi.Filtered++
 		case "投递失败":
 			resp.Kpi.Failed++
+		case model.DELIVERY_STATUS_CONTACTED:
+			resp.Kpi.Contacted++
+		}
+		if job.WhitelistHit != "" {
+			resp.Kpi.Whitelisted++
 		}
 	}
 
@@ -797,22 +1003,22 @@ func (s *BossService) GetBossStatsWithFilter(
 	// 计算图表数据
 	s.calculateCharts(resp.Charts, filteredJobs)
 
+	// 最近运行的AI费用（筛选账号时只统计该账号的运行）
+	aiUsageRepo := s.aiUsageRepo
+	if query != nil && query.AccountId != nil {
+		aiUsageRepo = repository.NewAiUsageRepository(repository.WithAccount(s.db, *query.AccountId))
+	}
+	aiRunCosts, err := aiUsageRepo.SummarizeRecentRuns(AI_RUN_COST_LIMIT)
+	if err != nil {
+		return nil, err
+	}
+	resp.AiRunCosts = aiRunCosts
+
 	return resp, nil
 }
 
 // ListBossJobs 列表查询（分页 + 筛选）
-func (s *BossService) ListBossJobs(
-	statuses []string,
-	location string,
-	experience string,
-	degree string,
-	minK *float64,
-	maxK *float64,
-	keyword string,
-	page int,
-	size int,
-	filterHeadhunter bool,
-) (*PagedResult, error) {
+func (s *BossService) ListBossJobs(query *BossJobQuery, page int, size int) (*PagedResult, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -821,62 +1027,27 @@ func (s *BossService) ListBossJobs(
 	}
 
 	// 构建查询条件
-	wrapper := s.db.Model(&model.BossJobDataEntity{})
-	
-	if len(statuses) > 0 {
-		wrapper = wrapper.Where("delivery_status IN ?", statuses)
-	}
-	if location != "" {
-		wrapper = wrapper.Where("location = ?", location)
-	}
-	if experience != "" {
-		wrapper = wrapper.Where("experience = ?", experience)
-	}
-	if degree != "" {
-		wrapper = wrapper.Where("degree = ?", degree)
-	}
-	if keyword != "" {
-		wrapper = wrapper.Where("company_name LIKE ? OR job_name LIKE ? OR hr_name LIKE ?", 
-			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
-	}
-	if filterHeadhunter {
-		wrapper = wrapper.Where("hr_position IS NULL OR hr_position NOT LIKE ?", "%猎头%")
-	}
+	wrapper := s.buildJobQuery(query)
 
 	wrapper = wrapper.Order("created_at DESC")
 
 	// 获取总数
-	_, err := s.jobDataRepo.CountByWrapper(wrapper)
+	_, err := s.jobRepo.CountByWrapper(wrapper)
 	if err != nil {
 		return nil, err
 	}
 
 	// 分页查询
 	wrapper = wrapper.Offset((page - 1) * size).Limit(size)
-	items, err := s.jobDataRepo.FindByWrapper(wrapper)
+	items, err := s.jobRepo.FindByWrapper(wrapper)
 	if err != nil {
 		return nil, err
 	}
 
 	// 内存薪资过滤
-	filteredItems := make([]*model.BossJobDataEntity, 0)
+	filteredItems := make([]*model.Job, 0)
 	for _, item := range items {
-		passSalary := true
-		if minK != nil || maxK != nil {
-			info := s.ParseSalary(item.Salary)
-			if info == nil || info.MedianK == nil {
-				passSalary = false
-			} else {
-				if minK != nil && *info.MedianK < *minK {
-					passSalary = false
-				}
-				if maxK != nil && *info.MedianK > *maxK {
-					passSalary = false
-				}
-			}
-		}
-
-		if passSalary {
+		if s.matchSalary(item, query) {
 			filteredItems = append(filteredItems, item)
 		}
 	}
@@ -889,6 +1060,87 @@ func (s *BossService) ListBossJobs(
 	}, nil
 }
 
+// buildJobQuery 根据筛选条件构建Boss职位查询（薪资在内存中过滤）
+func (s *BossService) buildJobQuery(query *BossJobQuery) *gorm.DB {
+	wrapper := s.db.Model(&model.Job{}).Where("platform = ?", model.PLATFORM_BOSS)
+	if query == nil {
+		return wrapper
+	}
+
+	if query.AccountId != nil {
+		wrapper = wrapper.Where("account_id = ?", *query.AccountId)
+	}
+	if len(query.Statuses) > 0 {
+		wrapper = wrapper.Where("delivery_status IN ?", query.Statuses)
+	}
+	if query.Location != "" {
+		wrapper = wrapper.Where("city = ?", query.Location)
+	}
+	if query.Experience != "" {
+		wrapper = wrapper.Where("experience = ?", query.Experience)
+	}
+	if query.Degree != "" {
+		wrapper = wrapper.Where("degree = ?", query.Degree)
+	}
+	if query.Keyword != "" {
+		wrapper = wrapper.Where("company_name LIKE ? OR job_name LIKE ? OR recruiter LIKE ?", 
+			"%"+query.Keyword+"%", "%"+query.Keyword+"%", "%"+query.Keyword+"%")
+	}
+	if query.FilterHeadhunter {
+		wrapper = wrapper.Where("recruiter_title IS NULL OR recruiter_title NOT LIKE ?", "%猎头%").
+			Where("recruiter_type IS NULL OR recruiter_type <> ?", model.RECRUITER_TYPE_HEADHUNTER)
+	}
+	if query.ExcludeAgency {
+		wrapper = wrapper.Where("recruiter_type IS NULL OR recruiter_type <> ?", model.RECRUITER_TYPE_OUTSOURCING)
+	}
+	if query.TechStack != "" {
+		wrapper = wrapper.Where("tech_stack LIKE ?", "%"+query.TechStack+"%")
+	}
+	if query.WorkMode != "" {
+		wrapper = wrapper.Where("work_mode = ?", query.WorkMode)
+	}
+	if query.ExcludeOvertime {
+		wrapper = wrapper.Where("overtime_signals IS NULL OR overtime_signals = ''")
+	}
+	if query.ExcludeOutsourcing {
+		wrapper = wrapper.Where("outsourcing IS NULL OR outsourcing = 0")
+	}
+	if query.MaxRequiredYears != nil {
+		wrapper = wrapper.Where("required_years IS NULL OR required_years <= ?", *query.MaxRequiredYears)
+	}
+	if query.MaxInactiveDays != nil {
+		wrapper = wrapper.Where("hr_inactive_days IS NULL OR hr_inactive_days <= ?", *query.MaxInactiveDays)
+	}
+	if query.MaxDistanceKm != nil {
+		wrapper = wrapper.Where("distance_km IS NULL OR distance_km <= ?", *query.MaxDistanceKm)
+	}
+	if query.District != "" {
+		wrapper = wrapper.Where("district LIKE ?", "%"+query.District+"%")
+	}
+	if query.HitKeyword != "" {
+		wrapper = wrapper.Where("hit_keywords LIKE ?", "%"+query.HitKeyword+"%")
+	}
+	return wrapper
+}
+
+// matchSalary 判断岗位薪资中位数是否在筛选区间内
+func (s *BossService) matchSalary(job *model.Job, query *BossJobQuery) bool {
+	if query == nil || (query.MinK == nil && query.MaxK == nil) {
+		return true
+	}
+	info := s.ParseSalary(job.Salary)
+	if info == nil || info.MedianK == nil {
+		return false
+	}
+	if query.MinK != nil && *info.MedianK < *query.MinK {
+		return false
+	}
+	if query.MaxK != nil && *info.MedianK > *query.MaxK {
+		return false
+	}
+	return true
+}
+
 // ReloadBossData 刷新数据
 func (s *BossService) ReloadBossData() (map[string]interface{}, error) {
 	result := make(map[string]interface{})
@@ -916,7 +1168,7 @@ func (s *BossService) ReloadBossData() (map[string]interface{}, error) {
 	}
 
 	// 获取总数
-	total, err := s.jobDataRepo.CountByCondition("1=1")
+	total, err := s.jobRepo.CountByCondition("platform = ?", model.PLATFORM_BOSS)
 	if err != nil {
 		result["success"] = false
 		result["message"] = "刷新失败: " + err.Error()
@@ -940,8 +1192,56 @@ func (s *BossService) nullSafeString(str string) string {
 	return str
 }
 
+// splitCommaList 拆分逗号分隔的列表，忽略空项
+func (s *BossService) splitCommaList(raw string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// requiredYearsBucket 年限要求分段
+func (s *BossService) requiredYearsBucket(years *int) string {
+	switch {
+	case years == nil || *years <= 0:
+		return "不限"
+	case *years < 3:
+		return "1-3年"
+	case *years < 5:
+		return "3-5年"
+	case *years < 10:
+		return "5-10年"
+	default:
+		return "10年以上"
+	}
+}
+
+// DISTANCE_BUCKETS 通勤距离分桶（按距离从近到远）
+var DISTANCE_BUCKETS = []string{"0-5km", "5-10km", "10-20km", "20-30km", ">=30km", "未知"}
+
+// distanceBucket 通勤距离分桶
+func (s *BossService) distanceBucket(km *float64) string {
+	switch {
+	case km == nil:
+		return "未知"
+	case *km < 5:
+		return "0-5km"
+	case *km < 10:
+		return "5-10km"
+	case *km < 20:
+		return "10-20km"
+	case *km < 30:
+		return "20-30km"
+	default:
+		return ">=30km"
+	}
+}
+
 // calculateCharts 计算图表数据
-func (s *BossService) calculateCharts(charts *Charts, jobs []*model.BossJobDataEntity) {
+func (s *BossService) calculateCharts(charts *Charts, jobs []*model.Job) {
 	// 状态统计
 	statusMap := make(map[string]int64)
 	cityMap := make(map[string]int64)
@@ -951,6 +1251,17 @@ func (s *BossService) calculateCharts(charts *Charts, jobs []*model.BossJobDataE
 	degreeMap := make(map[string]int64)
 	dailyMap := make(map[string]int64)
 	hrActivityMap := make(map[string]int64)
+	techStackMap := make(map[string]int64)
+	workModeMap := make(map[string]int64)
+	overtimeMap := make(map[string]int64)
+	requiredYearsMap := make(map[string]int64)
+	whitelistMap := make(map[string]int64)
+	recruiterTypeMap := make(map[string]int64)
+	districtMap := make(map[string]int64)
+	keywordTotal := make(map[string]int64)
+	keywordUnique := make(map[string]int64)
+	keywordPairs := make(map[string]int64)
+	distanceMap := make(map[string]int64)
 
 	// 薪资分桶
 	bucket0_10 := int64(0)
@@ -963,7 +1274,7 @@ func (s *BossService) calculateCharts(charts *Charts, jobs []*model.BossJobDataE
 	for _, job := range jobs {
 		// 状态统计 - 修复：直接传递字符串值，不需要指针
 		statusMap[s.nullSafeString(job.DeliveryStatus)]++
-		cityMap[s.nullSafeString(job.Location)]++
+		cityMap[s.nullSafeString(job.City)]++
 		industryMap[s.nullSafeString(job.Industry)]++
 		companyMap[s.nullSafeString(job.CompanyName)]++
 		experienceMap[s.nullSafeString(job.Experience)]++
@@ -975,9 +1286,61 @@ func (s *BossService) calculateCharts(charts *Charts, jobs []*model.BossJobDataE
 			dailyMap[date]++
 		}
 
-		// HR活跃度统计 - 修复：直接传递字符串值
-		if job.HrActiveStatus != "" {
-			hrActivityMap[s.nullSafeString(job.HrName)]++
+		// HR活跃度统计：按活跃度等级计数（旧数据按活跃描述现场解析）
+		if job.HrActiveStatus != "" || job.HrActivity != "" {
+			activity := job.HrActivity
+			if activity == "" {
+				if parsed, ok := ParseHrActivity(job.HrActiveStatus); ok {
+					activity = parsed.Code
+				}
+			}
+			hrActivityMap[activity]++
+		}
+
+		// AI提取的职位属性统计（仅统计已提取的岗位）
+		if job.JdAttributes != "" {
+			for _, tech := range s.splitCommaList(job.TechStack) {
+				techStackMap[tech]++
+			}
+			workModeMap[s.nullSafeString(job.WorkMode)]++
+			signals := s.splitCommaList(job.OvertimeSignals)
+			if len(signals) == 0 {
+				overtimeMap["无"]++
+			}
+			for _, signal := range signals {
+				overtimeMap[signal]++
+			}
+			requiredYearsMap[s.requiredYearsBucket(job.RequiredYears)]++
+		}
+
+		// 招聘方类型统计（仅统计已分类的岗位）
+		if job.RecruiterType != "" {
+			recruiterTypeMap[job.RecruiterType]++
+		}
+
+		// 通勤距离与商圈统计
+		distanceMap[s.distanceBucket(job.DistanceKm)]++
+		if job.District != "" {
+			districtMap[job.District]++
+		}
+
+		// 搜索关键词命中与两两重叠统计
+		keywords := s.splitCommaList(job.HitKeywords)
+		for i, keyword := range keywords {
+			keywordTotal[keyword]++
+			if len(keywords) == 1 {
+				keywordUnique[keyword]++
+			}
+			for _, other := range keywords[i+1:] {
+				pair := []string{keyword, other}
+				sort.Strings(pair)
+				keywordPairs[pair[0]+" & "+pair[1]]++
+			}
+		}
+
+		// 白名单命中统计
+		if job.WhitelistHit != "" {
+			whitelistMap[job.WhitelistHit]++
 		}
 
 		// 薪资分桶
@@ -1016,7 +1379,20 @@ func (s *BossService) calculateCharts(charts *Charts, jobs []*model.BossJobDataE
 	charts.ByExperience = s.mapToNameValueSlice(experienceMap)
 	charts.ByDegree = s.mapToNameValueSlice(degreeMap)
 	charts.DailyTrend = s.mapToNameValueSlice(dailyMap)
-	charts.HrActivity = s.mapToNameValueSlice(hrActivityMap)
+	charts.HrActivity = s.hrActivityChart(hrActivityMap)
+	charts.ByTechStack = s.getTop10(techStackMap)
+	charts.ByWorkMode = s.mapToNameValueSlice(workModeMap)
+	charts.ByOvertime = s.mapToNameValueSlice(overtimeMap)
+	charts.ByRequiredYears = s.mapToNameValueSlice(requiredYearsMap)
+	charts.ByWhitelist = s.getTop10(whitelistMap)
+	charts.ByRecruiterType = s.mapToNameValueSlice(recruiterTypeMap)
+	charts.ByDistrict = s.getTop10(districtMap)
+	charts.KeywordHits = s.keywordStats(keywordTotal, keywordUnique)
+	charts.KeywordOverlap = s.getTop10(keywordPairs)
+	charts.DistanceBuckets = make([]BucketValue, 0, len(DISTANCE_BUCKETS))
+	for _, bucket := range DISTANCE_BUCKETS {
+		charts.DistanceBuckets = append(charts.DistanceBuckets, BucketValue{Bucket: bucket, Value: distanceMap[bucket]})
+	}
 
 	// 薪资分桶
 	topEdge := int((maxMedian/5)+1) * 5
@@ -1032,6 +1408,40 @@ func (s *BossService) calculateCharts(charts *Charts, jobs []*model.BossJobDataE
 	}
 }
 
+// keywordStats 按命中岗位数从高到低输出搜索关键词统计
+func (s *BossService) keywordStats(total, unique map[string]int64) []KeywordStat {
+	result := make([]KeywordStat, 0, len(total))
+	for keyword, count := range total {
+		result = append(result, KeywordStat{
+			Keyword:     keyword,
+			Total:       count,
+			Unique:      unique[keyword],
+			OverlapRate: math.Round(float64(count-unique[keyword])/float64(count)*100) / 100,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Keyword < result[j].Keyword
+	})
+	return result
+}
+
+// hrActivityChart 按活跃度从高到低输出HR活跃度统计，无法识别的归为“未知”
+func (s *BossService) hrActivityChart(m map[string]int64) []NameValue {
+	result := make([]NameValue, 0, len(HrActivityLevels)+1)
+	for _, activity := range HrActivityLevels {
+		if count := m[activity.Code]; count > 0 {
+			result = append(result, NameValue{Name: activity.Label, Value: count})
+		}
+	}
+	if count := m[""]; count > 0 {
+		result = append(result, NameValue{Name: "未知", Value: count})
+	}
+	return result
+}
+
 // mapToNameValueSlice 将map转换为NameValue切片
 func (s *BossService) mapToNameValueSlice(m map[string]int64) []NameValue {
 	result := make([]NameValue, 0, len(m))
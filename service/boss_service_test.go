@@ -0,0 +1,128 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"get_jobs_go/model"
+	"get_jobs_go/repository"
+
+	"gorm.io/gorm"
+)
+
+// newTestBossService 基于指定会话（账号会话或不区分账号的全局会话）创建只用于职位查询与统计的Boss服务
+func newTestBossService(db *gorm.DB) *BossService {
+	return NewBossService(nil, nil, nil, nil, nil,
+		repository.NewJobRepository(db), repository.NewAiUsageRepository(db), db)
+}
+
+// jobIds 职位的平台职位ID（排序后拼接）
+func jobIds(jobs []*model.Job) string {
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.PlatformJobId)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestBossJobQueryAccountIsolation(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+	for _, job := range []*model.Job{
+		{AccountId: 1, PlatformJobId: "a1-go", CompanyName: "腾讯", JobName: "Go开发", Recruiter: "张三", DeliveryStatus: model.DELIVERY_STATUS_DELIVERED},
+		{AccountId: 1, PlatformJobId: "a1-java", CompanyName: "阿里巴巴", JobName: "Java开发", DeliveryStatus: model.DELIVERY_STATUS_PENDING},
+		{AccountId: 2, PlatformJobId: "a2-go", CompanyName: "字节跳动", JobName: "Go开发", Recruiter: "李四", DeliveryStatus: model.DELIVERY_STATUS_DELIVERED},
+		{AccountId: 2, PlatformJobId: "a2-head", CompanyName: "某猎头", JobName: "Go开发", RecruiterTitle: "猎头顾问", DeliveryStatus: model.DELIVERY_STATUS_PENDING},
+	} {
+		job.Platform = model.PLATFORM_BOSS
+		job.CreatedAt = now
+		job.UpdatedAt = now
+		if err := db.Create(job).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	account := func(id int64) *int64 { return &id }
+	global := newTestBossService(db)
+	scoped := newTestBossService(repository.WithAccount(db, 1))
+
+	tests := []struct {
+		name    string
+		service *BossService
+		query   *BossJobQuery
+		want    string
+	}{
+		{
+			// 全局统计未指定账号时汇总全部账号（仅跨账号统计使用）
+			name:    "全局会话未指定账号",
+			service: global,
+			query:   &BossJobQuery{},
+			want:    "a1-go,a1-java,a2-go,a2-head",
+		},
+		{
+			name:    "全局会话指定账号",
+			service: global,
+			query:   &BossJobQuery{AccountId: account(1)},
+			want:    "a1-go,a1-java",
+		},
+		{
+			// 关键词与猎头过滤含 OR 条件，不能绕过账号条件
+			name:    "全局会话指定账号且含 OR 条件",
+			service: global,
+			query:   &BossJobQuery{AccountId: account(2), Keyword: "Go", FilterHeadhunter: true},
+			want:    "a2-go",
+		},
+		{
+			// 账号会话（按账号查询的调用方）未指定账号时只返回本账号的职位
+			name:    "账号会话未指定账号",
+			service: scoped,
+			query:   &BossJobQuery{},
+			want:    "a1-go,a1-java",
+		},
+		{
+			name:    "账号会话未指定账号且含 OR 条件",
+			service: scoped,
+			query:   &BossJobQuery{Keyword: "Go", FilterHeadhunter: true},
+			want:    "a1-go",
+		},
+		{
+			name:    "账号会话指定其他账号",
+			service: scoped,
+			query:   &BossJobQuery{AccountId: account(2)},
+			want:    "",
+		},
+		{
+			name:    "账号会话查询条件为空",
+			service: scoped,
+			query:   nil,
+			want:    "a1-go,a1-java",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tt.service.ListBossJobs(tt.query, 1, 20)
+			if err != nil {
+				t.Fatalf("ListBossJobs: %v", err)
+			}
+			if got := jobIds(page.Items); got != tt.want {
+				t.Errorf("列表 = %q, 期望 %q", got, tt.want)
+			}
+
+			stats, err := tt.service.GetBossStatsWithFilter(tt.query)
+			if err != nil {
+				t.Fatalf("GetBossStatsWithFilter: %v", err)
+			}
+			want := 0
+			if tt.want != "" {
+				want = len(strings.Split(tt.want, ","))
+			}
+			if stats.Kpi.Total != int64(want) {
+				t.Errorf("统计总数 = %d, 期望 %d", stats.Kpi.Total, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"get_jobs_go/config"
+	"get_jobs_go/model"
+	"get_jobs_go/utils"
+	"math"
+	"strings"
+)
+
+// CommuteFilter 通勤过滤器：按岗位坐标计算到最近通勤点的距离，并按最大通勤半径与商圈过滤
+type CommuteFilter struct {
+	points    []config.CommutePoint
+	maxKm     float64
+	districts []string
+}
+
+// NewCommuteFilter 创建通勤过滤器，坐标无效的通勤点会被忽略
+func NewCommuteFilter(points []config.CommutePoint, maxKm float64, districts []string) *CommuteFilter {
+	valid := make([]config.CommutePoint, 0, len(points))
+	for _, point := range points {
+		if utils.ValidCoordinate(point.Latitude, point.Longitude) {
+			valid = append(valid, point)
+		}
+	}
+	return &CommuteFilter{
+		points:    valid,
+		maxKm:     maxKm,
+		districts: normalizeKeywords(districts),
+	}
+}
+
+// Locate 计算岗位到最近通勤点的距离（保留一位小数），岗位缺少坐标或未配置通勤点时不处理
+func (f *CommuteFilter) Locate(job *model.Job) {
+	if len(f.points) == 0 || job.Latitude == nil || job.Longitude == nil ||
+		!utils.ValidCoordinate(*job.Latitude, *job.Longitude) {
+		return
+	}
+
+	nearest := -1.0
+	for _, point := range f.points {
+		distance := utils.HaversineKm(point.Latitude, point.Longitude, *job.Latitude, *job.Longitude)
+		if nearest < 0 || distance < nearest {
+			nearest = distance
+			job.CommutePoint = point.Name
+		}
+	}
+	nearest = math.Round(nearest*10) / 10
+	job.DistanceKm = &nearest
+}
+
+// Check 按最大通勤半径与商圈检查岗位，返回过滤原因（为空表示不过滤）
+// 距离未知的岗位不按通勤半径过滤；未配置商圈时不按商圈过滤
+func (f *CommuteFilter) Check(job *model.Job) string {
+	if f.maxKm > 0 && job.DistanceKm != nil && *job.DistanceKm > f.maxKm {
+		return model.FILTER_REASON_COMMUTE
+	}
+	if len(f.districts) > 0 &&
+		matchAnyKeyword([]string{utils.NormalizeText(job.District), utils.NormalizeText(job.Address)}, f.districts) == "" {
+		return model.FILTER_REASON_DISTRICT
+	}
+	return ""
+}
+
+// JoinDistrict 拼接区县与商圈，如 “南山区·科技园”
+func JoinDistrict(area, business string) string {
+	var parts []string
+	for _, part := range []string{area, business} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "·")
+}
@@ -82,29 +82,52 @@ func (s *ConfigService) RequireConfigValue(configKey string) (string, error) {
 	return value, nil
 }
 
-// GetAiConfigs 获取AI调用所需的基础配置（BASE_URL, API_KEY, MODEL）
+// GetAiConfigs 获取AI调用所需的基础配置（PROVIDER, BASE_URL, API_KEY, MODEL, API_VERSION）
 func (s *ConfigService) GetAiConfigs() (map[string]string, error) {
 	result := make(map[string]string)
-	
+
+	// 服务提供方必须显式配置，未配置或不支持时返回列出可选值的配置错误
+	provider, err := s.GetConfigValue("PROVIDER")
+	if err != nil {
+		return nil, err
+	}
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if err := ValidateAiProvider(provider); err != nil {
+		return nil, err
+	}
+
 	baseUrl, err := s.RequireConfigValue("BASE_URL")
 	if err != nil {
 		return nil, err
 	}
-	
-	apiKey, err := s.RequireConfigValue("API_KEY")
+
+	// 本地 Ollama 服务无需密钥
+	var apiKey string
+	if provider == AI_PROVIDER_OLLAMA {
+		apiKey, err = s.GetConfigValue("API_KEY")
+	} else {
+		apiKey, err = s.RequireConfigValue("API_KEY")
+	}
 	if err != nil {
 		return nil, err
 	}
-	
+
 	model, err := s.RequireConfigValue("MODEL")
 	if err != nil {
 		return nil, err
 	}
-	
+
+	apiVersion, err := s.GetConfigValue("API_VERSION")
+	if err != nil {
+		return nil, err
+	}
+
+	result["PROVIDER"] = provider
 	result["BASE_URL"] = baseUrl
 	result["API_KEY"] = apiKey
 	result["MODEL"] = model
-	
+	result["API_VERSION"] = apiVersion
+
 	return result, nil
 }
 
@@ -179,6 +202,15 @@ func (s *ConfigService) GetBossConfig() (*config.BossConfig, error) {
 	return s.bossService.LoadBossConfig()
 }
 
+// HasBossConfig 数据库中是否保存了Boss配置（账号隔离的会话中表示该账号是否有自己的配置）
+func (s *ConfigService) HasBossConfig() (bool, error) {
+	entity, err := s.bossService.GetFirstConfig()
+	if err != nil {
+		return false, err
+	}
+	return entity != nil, nil
+}
+
 // 其他平台的配置获取方法可以后续添加
 /*
 func (s *ConfigService) GetLiepinConfig() (*config.LiepinConfig, error) {
@@ -197,8 +229,12 @@ func (s *ConfigService) GetJob51Config() (*config.Job51Config, error) {
 // ConfigRequiredError 配置缺失错误
 type ConfigRequiredError struct {
 	ConfigKey string
+	Options   []string // 可选值（为空时不提示）
 }
 
 func (e *ConfigRequiredError) Error() string {
+	if len(e.Options) > 0 {
+		return "缺少必要配置: " + e.ConfigKey + "（可选值: " + strings.Join(e.Options, ", ") + "）"
+	}
 	return "缺少必要配置: " + e.ConfigKey
 }
\ No newline at end of file
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"fmt"
+	"get_jobs_go/config"
+	"get_jobs_go/model"
+	"get_jobs_go/repository"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DEFAULT_RULE_TEST_LIMIT 规则试运行默认扫描的职位数量
+const DEFAULT_RULE_TEST_LIMIT = 500
+
+// FilterRule 编译后的过滤规则
+type FilterRule struct {
+	Name       string
+	Expression *RuleExpression
+	Action     string
+	Priority   int
+	Tag        string
+}
+
+// RuleOutcome 规则执行结果
+type RuleOutcome struct {
+	SkipRule string   // 命中的 skip 规则名称（为空表示不跳过）
+	Priority int      // 命中的 priority 规则累计的优先级
+	Tags     []string // 命中的 tag 规则打的标签
+	Matched  []string // 命中的规则名称
+}
+
+// RuleTestResult 规则试运行结果
+type RuleTestResult struct {
+	Expression string       `json:"expression"`
+	Fields     []string     `json:"fields"`
+	Scanned    int          `json:"scanned"`
+	Matched    int          `json:"matched"`
+	Jobs       []*model.Job `json:"jobs"`      // 命中的职位
+	Unmatched  []*model.Job `json:"unmatched"` // 未命中的职位
+	Errors     []string     `json:"errors"`
+}
+
+// FilterRuleService 自定义过滤规则服务：规则的维护、编译、执行与试运行
+type FilterRuleService struct {
+	ruleRepo    repository.FilterRuleRepository
+	jobRepo     repository.JobRepository
+	bossService *BossService
+}
+
+func NewFilterRuleService(
+	ruleRepo repository.FilterRuleRepository,
+	jobRepo repository.JobRepository,
+	bossService *BossService,
+) *FilterRuleService {
+	return &FilterRuleService{
+		ruleRepo:    ruleRepo,
+		jobRepo:     jobRepo,
+		bossService: bossService,
+	}
+}
+
+// GetAllRules 获取数据库中的全部规则
+func (s *FilterRuleService) GetAllRules() ([]*model.FilterRuleEntity, error) {
+	return s.ruleRepo.FindAll()
+}
+
+// SaveRule 校验并保存规则（ID 为 0 时新建）
+func (s *FilterRuleService) SaveRule(rule *model.FilterRuleEntity) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return fmt.Errorf("规则名称不能为空")
+	}
+	if err := validateRuleAction(rule.Action); err != nil {
+		return err
+	}
+	if _, err := CompileRuleExpression(rule.Expression); err != nil {
+		return err
+	}
+
+	existing, err := s.ruleRepo.FindByName(rule.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != rule.ID {
+		return fmt.Errorf("规则名称已存在: %s", rule.Name)
+	}
+
+	rule.UpdatedAt = time.Now()
+	if rule.ID == 0 {
+		rule.CreatedAt = rule.UpdatedAt
+		return s.ruleRepo.Save(rule)
+	}
+	return s.ruleRepo.Update(rule)
+}
+
+// DeleteRule 删除规则
+func (s *FilterRuleService) DeleteRule(id int64) error {
+	return s.ruleRepo.Delete(id)
+}
+
+// LoadRules 加载本次运行的规则：内置规则（由配置开关生成）、配置文件规则、数据库中启用的规则，无效规则记录日志后忽略
+func (s *FilterRuleService) LoadRules(cfg *config.BossConfig) ([]*FilterRule, error) {
+	var sources []config.FilterRule
+	if cfg.FilterDeadHR {
+		sources = append(sources, config.FilterRule{
+			Name:       model.FILTER_REASON_DEAD_HR,
+			Expression: deadHrExpression(cfg.DeadStatus, cfg.MaxInactiveDays),
+			Action:     model.RULE_ACTION_SKIP,
+		})
+	}
+	sources = append(sources, cfg.FilterRules...)
+
+	entities, err := s.ruleRepo.FindEnabled()
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range entities {
+		sources = append(sources, config.FilterRule{
+			Name:       entity.Name,
+			Expression: entity.Expression,
+			Action:     entity.Action,
+			Priority:   entity.Priority,
+			Tag:        entity.Tag,
+		})
+	}
+
+	rules := make([]*FilterRule, 0, len(sources))
+	for _, source := range sources {
+		rule, err := compileFilterRule(source)
+		if err != nil {
+			log.Printf("过滤规则无效，已忽略 | 规则：%s | 错误：%v", source.Name, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ApplyRules 按顺序对岗位执行规则，afterAi 为 false 时只执行不依赖AI字段的规则，为 true 时只执行依赖AI字段的规则
+// 命中 skip 规则后停止执行后续规则；命中的优先级与标签会累加到岗位上
+func (s *FilterRuleService) ApplyRules(rules []*FilterRule, job *model.Job, afterAi bool) *RuleOutcome {
+	outcome := &RuleOutcome{}
+	if len(rules) == 0 {
+		return outcome
+	}
+
+	env := NewRuleEnv(job, s.bossService.ParseSalary(job.Salary))
+	for _, rule := range rules {
+		if rule.Expression.NeedsAi() != afterAi {
+			continue
+		}
+
+		matched, err := rule.Expression.Eval(env)
+		if err != nil {
+			log.Printf("过滤规则执行失败 | 规则：%s | 岗位：%s | 错误：%v", rule.Name, job.JobName, err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+
+		outcome.Matched = append(outcome.Matched, rule.Name)
+		switch rule.Action {
+		case model.RULE_ACTION_SKIP:
+			outcome.SkipRule = rule.Name
+			return outcome
+		case model.RULE_ACTION_PRIORITY:
+			outcome.Priority += rule.Priority
+			job.Priority += rule.Priority
+		case model.RULE_ACTION_TAG:
+			outcome.Tags = append(outcome.Tags, rule.Tag)
+			job.Tags = appendTag(job.Tags, rule.Tag)
+		}
+	}
+	return outcome
+}
+
+// TestRule 对数据库中最近的职位试运行规则表达式，返回命中与未命中的职位
+func (s *FilterRuleService) TestRule(expression string, limit int) (*RuleTestResult, error) {
+	compiled, err := CompileRuleExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DEFAULT_RULE_TEST_LIMIT
+	}
+
+	jobs, err := s.jobRepo.FindRecent(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RuleTestResult{
+		Expression: expression,
+		Fields:     compiled.Fields(),
+		Scanned:    len(jobs),
+		Jobs:       make([]*model.Job, 0),
+		Unmatched:  make([]*model.Job, 0),
+		Errors:     make([]string, 0),
+	}
+	for _, job := range jobs {
+		matched, err := compiled.Eval(NewRuleEnv(job, s.bossService.ParseSalary(job.Salary)))
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("职位%d（%s）: %v", job.ID, job.JobName, err))
+			continue
+		}
+		if matched {
+			result.Jobs = append(result.Jobs, job)
+		} else {
+			result.Unmatched = append(result.Unmatched, job)
+		}
+	}
+	result.Matched = len(result.Jobs)
+	return result, nil
+}
+
+// compileFilterRule 校验并编译单条规则
+func compileFilterRule(source config.FilterRule) (*FilterRule, error) {
+	name := strings.TrimSpace(source.Name)
+	if name == "" {
+		return nil, fmt.Errorf("规则名称不能为空")
+	}
+	if err := validateRuleAction(source.Action); err != nil {
+		return nil, err
+	}
+	expression, err := CompileRuleExpression(source.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := strings.TrimSpace(source.Tag)
+	if tag == "" {
+		tag = name
+	}
+	return &FilterRule{
+		Name:       name,
+		Expression: expression,
+		Action:     source.Action,
+		Priority:   source.Priority,
+		Tag:        tag,
+	}, nil
+}
+
+// deadHrExpression 根据 deadStatus 与 maxInactiveDays 生成HR活跃过滤规则表达式，两者都未配置时使用默认最长不活跃天数
+func deadHrExpression(deadStatus []string, maxInactiveDays int) string {
+	var codes []string
+	for _, status := range deadStatus {
+		activity, ok := ParseHrActivity(status)
+		if !ok {
+			if strings.TrimSpace(status) != "" {
+				log.Printf("无法识别的HR活跃状态，已忽略: %s", status)
+			}
+			continue
+		}
+		codes = append(codes, strconv.Quote(activity.Code))
+	}
+	if len(codes) == 0 && maxInactiveDays <= 0 {
+		maxInactiveDays = DEFAULT_HR_MAX_INACTIVE_DAYS
+	}
+
+	var conditions []string
+	if len(codes) > 0 {
+		conditions = append(conditions, "hrActivity in ["+strings.Join(codes, ", ")+"]")
+	}
+	if maxInactiveDays > 0 {
+		conditions = append(conditions, "hrActiveDays > "+strconv.Itoa(maxInactiveDays))
+	}
+	return strings.Join(conditions, " || ")
+}
+
+// validateRuleAction 校验规则动作
+func validateRuleAction(action string) error {
+	switch action {
+	case model.RULE_ACTION_SKIP, model.RULE_ACTION_PRIORITY, model.RULE_ACTION_TAG:
+		return nil
+	}
+	return fmt.Errorf("不支持的规则动作: %s（可选 skip / priority / tag）", action)
+}
+
+// appendTag 向逗号分隔的标签列表追加标签（已存在时不重复追加）
+func appendTag(tags, tag string) string {
+	if tags == "" {
+		return tag
+	}
+	for _, existing := range strings.Split(tags, ",") {
+		if existing == tag {
+			return tags
+		}
+	}
+	return tags + "," + tag
+}
+
+// mergeTags 合并两个逗号分隔的列表（去重并保持先后顺序）
+func mergeTags(tags, more string) string {
+	for _, tag := range strings.Split(more, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = appendTag(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"get_jobs_go/model"
+	"get_jobs_go/repository"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB 打开迁移完成的内存数据库
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存数据库随连接关闭而销毁，只使用一个连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := repository.RegisterAccountScope(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := repository.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// hrActivityEnv 按抓取岗位时的方式解析HR活跃描述并构建求值环境
+func hrActivityEnv(status string) RuleEnv {
+	job := &model.Job{HrActiveStatus: status}
+	if activity, ok := ParseHrActivity(status); ok {
+		days := activity.Days
+		job.HrActivity = activity.Code
+		job.HrInactiveDays = &days
+	}
+	return NewRuleEnv(job, nil)
+}
+
+func TestDeadHrExpression(t *testing.T) {
+	statuses := []string{"在线", "刚刚活跃", "今日活跃", "3日内活跃", "本周活跃", "2周内活跃", "本月活跃", "2月内活跃", "近半年活跃", "半年前活跃", "1年前活跃", ""}
+
+	tests := []struct {
+		name            string
+		deadStatus      []string
+		maxInactiveDays int
+		want            string
+		skipped         []string
+	}{
+		{
+			name:       "仅配置活跃状态",
+			deadStatus: []string{"半年前活跃", "本月活跃", "无法识别"},
+			want:       `hrActivity in ["over_half_year", "this_month"]`,
+			skipped:    []string{"本月活跃", "半年前活跃", "1年前活跃"},
+		},
+		{
+			name:            "仅配置最长不活跃天数",
+			maxInactiveDays: 7,
+			want:            `hrActiveDays > 7`,
+			skipped:         []string{"2周内活跃", "本月活跃", "2月内活跃", "近半年活跃", "半年前活跃", "1年前活跃"},
+		},
+		{
+			name:            "同时配置",
+			deadStatus:      []string{"over_half_year"},
+			maxInactiveDays: 3,
+			want:            `hrActivity in ["over_half_year"] || hrActiveDays > 3`,
+			skipped:         []string{"本周活跃", "2周内活跃", "本月活跃", "2月内活跃", "近半年活跃", "半年前活跃", "1年前活跃"},
+		},
+		{
+			// 比早期按“年”字过滤更严格：“2月内活跃”也会被过滤
+			name:    "均未配置时使用默认天数",
+			want:    `hrActiveDays > 30`,
+			skipped: []string{"2月内活跃", "近半年活跃", "半年前活跃", "1年前活跃"},
+		},
+		{
+			name:       "活跃状态均无法识别时使用默认天数",
+			deadStatus: []string{"无法识别", " "},
+			want:       `hrActiveDays > 30`,
+			skipped:    []string{"2月内活跃", "近半年活跃", "半年前活跃", "1年前活跃"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := deadHrExpression(tt.deadStatus, tt.maxInactiveDays)
+			if source != tt.want {
+				t.Fatalf("deadHrExpression = %s, 期望 %s", source, tt.want)
+			}
+			expr, err := CompileRuleExpression(source)
+			if err != nil {
+				t.Fatalf("CompileRuleExpression(%q): %v", source, err)
+			}
+
+			skipped := make(map[string]bool)
+			for _, status := range tt.skipped {
+				skipped[status] = true
+			}
+			for _, status := range statuses {
+				got, err := expr.Eval(hrActivityEnv(status))
+				if err != nil {
+					t.Fatalf("Eval(%q): %v", status, err)
+				}
+				if got != skipped[status] {
+					t.Errorf("活跃状态 %q 过滤结果 = %v, 期望 %v", status, got, skipped[status])
+				}
+			}
+		})
+	}
+}
+
+func TestDeadHrExpressionMatchesBaseline(t *testing.T) {
+	// 对各等级的标准描述，默认规则与早期 strings.Contains(status, "年") 的过滤结果一致
+	// （按月计算的描述如“2月内活跃”除外，见 TestDeadHrExpression）
+	expr, err := CompileRuleExpression(deadHrExpression(nil, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, activity := range HrActivityLevels {
+		got, err := expr.Eval(hrActivityEnv(activity.Label))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := strings.Contains(activity.Label, "年"); got != want {
+			t.Errorf("活跃状态 %q 过滤结果 = %v, 早期行为为 %v", activity.Label, got, want)
+		}
+	}
+}
+
+func TestFilterRuleServiceTestRule(t *testing.T) {
+	db := openTestDB(t)
+	jobRepo := repository.NewJobRepository(db)
+	for _, job := range []*model.Job{
+		{Platform: model.PLATFORM_BOSS, PlatformJobId: "job-1", CompanyName: "腾讯", JobName: "后端开发", Salary: "15-18K"},
+		{Platform: model.PLATFORM_BOSS, PlatformJobId: "job-2", CompanyName: "阿里巴巴", JobName: "Go开发", Salary: "30-50K"},
+		{Platform: model.PLATFORM_BOSS, PlatformJobId: "job-3", CompanyName: "某外包", JobName: "测试", Salary: "面议"},
+	} {
+		if err := jobRepo.Save(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ruleService := NewFilterRuleService(repository.NewFilterRuleRepository(db), jobRepo, &BossService{})
+
+	result, err := ruleService.TestRule(`salaryMax < 20`, 0)
+	if err != nil {
+		t.Fatalf("TestRule: %v", err)
+	}
+	if result.Scanned != 3 || result.Matched != 1 || len(result.Jobs) != 1 || result.Jobs[0].PlatformJobId != "job-1" {
+		t.Errorf("命中 = %d/%d, 期望只命中 job-1", result.Matched, result.Scanned)
+	}
+	// 未命中的职位按ID倒序返回，未解析出薪资的职位不命中薪资条件
+	var unmatched []string
+	for _, job := range result.Unmatched {
+		unmatched = append(unmatched, job.PlatformJobId)
+	}
+	if strings.Join(unmatched, ",") != "job-3,job-2" || len(result.Errors) != 0 {
+		t.Errorf("未命中 = %v, 求值失败 = %v, 期望未命中 [job-3 job-2]", unmatched, result.Errors)
+	}
+
+	if _, err := ruleService.TestRule(`salaryMax <`, 0); err == nil {
+		t.Error("无效表达式应返回错误")
+	}
+}
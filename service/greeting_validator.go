@@ -0,0 +1,163 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 打招呼语校验失败原因
+const (
+	GREETING_REJECT_EMPTY         = "empty"         // 内容为空
+	GREETING_REJECT_VETO          = "ai_veto"       // AI认为不适合投递（回复包含 false）
+	GREETING_REJECT_TOO_LONG      = "too_long"      // 超出字数上限
+	GREETING_REJECT_LANGUAGE      = "language"      // 非中文
+	GREETING_REJECT_BANNED_PHRASE = "banned_phrase" // 包含禁用语
+	GREETING_REJECT_PII           = "pii"           // 包含手机号、邮箱等个人信息
+	GREETING_REJECT_PROMPT_ECHO   = "prompt_echo"   // 复述了提示词
+	GREETING_REJECT_AI_ERROR      = "ai_error"      // AI调用失败
+)
+
+const (
+	// GREETING_MIN_CJK_RATIO 打招呼语中汉字占文字字符的最低比例
+	GREETING_MIN_CJK_RATIO = 0.4
+	// GREETING_MAX_PROMPT_SIMILARITY 打招呼语与提示词的最高相似度（字符二元组重合比例）
+	GREETING_MAX_PROMPT_SIMILARITY = 0.8
+)
+
+// DefaultGreetingBannedPhrases 默认禁用语，命中说明模型在解释自身或拒绝回答
+var DefaultGreetingBannedPhrases = []string{
+	"作为AI", "作为一个AI", "AI助手", "人工智能助手", "语言模型", "as an ai", "抱歉", "无法生成", "打招呼语：",
+}
+
+var (
+	greetingMarkdownLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	greetingMarkdownMarkRegex = regexp.MustCompile("(\\*\\*|__|\\*|`|~~)")
+	greetingMarkdownLineRegex = regexp.MustCompile(`(?m)^\s*(#{1,6}\s+|>\s*|[-*+]\s+|\d+\.\s+)`)
+	greetingWhitespaceRegex   = regexp.MustCompile(`\s+`)
+	greetingPhoneRegex        = regexp.MustCompile(`(?:\+?86[-\s]?)?1[3-9]\d{9}`)
+	greetingEmailRegex        = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	greetingIdCardRegex       = regexp.MustCompile(`\d{17}[\dXx]`)
+	greetingContactRegex      = regexp.MustCompile(`(?i)(微信|vx|wechat|qq)\s*[:：号]?\s*[A-Za-z0-9_-]{5,}`)
+	greetingSurroundingQuotes = "\"'“”‘’「」『』《》`"
+)
+
+// GreetingValidator AI打招呼语校验器
+type GreetingValidator struct {
+	MaxLength     int      // 字数上限
+	BannedPhrases []string // 禁用语
+}
+
+// NewGreetingValidator 创建打招呼语校验器，额外禁用语会追加到默认禁用语之后
+func NewGreetingValidator(maxLength int, bannedPhrases []string) *GreetingValidator {
+	if maxLength <= 0 {
+		maxLength = DEFAULT_GREETING_MAX_LENGTH
+	}
+	phrases := append([]string{}, DefaultGreetingBannedPhrases...)
+	for _, phrase := range bannedPhrases {
+		if phrase = strings.TrimSpace(phrase); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+	return &GreetingValidator{MaxLength: maxLength, BannedPhrases: phrases}
+}
+
+// Validate 清理并校验AI生成的打招呼语，返回清理后的内容和失败原因（为空表示通过）
+// allowed 为允许打招呼语引用的提示词片段（如个人介绍、参考语），计算与提示词相似度时会先从提示词中去除
+func (v *GreetingValidator) Validate(greeting, prompt string, allowed ...string) (string, string) {
+	if strings.Contains(strings.ToLower(greeting), "false") {
+		return "", GREETING_REJECT_VETO
+	}
+
+	cleaned := CleanGreeting(greeting)
+	if cleaned == "" {
+		return "", GREETING_REJECT_EMPTY
+	}
+	if utf8.RuneCountInString(cleaned) > v.MaxLength {
+		return cleaned, GREETING_REJECT_TOO_LONG
+	}
+	if cjkRatio(cleaned) < GREETING_MIN_CJK_RATIO {
+		return cleaned, GREETING_REJECT_LANGUAGE
+	}
+
+	lower := strings.ToLower(cleaned)
+	for _, phrase := range v.BannedPhrases {
+		if strings.Contains(lower, strings.ToLower(phrase)) {
+			return cleaned, GREETING_REJECT_BANNED_PHRASE
+		}
+	}
+
+	if greetingPhoneRegex.MatchString(cleaned) || greetingEmailRegex.MatchString(cleaned) ||
+		greetingIdCardRegex.MatchString(cleaned) || greetingContactRegex.MatchString(cleaned) {
+		return cleaned, GREETING_REJECT_PII
+	}
+
+	for _, fragment := range allowed {
+		if fragment != "" {
+			prompt = strings.ReplaceAll(prompt, fragment, "")
+		}
+	}
+	if promptSimilarity(cleaned, prompt) > GREETING_MAX_PROMPT_SIMILARITY {
+		return cleaned, GREETING_REJECT_PROMPT_ECHO
+	}
+	return cleaned, ""
+}
+
+// CleanGreeting 去除Markdown标记、首尾引号并合并为单行
+func CleanGreeting(greeting string) string {
+	text := greetingMarkdownLinkRegex.ReplaceAllString(greeting, "$1")
+	text = greetingMarkdownLineRegex.ReplaceAllString(text, "")
+	text = greetingMarkdownMarkRegex.ReplaceAllString(text, "")
+	text = greetingWhitespaceRegex.ReplaceAllString(strings.TrimSpace(text), " ")
+
+	// 反复去除成对包裹的引号
+	for {
+		trimmed := strings.TrimSpace(strings.Trim(text, greetingSurroundingQuotes))
+		if trimmed == text {
+			return text
+		}
+		text = trimmed
+	}
+}
+
+// cjkRatio 汉字占文字字符（汉字与字母）的比例
+func cjkRatio(text string) float64 {
+	var cjk, letters int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			cjk++
+			letters++
+		case unicode.IsLetter(r):
+			letters++
+		}
+	}
+	if letters == 0 {
+		return 0
+	}
+	return float64(cjk) / float64(letters)
+}
+
+// promptSimilarity 打招呼语中的字符二元组出现在提示词中的比例
+func promptSimilarity(greeting, prompt string) float64 {
+	greetingRunes := []rune(greetingWhitespaceRegex.ReplaceAllString(greeting, ""))
+	if len(greetingRunes) < 2 || prompt == "" {
+		return 0
+	}
+
+	promptRunes := []rune(greetingWhitespaceRegex.ReplaceAllString(prompt, ""))
+	promptBigrams := make(map[string]bool, len(promptRunes))
+	for i := 0; i+1 < len(promptRunes); i++ {
+		promptBigrams[string(promptRunes[i:i+2])] = true
+	}
+
+	matched := 0
+	total := len(greetingRunes) - 1
+	for i := 0; i < total; i++ {
+		if promptBigrams[string(greetingRunes[i:i+2])] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(total)
+}
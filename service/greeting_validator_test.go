@@ -0,0 +1,114 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanGreeting(t *testing.T) {
+	tests := []struct {
+		name     string
+		greeting string
+		want     string
+	}{
+		{"无需清理", "您好，期待与您沟通", "您好，期待与您沟通"},
+		{"加粗与行内代码", "**您好**，我熟悉`Go`与__K8s__", "您好，我熟悉Go与K8s"},
+		{"链接保留文字", "您好，我对[贵司](https://example.com)很感兴趣", "您好，我对贵司很感兴趣"},
+		{"标题、列表与引用合并为单行", "# 您好\n- 我有七年经验\n> 期待回复", "您好 我有七年经验 期待回复"},
+		{"有序列表", "1. 您好\n2. 期待回复", "您好 期待回复"},
+		{"首尾引号", "\"您好，期待回复\"", "您好，期待回复"},
+		{"多层中文引号", "“「您好，期待回复」”", "您好，期待回复"},
+		{"引号内的空白", "  『 您好 』  ", "您好"},
+		{"只有标记", "** **", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanGreeting(tt.greeting); got != tt.want {
+				t.Errorf("CleanGreeting(%q) = %q, 期望 %q", tt.greeting, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetingValidatorValidate(t *testing.T) {
+	const intro = "我有七年后端开发经验熟悉微服务"
+	prompt := "请根据岗位信息生成打招呼语。岗位是Go开发工程师负责后端服务。我的介绍：" + intro
+
+	tests := []struct {
+		name     string
+		greeting string
+		allowed  []string
+		want     string
+		cleaned  string
+	}{
+		{"通过", "**您好**，我熟悉Go与微服务，期待与您进一步沟通！", nil, "", "您好，我熟悉Go与微服务，期待与您进一步沟通！"},
+		{"AI否决", "False", nil, GREETING_REJECT_VETO, ""},
+		{"AI否决（大小写混合）", "结论：FaLsE", nil, GREETING_REJECT_VETO, ""},
+		{"空内容", "  「」  ", nil, GREETING_REJECT_EMPTY, ""},
+		{"超出字数上限", strings.Repeat("您", 61), nil, GREETING_REJECT_TOO_LONG, strings.Repeat("您", 61)},
+		{"恰好达到字数上限", strings.Repeat("您", 60), nil, "", strings.Repeat("您", 60)},
+		{"非中文", "Hello, I am very interested in this position 您好", nil, GREETING_REJECT_LANGUAGE, "Hello, I am very interested in this position 您好"},
+		{"默认禁用语", "抱歉，我无法为您生成合适的内容", nil, GREETING_REJECT_BANNED_PHRASE, "抱歉，我无法为您生成合适的内容"},
+		{"禁用语忽略大小写", "您好，As An AI 我建议您投递", nil, GREETING_REJECT_BANNED_PHRASE, "您好，As An AI 我建议您投递"},
+		{"自定义禁用语", "您好，我是资深大牛，期待沟通", nil, GREETING_REJECT_BANNED_PHRASE, "您好，我是资深大牛，期待沟通"},
+		{"手机号", "您好，我的电话是13812345678，期待沟通", nil, GREETING_REJECT_PII, "您好，我的电话是13812345678，期待沟通"},
+		{"带区号的手机号", "您好，请致电+86 13812345678，期待沟通", nil, GREETING_REJECT_PII, "您好，请致电+86 13812345678，期待沟通"},
+		{"邮箱", "您好，简历已发至me@example.com，期待回复", nil, GREETING_REJECT_PII, "您好，简历已发至me@example.com，期待回复"},
+		{"微信号", "您好，方便加微信：abc12345详聊吗", nil, GREETING_REJECT_PII, "您好，方便加微信：abc12345详聊吗"},
+		{"身份证号", "您好，我的证件号11010519491231002X请核实", nil, GREETING_REJECT_PII, "您好，我的证件号11010519491231002X请核实"},
+		{"复述提示词", "岗位是Go开发工程师负责后端服务", nil, GREETING_REJECT_PROMPT_ECHO, "岗位是Go开发工程师负责后端服务"},
+		{"复述个人介绍（未声明允许）", intro + "盼复", nil, GREETING_REJECT_PROMPT_ECHO, intro + "盼复"},
+		{"引用允许的个人介绍", intro + "盼复", []string{intro}, "", intro + "盼复"},
+	}
+
+	validator := NewGreetingValidator(0, []string{" 大牛 ", ""})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaned, reason := validator.Validate(tt.greeting, prompt, tt.allowed...)
+			if reason != tt.want {
+				t.Errorf("Validate(%q) 原因 = %q, 期望 %q", tt.greeting, reason, tt.want)
+			}
+			if cleaned != tt.cleaned {
+				t.Errorf("Validate(%q) 内容 = %q, 期望 %q", tt.greeting, cleaned, tt.cleaned)
+			}
+		})
+	}
+}
+
+func TestNewGreetingValidator(t *testing.T) {
+	validator := NewGreetingValidator(0, []string{" 大牛 ", "", "  "})
+	if validator.MaxLength != DEFAULT_GREETING_MAX_LENGTH {
+		t.Errorf("MaxLength = %d, 期望默认值 %d", validator.MaxLength, DEFAULT_GREETING_MAX_LENGTH)
+	}
+	if len(validator.BannedPhrases) != len(DefaultGreetingBannedPhrases)+1 ||
+		validator.BannedPhrases[len(validator.BannedPhrases)-1] != "大牛" {
+		t.Errorf("BannedPhrases = %v, 期望在默认禁用语后追加去除空白的自定义禁用语", validator.BannedPhrases)
+	}
+
+	if _, reason := NewGreetingValidator(5, nil).Validate("您好期待沟通", ""); reason != GREETING_REJECT_TOO_LONG {
+		t.Errorf("自定义字数上限未生效，原因 = %q", reason)
+	}
+}
+
+func TestPromptSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		greeting string
+		prompt   string
+		want     float64
+	}{
+		{"提示词为空", "您好期待", "", 0},
+		{"单个字符", "您", "您好", 0},
+		{"完全复述", "期待沟通", "请写：期待沟通", 1},
+		{"忽略空白", "期待 沟通", "期待沟\n通", 1},
+		{"部分重合", "您好期待", "期待", 1.0 / 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := promptSimilarity(tt.greeting, tt.prompt); got != tt.want {
+				t.Errorf("promptSimilarity = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
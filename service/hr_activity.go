@@ -0,0 +1,116 @@
+package service
+
+import (
+	"get_jobs_go/utils"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HR活跃度（按活跃程度从高到低排列）
+const (
+	HR_ACTIVITY_ONLINE           = "online"
+	HR_ACTIVITY_JUST_NOW         = "just_now"
+	HR_ACTIVITY_TODAY            = "today"
+	HR_ACTIVITY_WITHIN_3_DAYS    = "within_3_days"
+	HR_ACTIVITY_THIS_WEEK        = "this_week"
+	HR_ACTIVITY_WITHIN_2_WEEKS   = "within_2_weeks"
+	HR_ACTIVITY_THIS_MONTH       = "this_month"
+	HR_ACTIVITY_WITHIN_HALF_YEAR = "within_half_year"
+	HR_ACTIVITY_OVER_HALF_YEAR   = "over_half_year"
+)
+
+// DEFAULT_HR_MAX_INACTIVE_DAYS 开启HR活跃过滤但未配置 deadStatus 与 maxInactiveDays 时的默认最长不活跃天数
+// （过滤“近半年活跃”“半年前活跃”以及“2月内活跃”等超过一个月未活跃的描述；
+// 早期只按“年”字过滤，不会过滤“2月内活跃”这类按月计算的描述）
+const DEFAULT_HR_MAX_INACTIVE_DAYS = 30
+
+// HrActivity HR活跃度等级
+type HrActivity struct {
+	Code  string // 等级代码
+	Label string // Boss直聘上的展示文本
+	Level int    // 排序值，越小越活跃
+	Days  int    // 距上次活跃的大致天数（取区间上限）
+}
+
+// HrActivityLevels 全部HR活跃度等级（按 Level 升序）
+var HrActivityLevels = []HrActivity{
+	{Code: HR_ACTIVITY_ONLINE, Label: "在线", Level: 0, Days: 0},
+	{Code: HR_ACTIVITY_JUST_NOW, Label: "刚刚活跃", Level: 1, Days: 0},
+	{Code: HR_ACTIVITY_TODAY, Label: "今日活跃", Level: 2, Days: 1},
+	{Code: HR_ACTIVITY_WITHIN_3_DAYS, Label: "3日内活跃", Level: 3, Days: 3},
+	{Code: HR_ACTIVITY_THIS_WEEK, Label: "本周活跃", Level: 4, Days: 7},
+	{Code: HR_ACTIVITY_WITHIN_2_WEEKS, Label: "2周内活跃", Level: 5, Days: 14},
+	{Code: HR_ACTIVITY_THIS_MONTH, Label: "本月活跃", Level: 6, Days: 30},
+	{Code: HR_ACTIVITY_WITHIN_HALF_YEAR, Label: "近半年活跃", Level: 7, Days: 180},
+	{Code: HR_ACTIVITY_OVER_HALF_YEAR, Label: "半年前活跃", Level: 8, Days: 365},
+}
+
+// hrActivityRelativeRegex 数字形式的活跃描述，如 “5日内活跃”、“3周内活跃”、“2月内活跃”
+var hrActivityRelativeRegex = regexp.MustCompile(`(\d+)\s*(日|天|周|个月|月)内`)
+
+// ParseHrActivity 解析Boss直聘的HR活跃描述（如 “刚刚活跃”、“3日内活跃”、“半年前活跃”），无法识别时返回 false
+// 参数也可以是等级代码（如 over_half_year），便于在配置中使用
+func ParseHrActivity(desc string) (HrActivity, bool) {
+	text := utils.NormalizeForKey(desc)
+	if text == "" {
+		return HrActivity{}, false
+	}
+
+	for _, activity := range HrActivityLevels {
+		if text == utils.NormalizeForKey(activity.Code) || text == utils.NormalizeForKey(activity.Label) {
+			return activity, true
+		}
+	}
+
+	switch {
+	case strings.Contains(text, "在线"):
+		return HrActivityLevels[0], true
+	case strings.Contains(text, "刚刚"):
+		return HrActivityLevels[1], true
+	case strings.Contains(text, "今日"), strings.Contains(text, "今天"):
+		return HrActivityLevels[2], true
+	case strings.Contains(text, "本周"):
+		return HrActivityLevels[4], true
+	case strings.Contains(text, "本月"):
+		return HrActivityLevels[6], true
+	case strings.Contains(text, "近半年"), strings.Contains(text, "半年内"):
+		return HrActivityLevels[7], true
+	case strings.Contains(text, "年"):
+		// 半年前、1年前等
+		return HrActivityLevels[8], true
+	}
+
+	if match := hrActivityRelativeRegex.FindStringSubmatch(text); len(match) > 2 {
+		n, _ := strconv.Atoi(match[1])
+		days := n
+		switch match[2] {
+		case "周":
+			days = n * 7
+		case "月", "个月":
+			days = n * 30
+		}
+		return hrActivityByDays(days), true
+	}
+	return HrActivity{}, false
+}
+
+// hrActivityByDays 按天数归入最接近的等级（不低于该天数的第一个等级）
+func hrActivityByDays(days int) HrActivity {
+	for _, activity := range HrActivityLevels {
+		if activity.Level >= 2 && days <= activity.Days {
+			return activity
+		}
+	}
+	return HrActivityLevels[len(HrActivityLevels)-1]
+}
+
+// HrActivityLabel 等级代码对应的展示文本，未知代码原样返回
+func HrActivityLabel(code string) string {
+	for _, activity := range HrActivityLevels {
+		if activity.Code == code {
+			return activity.Label
+		}
+	}
+	return code
+}
@@ -0,0 +1,112 @@
+package service
+
+import "testing"
+
+func TestParseHrActivity(t *testing.T) {
+	tests := []struct {
+		name   string
+		desc   string
+		want   string
+		wantOk bool
+	}{
+		// Boss直聘展示文本
+		{"在线", "在线", HR_ACTIVITY_ONLINE, true},
+		{"刚刚活跃", "刚刚活跃", HR_ACTIVITY_JUST_NOW, true},
+		{"今日活跃", "今日活跃", HR_ACTIVITY_TODAY, true},
+		{"3日内活跃", "3日内活跃", HR_ACTIVITY_WITHIN_3_DAYS, true},
+		{"本周活跃", "本周活跃", HR_ACTIVITY_THIS_WEEK, true},
+		{"2周内活跃", "2周内活跃", HR_ACTIVITY_WITHIN_2_WEEKS, true},
+		{"本月活跃", "本月活跃", HR_ACTIVITY_THIS_MONTH, true},
+		{"近半年活跃", "近半年活跃", HR_ACTIVITY_WITHIN_HALF_YEAR, true},
+		{"半年前活跃", "半年前活跃", HR_ACTIVITY_OVER_HALF_YEAR, true},
+
+		// 关键词
+		{"近半年先于“年”判断", "近半年内活跃过", HR_ACTIVITY_WITHIN_HALF_YEAR, true},
+		{"半年内", "半年内活跃", HR_ACTIVITY_WITHIN_HALF_YEAR, true},
+		{"1年前", "1年前活跃", HR_ACTIVITY_OVER_HALF_YEAR, true},
+		{"一年以上", "一年以上未活跃", HR_ACTIVITY_OVER_HALF_YEAR, true},
+		{"今天", "今天活跃", HR_ACTIVITY_TODAY, true},
+		{"刚刚在线按在线处理", "刚刚在线", HR_ACTIVITY_ONLINE, true},
+		{"全角与空白", " ３日内 活跃 ", HR_ACTIVITY_WITHIN_3_DAYS, true},
+
+		// 数字形式
+		{"2周内", "2周内", HR_ACTIVITY_WITHIN_2_WEEKS, true},
+		{"0日内", "0日内活跃", HR_ACTIVITY_TODAY, true},
+		{"1日内", "1日内活跃", HR_ACTIVITY_TODAY, true},
+		{"2天内", "2天内活跃", HR_ACTIVITY_WITHIN_3_DAYS, true},
+		{"5日内", "5日内活跃", HR_ACTIVITY_THIS_WEEK, true},
+		{"10日内", "10日内活跃", HR_ACTIVITY_WITHIN_2_WEEKS, true},
+		{"1周内", "1周内活跃", HR_ACTIVITY_THIS_WEEK, true},
+		{"3周内", "3周内活跃", HR_ACTIVITY_THIS_MONTH, true},
+		{"1月内", "1月内活跃", HR_ACTIVITY_THIS_MONTH, true},
+		{"2月内", "2月内活跃", HR_ACTIVITY_WITHIN_HALF_YEAR, true},
+		{"6个月内", "6个月内活跃", HR_ACTIVITY_WITHIN_HALF_YEAR, true},
+		{"7个月内", "7个月内活跃", HR_ACTIVITY_OVER_HALF_YEAR, true},
+
+		// 等级代码
+		{"等级代码", "over_half_year", HR_ACTIVITY_OVER_HALF_YEAR, true},
+		{"等级代码忽略大小写", "This_Week", HR_ACTIVITY_THIS_WEEK, true},
+		{"数字开头的等级代码", "within_3_days", HR_ACTIVITY_WITHIN_3_DAYS, true},
+
+		// 无法识别
+		{"空字符串", "", "", false},
+		{"空白", "  ", "", false},
+		{"只有活跃", "活跃", "", false},
+		{"昨天", "昨天活跃", "", false},
+		{"未知代码", "unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity, ok := ParseHrActivity(tt.desc)
+			if ok != tt.wantOk || activity.Code != tt.want {
+				t.Errorf("ParseHrActivity(%q) = %q, %v, 期望 %q, %v", tt.desc, activity.Code, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestHrActivityByDays(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{0, HR_ACTIVITY_TODAY},
+		{1, HR_ACTIVITY_TODAY},
+		{2, HR_ACTIVITY_WITHIN_3_DAYS},
+		{3, HR_ACTIVITY_WITHIN_3_DAYS},
+		{4, HR_ACTIVITY_THIS_WEEK},
+		{7, HR_ACTIVITY_THIS_WEEK},
+		{8, HR_ACTIVITY_WITHIN_2_WEEKS},
+		{14, HR_ACTIVITY_WITHIN_2_WEEKS},
+		{15, HR_ACTIVITY_THIS_MONTH},
+		{30, HR_ACTIVITY_THIS_MONTH},
+		{31, HR_ACTIVITY_WITHIN_HALF_YEAR},
+		{180, HR_ACTIVITY_WITHIN_HALF_YEAR},
+		{181, HR_ACTIVITY_OVER_HALF_YEAR},
+		{1000, HR_ACTIVITY_OVER_HALF_YEAR},
+	}
+
+	for _, tt := range tests {
+		if got := hrActivityByDays(tt.days).Code; got != tt.want {
+			t.Errorf("hrActivityByDays(%d) = %q, 期望 %q", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestHrActivityLevels(t *testing.T) {
+	for i, activity := range HrActivityLevels {
+		if activity.Level != i {
+			t.Errorf("%s 的 Level = %d, 期望按顺序为 %d", activity.Code, activity.Level, i)
+		}
+		if i > 0 && activity.Days < HrActivityLevels[i-1].Days {
+			t.Errorf("%s 的天数 %d 小于更活跃的等级", activity.Code, activity.Days)
+		}
+		if got := HrActivityLabel(activity.Code); got != activity.Label {
+			t.Errorf("HrActivityLabel(%q) = %q, 期望 %q", activity.Code, got, activity.Label)
+		}
+	}
+	if got := HrActivityLabel("unknown"); got != "unknown" {
+		t.Errorf("未知代码应原样返回, got %q", got)
+	}
+}
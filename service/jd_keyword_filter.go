@@ -0,0 +1,174 @@
+package service
+
+import (
+	"fmt"
+	"get_jobs_go/model"
+	"get_jobs_go/utils"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// JD_REGEX_PREFIX 以该前缀开头的条目整体按正则表达式匹配
+const JD_REGEX_PREFIX = "re:"
+
+// jdTerm 单个关键词（普通关键词按包含匹配，re: 前缀按正则匹配）
+type jdTerm struct {
+	keyword string
+	regex   *regexp.Regexp
+}
+
+// find 在规范化文本中查找关键词，返回命中的文本（未命中返回空）
+func (t *jdTerm) find(normalized string) string {
+	if t.regex != nil {
+		return t.regex.FindString(normalized)
+	}
+	if strings.Contains(normalized, t.keyword) {
+		return t.keyword
+	}
+	return ""
+}
+
+// jdGroup 关键词组：多个备选项之间为“或”（|），备选项内的关键词之间为“且”（&）
+type jdGroup struct {
+	source       string
+	alternatives [][]*jdTerm
+}
+
+// match 任一备选项的全部关键词都命中时返回命中的文本，否则返回 nil
+func (g *jdGroup) match(normalized string) []string {
+	for _, terms := range g.alternatives {
+		hits := make([]string, 0, len(terms))
+		for _, term := range terms {
+			hit := term.find(normalized)
+			if hit == "" {
+				hits = nil
+				break
+			}
+			hits = append(hits, hit)
+		}
+		if hits != nil {
+			return hits
+		}
+	}
+	return nil
+}
+
+// JdKeywordFilter 职位描述关键词过滤器：包含组须全部命中，排除组任一命中即过滤
+type JdKeywordFilter struct {
+	include []*jdGroup
+	exclude []*jdGroup
+}
+
+// JdKeywordResult 职位描述关键词检查结果
+type JdKeywordResult struct {
+	Reason     string   // 过滤原因（为空表示不过滤），如 jd_exclude:外包、jd_include:go|golang
+	Highlights []string // 命中的关键词（包含组与排除组）
+}
+
+// NewJdKeywordFilter 创建职位描述关键词过滤器，无效的条目记录日志后忽略
+// 条目格式：“go|golang” 表示任一命中，“go&kubernetes” 表示同时命中，“re:单休|大小周” 表示正则
+func NewJdKeywordFilter(include, exclude []string) *JdKeywordFilter {
+	return &JdKeywordFilter{
+		include: compileJdGroups(include),
+		exclude: compileJdGroups(exclude),
+	}
+}
+
+// Enabled 是否配置了关键词条目
+func (f *JdKeywordFilter) Enabled() bool {
+	return len(f.include) > 0 || len(f.exclude) > 0
+}
+
+// Check 检查职位描述与职位标签，排除组优先于包含组
+func (f *JdKeywordFilter) Check(job *model.Job) *JdKeywordResult {
+	result := &JdKeywordResult{}
+	if !f.Enabled() {
+		return result
+	}
+
+	text := utils.NormalizeText(job.JobInfo + "\n" + job.JobLabels)
+	for _, group := range f.exclude {
+		if hits := group.match(text); hits != nil {
+			result.Highlights = appendUnique(result.Highlights, hits...)
+			if result.Reason == "" {
+				result.Reason = model.FILTER_REASON_JD_EXCLUDE + ":" + group.source
+			}
+		}
+	}
+	for _, group := range f.include {
+		hits := group.match(text)
+		if hits == nil {
+			if result.Reason == "" {
+				result.Reason = model.FILTER_REASON_JD_INCLUDE + ":" + group.source
+			}
+			continue
+		}
+		result.Highlights = appendUnique(result.Highlights, hits...)
+	}
+	return result
+}
+
+// compileJdGroups 解析关键词条目
+func compileJdGroups(entries []string) []*jdGroup {
+	groups := make([]*jdGroup, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		group, err := compileJdGroup(entry)
+		if err != nil {
+			log.Printf("职位描述关键词无效，已忽略 | 条目：%s | 错误：%v", entry, err)
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// compileJdGroup 解析单个关键词条目
+func compileJdGroup(entry string) (*jdGroup, error) {
+	group := &jdGroup{source: entry}
+	if strings.HasPrefix(entry, JD_REGEX_PREFIX) {
+		regex, err := compileBlacklistRegex(strings.TrimPrefix(entry, JD_REGEX_PREFIX))
+		if err != nil {
+			return nil, err
+		}
+		group.alternatives = [][]*jdTerm{{{regex: regex}}}
+		return group, nil
+	}
+
+	for _, alternative := range strings.Split(entry, "|") {
+		var terms []*jdTerm
+		for _, keyword := range strings.Split(alternative, "&") {
+			if keyword = utils.NormalizeText(keyword); keyword != "" {
+				terms = append(terms, &jdTerm{keyword: keyword})
+			}
+		}
+		if len(terms) > 0 {
+			group.alternatives = append(group.alternatives, terms)
+		}
+	}
+	if len(group.alternatives) == 0 {
+		return nil, fmt.Errorf("关键词为空")
+	}
+	return group, nil
+}
+
+// appendUnique 追加未出现过的值
+func appendUnique(values []string, items ...string) []string {
+	for _, item := range items {
+		exists := false
+		for _, value := range values {
+			if value == item {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			values = append(values, item)
+		}
+	}
+	return values
+}
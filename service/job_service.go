@@ -0,0 +1,112 @@
+package service
+
+import (
+	"get_jobs_go/model"
+	"get_jobs_go/repository"
+	"get_jobs_go/utils"
+	"time"
+)
+
+// JobService 统一职位服务：跨平台职位的持久化与去重
+type JobService struct {
+	jobRepo repository.JobRepository
+}
+
+func NewJobService(jobRepo repository.JobRepository) *JobService {
+	return &JobService{
+		jobRepo: jobRepo,
+	}
+}
+
+// BuildDedupeKey 构建去重键：规范化的 公司|职位|城市
+func (s *JobService) BuildDedupeKey(companyName, jobName, city string) string {
+	return utils.JobDedupeKey(companyName, jobName, city)
+}
+
+// SaveOrUpdateJob 按 平台+平台职位ID 保存或更新职位，并刷新去重键
+func (s *JobService) SaveOrUpdateJob(job *model.Job) error {
+	job.DedupeKey = s.BuildDedupeKey(job.CompanyName, job.JobName, job.City)
+
+	if job.PlatformJobId == "" {
+		return s.saveNew(job)
+	}
+
+	existing, err := s.jobRepo.FindByPlatformJobId(job.Platform, job.PlatformJobId)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return s.saveNew(job)
+	}
+
+	job.ID = existing.ID
+	job.CreatedAt = existing.CreatedAt
+	job.HitKeywords = mergeTags(existing.HitKeywords, job.HitKeywords)
+	if job.DeliveryStatus == "" {
+		// 未指定状态时保留已有的投递结果（包括过滤原因）
+		job.DeliveryStatus = existing.DeliveryStatus
+		job.FilterReason = existing.FilterReason
+	}
+	job.UpdatedAt = time.Now()
+	return s.jobRepo.Update(job)
+}
+
+// AddHitKeyword 为已保存的职位追加搜到它的搜索关键词（职位不存在时忽略）
+func (s *JobService) AddHitKeyword(platform, platformJobId, keyword string) error {
+	existing, err := s.jobRepo.FindByPlatformJobId(platform, platformJobId)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	merged := mergeTags(existing.HitKeywords, keyword)
+	if merged == existing.HitKeywords {
+		return nil
+	}
+	existing.HitKeywords = merged
+	existing.UpdatedAt = time.Now()
+	return s.jobRepo.Update(existing)
+}
+
+// saveNew 新建职位记录
+func (s *JobService) saveNew(job *model.Job) error {
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	if job.DeliveryStatus == "" {
+		job.DeliveryStatus = model.DELIVERY_STATUS_PENDING
+	}
+	return s.jobRepo.Save(job)
+}
+
+// UpdateDeliveryStatus 更新职位的投递状态与过滤原因
+func (s *JobService) UpdateDeliveryStatus(platform, platformJobId, status, filterReason string) error {
+	return s.jobRepo.UpdateDeliveryStatus(platform, platformJobId, status, filterReason)
+}
+
+// FindCrossPlatformDuplicate 查找其他平台上已投递或已沟通过的同一职位（同公司、同职位、同城市）
+func (s *JobService) FindCrossPlatformDuplicate(job *model.Job) (*model.Job, error) {
+	dedupeKey := s.BuildDedupeKey(job.CompanyName, job.JobName, job.City)
+	if dedupeKey == "" {
+		return nil, nil
+	}
+
+	candidates, err := s.jobRepo.FindByDedupeKey(dedupeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Platform == job.Platform {
+			continue
+		}
+		if candidate.DeliveryStatus == model.DELIVERY_STATUS_DELIVERED || candidate.DeliveryStatus == model.DELIVERY_STATUS_CONTACTED {
+			return candidate, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetJobById 根据ID获取职位
+func (s *JobService) GetJobById(id int64) (*model.Job, error) {
+	return s.jobRepo.FindByID(id)
+}
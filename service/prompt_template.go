@@ -0,0 +1,134 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"get_jobs_go/model"
+	"strings"
+	"text/template"
+)
+
+const (
+	// DEFAULT_PROMPT_PLACEHOLDER 默认AI配置中的提示词占位文本，视为未配置模板
+	DEFAULT_PROMPT_PLACEHOLDER = "请在此填写AI提示词模板"
+	// DEFAULT_GREETING_MAX_LENGTH 打招呼语默认字数上限
+	DEFAULT_GREETING_MAX_LENGTH = 60
+)
+
+// DefaultGreetingPromptTemplate 未配置提示词模板时使用的默认打招呼语模板
+const DefaultGreetingPromptTemplate = `请基于以下信息生成简洁友好的中文打招呼语，不超过{{.MaxLength}}字：
+个人介绍：{{.Introduce}}
+关键词：{{.Keyword}}
+职位名称：{{.JobName}}
+{{- if .CompanyName}}
+公司名称：{{.CompanyName}}{{end}}
+{{- if .Salary}}
+薪资：{{.Salary}}{{end}}
+{{- if .HrName}}
+招聘者：{{.HrName}}{{if .HrTitle}}（{{.HrTitle}}）{{end}}{{end}}
+职位描述：{{truncate .JobDescription 2000}}
+参考语：{{.SayHi}}`
+
+// PromptTemplateData 提示词模板可用变量
+//
+// 模板语法为 Go text/template，例如：
+//
+//	{{.JobName}}、{{if .Salary}}薪资：{{.Salary}}{{end}}、{{truncate .JobDescription 500}}、{{default .HrName "HR"}}
+type PromptTemplateData struct {
+	Introduce      string // 个人介绍
+	Keyword        string // 搜索关键词
+	JobName        string // 职位名称
+	JobDescription string // 职位描述
+	CompanyName    string // 公司名称
+	Salary         string // 薪资
+	HrName         string // 招聘者姓名
+	HrTitle        string // 招聘者职位
+	City           string // 城市
+	SayHi          string // 默认打招呼语（参考语）
+	MaxLength      int    // 打招呼语字数上限
+}
+
+// NewPromptTemplateData 根据统一职位构建模板变量
+func NewPromptTemplateData(job *model.Job, keyword, sayHi string) *PromptTemplateData {
+	return &PromptTemplateData{
+		Keyword:        keyword,
+		JobName:        job.JobName,
+		JobDescription: job.JobInfo,
+		CompanyName:    job.CompanyName,
+		Salary:         job.Salary,
+		HrName:         job.Recruiter,
+		HrTitle:        job.RecruiterTitle,
+		City:           job.City,
+		SayHi:          sayHi,
+		MaxLength:      DEFAULT_GREETING_MAX_LENGTH,
+	}
+}
+
+// promptTemplateFuncs 模板函数
+var promptTemplateFuncs = template.FuncMap{
+	// truncate 按字符数截断文本，超出部分以省略号结尾
+	"truncate": func(s string, n int) string {
+		runes := []rune(s)
+		if n <= 0 || len(runes) <= n {
+			return s
+		}
+		return string(runes[:n]) + "…"
+	},
+	// default 值为空时使用默认值
+	"default": func(value, fallback string) string {
+		if strings.TrimSpace(value) == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// samplePromptTemplateData 用于校验模板的示例数据
+var samplePromptTemplateData = &PromptTemplateData{
+	Introduce:      "示例个人介绍",
+	Keyword:        "Golang",
+	JobName:        "Golang开发工程师",
+	JobDescription: "示例职位描述",
+	CompanyName:    "示例公司",
+	Salary:         "20-30K",
+	HrName:         "张女士",
+	HrTitle:        "HR",
+	City:           "广州",
+	SayHi:          "您好，对贵司岗位很感兴趣，期待进一步沟通。",
+	MaxLength:      DEFAULT_GREETING_MAX_LENGTH,
+}
+
+// IsPromptTemplateConfigured 判断提示词是否为用户配置的模板（空或默认占位文本视为未配置）
+func IsPromptTemplateConfigured(prompt string) bool {
+	trimmed := strings.TrimSpace(prompt)
+	return trimmed != "" && trimmed != DEFAULT_PROMPT_PLACEHOLDER
+}
+
+// ValidatePromptTemplate 校验提示词模板：语法正确且只引用已支持的变量
+func ValidatePromptTemplate(prompt string) error {
+	if !IsPromptTemplateConfigured(prompt) {
+		return nil
+	}
+	rendered, err := RenderPromptTemplate(prompt, samplePromptTemplateData)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(rendered) == "" {
+		return fmt.Errorf("提示词模板渲染结果为空")
+	}
+	return nil
+}
+
+// RenderPromptTemplate 使用模板变量渲染提示词
+func RenderPromptTemplate(prompt string, data *PromptTemplateData) (string, error) {
+	tpl, err := template.New("prompt").Funcs(promptTemplateFuncs).Option("missingkey=error").Parse(prompt)
+	if err != nil {
+		return "", fmt.Errorf("提示词模板语法错误: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("提示词模板渲染失败: %v", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"get_jobs_go/model"
+	"get_jobs_go/utils"
+	"strings"
+)
+
+// DefaultHeadhunterTitleKeywords 招聘者职位中表明猎头身份的关键词
+var DefaultHeadhunterTitleKeywords = []string{
+	"猎头", "猎聘顾问", "寻访", "headhunter", "hunter",
+}
+
+// DefaultHeadhunterCompanyKeywords 公司名称中表明猎头机构的关键词
+var DefaultHeadhunterCompanyKeywords = []string{
+	"猎头", "猎聘", "人才寻访",
+}
+
+// DefaultAgencyCompanyKeywords 公司名称或行业中表明外包/派遣机构的关键词
+var DefaultAgencyCompanyKeywords = []string{
+	"人力资源", "外包", "派遣", "劳务", "人才服务",
+}
+
+// DefaultAgencyCompanies 常见的IT外包/驻场公司
+var DefaultAgencyCompanies = []string{
+	"中软国际", "软通动力", "文思海辉", "博彦科技", "法本信息", "佰钧成", "诚迈科技", "润和软件", "汉克时代", "亿达信息",
+}
+
+// RecruiterProfile 招聘方信息（来自 detail.json 的 bossInfo / brandComInfo / jobInfo）
+type RecruiterProfile struct {
+	HrTitle     string // 招聘者职位（bossInfo.title）
+	HrCompany   string // 招聘者所属公司（bossInfo.brandName）
+	CompanyName string // 职位所属公司（brandComInfo.brandName）
+	Industry    string // 公司行业（brandComInfo.industryName）
+	ProxyJob    bool   // 是否为代招职位（jobInfo.proxyJob）
+}
+
+// RecruiterClassifier 招聘方分类器：区分猎头、外包/派遣机构与直招
+type RecruiterClassifier struct {
+	headhunterCompanies []string
+	agencyCompanies     []string
+	titleKeywords       []string
+	hunterKeywords      []string
+	agencyKeywords      []string
+}
+
+// NewRecruiterClassifier 创建招聘方分类器，额外的猎头公司与外包公司名单会追加到默认名单之后
+func NewRecruiterClassifier(headhunterCompanies, agencyCompanies []string) *RecruiterClassifier {
+	return &RecruiterClassifier{
+		headhunterCompanies: normalizeKeywords(headhunterCompanies),
+		agencyCompanies:     normalizeKeywords(append(append([]string{}, DefaultAgencyCompanies...), agencyCompanies...)),
+		titleKeywords:       normalizeKeywords(DefaultHeadhunterTitleKeywords),
+		hunterKeywords:      normalizeKeywords(DefaultHeadhunterCompanyKeywords),
+		agencyKeywords:      normalizeKeywords(DefaultAgencyCompanyKeywords),
+	}
+}
+
+// Classify 判断招聘方类型，返回类型（headhunter/outsourcing/direct）与判断依据
+func (c *RecruiterClassifier) Classify(profile *RecruiterProfile) (string, string) {
+	title := utils.NormalizeText(profile.HrTitle)
+	companies := []string{utils.NormalizeText(profile.CompanyName), utils.NormalizeText(profile.HrCompany)}
+
+	// 人工维护的名单优先
+	if hit := matchAnyKeyword(companies, c.headhunterCompanies); hit != "" {
+		return model.RECRUITER_TYPE_HEADHUNTER, "猎头公司名单：" + hit
+	}
+	if hit := matchAnyKeyword(companies, c.agencyCompanies); hit != "" {
+		return model.RECRUITER_TYPE_OUTSOURCING, "外包公司名单：" + hit
+	}
+
+	if hit := matchAnyKeyword([]string{title}, c.titleKeywords); hit != "" {
+		return model.RECRUITER_TYPE_HEADHUNTER, "招聘者职位包含：" + hit
+	}
+	if hit := matchAnyKeyword(companies, c.hunterKeywords); hit != "" {
+		return model.RECRUITER_TYPE_HEADHUNTER, "公司名称包含：" + hit
+	}
+	if hit := matchAnyKeyword(companies, c.agencyKeywords); hit != "" {
+		return model.RECRUITER_TYPE_OUTSOURCING, "公司名称包含：" + hit
+	}
+	if hit := matchAnyKeyword([]string{utils.NormalizeText(profile.Industry)}, c.agencyKeywords); hit != "" {
+		return model.RECRUITER_TYPE_OUTSOURCING, "公司行业包含：" + hit
+	}
+
+	if profile.ProxyJob {
+		return model.RECRUITER_TYPE_HEADHUNTER, "代招职位"
+	}
+	// 招聘者不属于职位所在公司且自称顾问，多为猎头代招
+	if companies[1] != "" && utils.NormalizeCompanyName(profile.HrCompany) != utils.NormalizeCompanyName(profile.CompanyName) &&
+		strings.Contains(title, "顾问") {
+		return model.RECRUITER_TYPE_HEADHUNTER, "招聘者所属公司与职位公司不一致"
+	}
+	return model.RECRUITER_TYPE_DIRECT, ""
+}
+
+// normalizeKeywords 规范化关键词并去掉空值
+func normalizeKeywords(keywords []string) []string {
+	result := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword = utils.NormalizeText(keyword); keyword != "" {
+			result = append(result, keyword)
+		}
+	}
+	return result
+}
+
+// matchAnyKeyword 返回任一文本包含的第一个关键词，未命中返回空
+func matchAnyKeyword(texts, keywords []string) string {
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+		for _, keyword := range keywords {
+			if strings.Contains(text, keyword) {
+				return keyword
+			}
+		}
+	}
+	return ""
+}
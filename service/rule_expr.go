@@ -0,0 +1,649 @@
+package service
+
+import (
+	"fmt"
+	"get_jobs_go/model"
+	"get_jobs_go/utils"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RuleFields 规则表达式可用字段及说明
+var RuleFields = map[string]string{
+	"jobName":       "职位名称",
+	"company":       "公司名称",
+	"city":          "城市",
+	"location":      "工作地区（城市, 经验, 学历）",
+	"address":       "工作地址",
+	"district":      "区县与商圈（如 南山区·科技园）",
+	"distanceKm":    "到最近通勤点的距离（千米，未知时为空）",
+	"salary":        "薪资原文",
+	"salaryMin":     "薪资下限（K/月，无法解析时为空）",
+	"salaryMax":     "薪资上限（K/月，无法解析时为空）",
+	"salaryMonths":  "年薪月数",
+	"experience":    "经验要求",
+	"degree":        "学历要求",
+	"companyScale":  "公司规模",
+	"companyStage":  "融资阶段",
+	"industry":      "所属行业",
+	"hrName":        "招聘者姓名",
+	"hrTitle":       "招聘者职位",
+	"hrActive":      "招聘者活跃状态（如 刚刚活跃、本周活跃、半年前活跃）",
+	"hrActivity":    "招聘者活跃度等级（online/just_now/today/within_3_days/this_week/within_2_weeks/this_month/within_half_year/over_half_year）",
+	"hrActiveDays":  "招聘者距上次活跃的大致天数（无法识别时为空）",
+	"recruiterType": "招聘方类型（headhunter/outsourcing/direct）",
+	"jd":            "职位描述",
+	"labels":        "职位标签（逗号分隔）",
+	"aiScore":       "AI匹配度评分（未评分为空）",
+	"techStack":     "技术栈（逗号分隔）",
+	"workMode":      "工作模式（remote/hybrid/onsite）",
+	"requiredYears": "要求工作年限（未提及为空）",
+	"overtime":      "加班信号（逗号分隔）",
+	"outsourcing":   "是否外包/驻场",
+	"education":     "职位描述中的学历要求",
+	"teamSize":      "团队规模",
+}
+
+// ruleAiFields 依赖AI评分或AI属性提取结果的字段
+var ruleAiFields = map[string]bool{
+	"aiScore":       true,
+	"techStack":     true,
+	"workMode":      true,
+	"requiredYears": true,
+	"overtime":      true,
+	"outsourcing":   true,
+	"education":     true,
+	"teamSize":      true,
+}
+
+// RuleEnv 规则表达式求值环境（字段名 -> 值），值类型为 string / float64 / bool / nil
+type RuleEnv map[string]interface{}
+
+// NewRuleEnv 根据统一职位构建求值环境，salary 为解析后的薪资（可为空）
+func NewRuleEnv(job *model.Job, salary *SalaryInfo) RuleEnv {
+	env := RuleEnv{
+		"jobName":       job.JobName,
+		"company":       job.CompanyName,
+		"city":          job.City,
+		"location":      job.JobArea,
+		"address":       job.Address,
+		"district":      job.District,
+		"distanceKm":    nil,
+		"salary":        job.Salary,
+		"salaryMin":     nil,
+		"salaryMax":     nil,
+		"salaryMonths":  nil,
+		"experience":    job.Experience,
+		"degree":        job.Degree,
+		"companyScale":  job.CompanyScale,
+		"companyStage":  job.FinancingStage,
+		"industry":      job.Industry,
+		"hrName":        job.Recruiter,
+		"hrTitle":       job.RecruiterTitle,
+		"hrActive":      job.HrActiveStatus,
+		"hrActivity":    job.HrActivity,
+		"hrActiveDays":  nil,
+		"recruiterType": job.RecruiterType,
+		"jd":            job.JobInfo,
+		"labels":        job.JobLabels,
+		"aiScore":       nil,
+		"techStack":     job.TechStack,
+		"workMode":      job.WorkMode,
+		"requiredYears": nil,
+		"overtime":      job.OvertimeSignals,
+		"outsourcing":   job.Outsourcing == 1,
+		"education":     job.Education,
+		"teamSize":      job.TeamSize,
+	}
+	if salary != nil {
+		if salary.MinK != nil {
+			env["salaryMin"] = float64(*salary.MinK)
+		}
+		if salary.MaxK != nil {
+			env["salaryMax"] = float64(*salary.MaxK)
+		}
+		env["salaryMonths"] = float64(salary.Months)
+	}
+	if job.AiScore != nil {
+		env["aiScore"] = float64(*job.AiScore)
+	}
+	if job.RequiredYears != nil {
+		env["requiredYears"] = float64(*job.RequiredYears)
+	}
+	if job.HrInactiveDays != nil {
+		env["hrActiveDays"] = float64(*job.HrInactiveDays)
+	}
+	if job.DistanceKm != nil {
+		env["distanceKm"] = *job.DistanceKm
+	}
+	return env
+}
+
+// RuleExpression 编译后的规则表达式
+//
+// 语法示例：
+//
+//	salaryMax >= 30 && companyScale in ["1000-9999人", "10000人以上"]
+//	jd contains "外包" or hrTitle matches "(?i)猎头|headhunter"
+//	not (hrActive contains "年") and (aiScore == null or aiScore >= 70)
+//
+// 支持的运算符：== != < <= > >= contains matches in，&& (and) || (or) ! (not)，括号与 [..] 列表
+type RuleExpression struct {
+	Source string
+	root   ruleNode
+	fields map[string]bool
+}
+
+// CompileRuleExpression 解析并校验规则表达式
+func CompileRuleExpression(source string) (*RuleExpression, error) {
+	tokens, err := tokenizeRule(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, fmt.Errorf("规则表达式为空")
+	}
+
+	p := &ruleParser{tokens: tokens, fields: make(map[string]bool)}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != ruleTokenEOF {
+		return nil, fmt.Errorf("规则表达式第%d个字符处存在多余内容: %s", tok.pos+1, tok.text)
+	}
+	return &RuleExpression{Source: source, root: root, fields: p.fields}, nil
+}
+
+// Eval 对求值环境计算表达式，结果按真值判断
+func (e *RuleExpression) Eval(env RuleEnv) (bool, error) {
+	value, err := e.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	return ruleTruthy(value), nil
+}
+
+// Fields 表达式引用的字段（按名称排序）
+func (e *RuleExpression) Fields() []string {
+	fields := make([]string, 0, len(e.fields))
+	for field := range e.fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// NeedsAi 表达式是否引用了依赖AI评分或AI属性提取的字段
+func (e *RuleExpression) NeedsAi() bool {
+	for field := range e.fields {
+		if ruleAiFields[field] {
+			return true
+		}
+	}
+	return false
+}
+
+// ==================== 词法分析 ====================
+
+type ruleTokenKind int
+
+const (
+	ruleTokenEOF ruleTokenKind = iota
+	ruleTokenIdent
+	ruleTokenString
+	ruleTokenNumber
+	ruleTokenOperator
+)
+
+type ruleToken struct {
+	kind ruleTokenKind
+	text string
+	pos  int
+}
+
+// ruleOperators 符号运算符（长的在前，优先匹配）
+var ruleOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ","}
+
+func tokenizeRule(source string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			text, next, err := scanRuleString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, ruleToken{kind: ruleTokenString, text: text, pos: i})
+			i = next
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, ruleToken{kind: ruleTokenNumber, text: string(runes[start:i]), pos: start})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, ruleToken{kind: ruleTokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			matched := false
+			rest := string(runes[i:])
+			for _, op := range ruleOperators {
+				if strings.HasPrefix(rest, op) {
+					tokens = append(tokens, ruleToken{kind: ruleTokenOperator, text: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("规则表达式第%d个字符处无法识别: %c", i+1, r)
+			}
+		}
+	}
+	return append(tokens, ruleToken{kind: ruleTokenEOF, pos: len(runes)}), nil
+}
+
+// scanRuleString 读取引号包裹的字符串，支持 \" \' \\ 转义
+func scanRuleString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				if runes[i] != quote && runes[i] != '\\' {
+					b.WriteRune('\\')
+				}
+				b.WriteRune(runes[i])
+			}
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, fmt.Errorf("规则表达式第%d个字符处的字符串缺少结束引号", start+1)
+}
+
+// ==================== 语法分析 ====================
+
+type ruleParser struct {
+	tokens []ruleToken
+	pos    int
+	fields map[string]bool
+}
+
+func (p *ruleParser) peek() ruleToken {
+	return p.tokens[p.pos]
+}
+
+func (p *ruleParser) next() ruleToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != ruleTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept 当前词为指定运算符或关键字（不区分大小写）时前进并返回 true
+func (p *ruleParser) accept(words ...string) bool {
+	tok := p.peek()
+	if tok.kind != ruleTokenOperator && tok.kind != ruleTokenIdent {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(tok.text, word) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ruleParser) expect(op string) error {
+	if p.accept(op) {
+		return nil
+	}
+	tok := p.peek()
+	return fmt.Errorf("规则表达式第%d个字符处缺少 %s", tok.pos+1, op)
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||", "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &ruleLogicNode{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&", "and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &ruleLogicNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseNot() (ruleNode, error) {
+	if p.accept("!", "not") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &ruleNotNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *ruleParser) parseComparison() (ruleNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	op := strings.ToLower(tok.text)
+	switch {
+	case tok.kind == ruleTokenOperator && (op == "==" || op == "!=" || op == "<" || op == "<=" || op == ">" || op == ">="):
+	case tok.kind == ruleTokenIdent && (op == "contains" || op == "matches" || op == "in"):
+	default:
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	node := &ruleCompareNode{op: op, left: left, right: right}
+
+	// 字面量正则在编译时校验
+	if op == "matches" {
+		if literal, ok := right.(*ruleLiteralNode); ok {
+			pattern, ok := literal.value.(string)
+			if !ok {
+				return nil, fmt.Errorf("规则表达式第%d个字符处: matches 右侧必须为字符串", tok.pos+1)
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("规则表达式中的正则无效: %v", err)
+			}
+			node.regex = re
+		}
+	}
+	return node, nil
+}
+
+func (p *ruleParser) parsePrimary() (ruleNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case ruleTokenString:
+		return &ruleLiteralNode{value: tok.text}, nil
+	case ruleTokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("规则表达式第%d个字符处的数字无效: %s", tok.pos+1, tok.text)
+		}
+		return &ruleLiteralNode{value: value}, nil
+	case ruleTokenIdent:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return &ruleLiteralNode{value: true}, nil
+		case "false":
+			return &ruleLiteralNode{value: false}, nil
+		case "null":
+			return &ruleLiteralNode{value: nil}, nil
+		}
+		if _, ok := RuleFields[tok.text]; !ok {
+			return nil, fmt.Errorf("规则表达式第%d个字符处的字段不存在: %s", tok.pos+1, tok.text)
+		}
+		p.fields[tok.text] = true
+		return &ruleFieldNode{name: tok.text}, nil
+	case ruleTokenOperator:
+		switch tok.text {
+		case "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		case "[":
+			list := &ruleListNode{}
+			if p.accept("]") {
+				return list, nil
+			}
+			for {
+				item, err := p.parsePrimary()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+				if p.accept("]") {
+					return list, nil
+				}
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case ruleTokenEOF:
+		return nil, fmt.Errorf("规则表达式不完整")
+	}
+	return nil, fmt.Errorf("规则表达式第%d个字符处无法解析: %s", tok.pos+1, tok.text)
+}
+
+// ==================== 求值 ====================
+
+type ruleNode interface {
+	eval(env RuleEnv) (interface{}, error)
+}
+
+type ruleLiteralNode struct {
+	value interface{}
+}
+
+func (n *ruleLiteralNode) eval(RuleEnv) (interface{}, error) {
+	return n.value, nil
+}
+
+type ruleFieldNode struct {
+	name string
+}
+
+func (n *ruleFieldNode) eval(env RuleEnv) (interface{}, error) {
+	return env[n.name], nil
+}
+
+type ruleListNode struct {
+	items []ruleNode
+}
+
+func (n *ruleListNode) eval(env RuleEnv) (interface{}, error) {
+	values := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+type ruleNotNode struct {
+	operand ruleNode
+}
+
+func (n *ruleNotNode) eval(env RuleEnv) (interface{}, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return !ruleTruthy(value), nil
+}
+
+type ruleLogicNode struct {
+	or          bool
+	left, right ruleNode
+}
+
+func (n *ruleLogicNode) eval(env RuleEnv) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	// 短路求值
+	if ruleTruthy(left) == n.or {
+		return n.or, nil
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return ruleTruthy(right), nil
+}
+
+type ruleCompareNode struct {
+	op          string
+	left, right ruleNode
+	regex       *regexp.Regexp
+}
+
+func (n *ruleCompareNode) eval(env RuleEnv) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return ruleEquals(left, right), nil
+	case "!=":
+		return !ruleEquals(left, right), nil
+	case "<", "<=", ">", ">=":
+		l, lok := left.(float64)
+		r, rok := right.(float64)
+		if !lok || !rok {
+			// 空值或非数字不满足大小比较
+			return false, nil
+		}
+		switch n.op {
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		default:
+			return l >= r, nil
+		}
+	case "contains":
+		text := utils.NormalizeText(ruleString(left))
+		if list, ok := right.([]interface{}); ok {
+			for _, item := range list {
+				if needle := utils.NormalizeText(ruleString(item)); needle != "" && strings.Contains(text, needle) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return strings.Contains(text, utils.NormalizeText(ruleString(right))), nil
+	case "matches":
+		re := n.regex
+		if re == nil {
+			if re, err = regexp.Compile(ruleString(right)); err != nil {
+				return nil, fmt.Errorf("规则表达式中的正则无效: %v", err)
+			}
+		}
+		return re.MatchString(ruleString(left)), nil
+	case "in":
+		list, ok := right.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("in 右侧必须为列表")
+		}
+		for _, item := range list {
+			if ruleEquals(left, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return nil, fmt.Errorf("不支持的运算符: %s", n.op)
+}
+
+// ruleTruthy 真值判断：空值、false、空字符串、0 和空列表为假
+func ruleTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return strings.TrimSpace(v) != ""
+	case float64:
+		return v != 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// ruleEquals 相等比较：数字按数值，字符串忽略大小写与全半角
+func ruleEquals(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	if l, ok := left.(float64); ok {
+		r, ok := right.(float64)
+		return ok && l == r
+	}
+	if l, ok := left.(bool); ok {
+		r, ok := right.(bool)
+		return ok && l == r
+	}
+	return utils.NormalizeText(ruleString(left)) == utils.NormalizeText(ruleString(right))
+}
+
+// ruleString 值的字符串形式，空值为空字符串
+func ruleString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileRuleExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"空表达式", "   ", "规则表达式为空"},
+		{"未知字段", `salaryMax >= 30 && unknownField == "x"`, "字段不存在: unknownField"},
+		{"字符串缺少结束引号", `jd contains "外包`, "缺少结束引号"},
+		{"单引号字符串缺少结束引号", `jd contains '外包`, "缺少结束引号"},
+		{"字面量正则无效", `hrTitle matches "(猎头"`, "正则无效"},
+		{"matches 右侧不是字符串", `hrTitle matches 1`, "matches 右侧必须为字符串"},
+		{"缺少右括号", `(jd contains "外包"`, "缺少 )"},
+		{"列表缺少逗号", `city in ["北京" "上海"]`, "缺少 ,"},
+		{"表达式不完整", `salaryMax >=`, "规则表达式不完整"},
+		{"多余内容", `city == "北京" "上海"`, "存在多余内容"},
+		{"无法识别的字符", `salaryMax >= 30 ; city == "北京"`, "无法识别"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileRuleExpression(tt.source)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CompileRuleExpression(%q) err = %v, 期望包含 %q", tt.source, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuleExpressionEval(t *testing.T) {
+	env := RuleEnv{
+		"jobName":      "高级Go开发工程师",
+		"company":      "",
+		"city":         "北京",
+		"companyScale": "1000-9999人",
+		"hrTitle":      "资深猎头顾问",
+		"hrActive":     "半年前活跃",
+		"jd":           "负责ＧＯ微服务开发，驻场外包",
+		"salaryMin":    float64(25),
+		"salaryMax":    float64(40),
+		"aiScore":      nil,
+		"outsourcing":  true,
+		"labels":       "Go,Kubernetes",
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   bool
+	}{
+		// 优先级：not > and > or
+		{"and 优先于 or", `city == "上海" or city == "北京" and salaryMax >= 30`, true},
+		{"and 优先于 or（右侧为假）", `city == "北京" or city == "上海" and salaryMax >= 50`, true},
+		{"括号改变优先级", `(city == "北京" or city == "上海") and salaryMax >= 50`, false},
+		{"not 优先于 and", `not city == "上海" and salaryMax >= 30`, true},
+		{"not 只作用于紧随的比较", `not city == "北京" or outsourcing`, true},
+		{"not 作用于括号", `not (city == "北京" or outsourcing)`, false},
+		{"双重否定", `!!outsourcing`, true},
+		{"符号与关键字混用", `city == "北京" && (salaryMin > 30 || outsourcing) AND NOT company`, true},
+
+		// 比较
+		{"数字比较", `salaryMin >= 25 and salaryMax < 40.5`, true},
+		{"负数", `salaryMin > -1`, true},
+		{"字符串相等忽略大小写与全半角", `jobName == "高级ＧＯ开发工程师"`, true},
+		{"字符串与数字不相等", `city == 1`, false},
+
+		// in 与 contains
+		{"in 列表命中", `companyScale in ["1000-9999人", "10000人以上"]`, true},
+		{"in 列表未命中", `city in ["上海", "深圳"]`, false},
+		{"in 空列表", `city in []`, false},
+		{"in 数字列表", `salaryMax in [30, 40]`, true},
+		{"contains 字符串忽略全半角", `jd contains "go微服务"`, true},
+		{"contains 列表任一命中", `jd contains ["外包", "派遣"]`, true},
+		{"contains 列表均未命中", `jd contains ["派遣", "996"]`, false},
+		{"contains 列表忽略空字符串", `jd contains ["", "派遣"]`, false},
+		{"contains 逗号分隔字段", `labels contains "kubernetes"`, true},
+
+		// matches
+		{"matches 字面量正则", `hrTitle matches "(?i)猎头|headhunter"`, true},
+		{"matches 字段作为正则", `hrActive matches hrActive`, true},
+
+		// 空值
+		{"空值等于 null", `aiScore == null`, true},
+		{"空值不等于 0", `aiScore == 0`, false},
+		{"非空值不等于 null", `salaryMax != null`, true},
+		{"空值不满足大小比较", `aiScore >= 70`, false},
+		{"空值不满足小于比较", `aiScore < 70`, false},
+		{"空值或评分达标", `aiScore == null or aiScore >= 70`, true},
+		{"空值字符串包含", `aiScore contains ""`, true},
+		{"未提供的字段视为空值", `district == null`, true},
+
+		// 真值
+		{"空字符串为假", `company`, false},
+		{"非空字符串为真", `city`, true},
+		{"布尔字面量", `true and not false`, true},
+		{"空值为假", `aiScore`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := CompileRuleExpression(tt.source)
+			if err != nil {
+				t.Fatalf("CompileRuleExpression(%q): %v", tt.source, err)
+			}
+			got, err := expr.Eval(env)
+			if err != nil {
+				t.Fatalf("Eval(%q): %v", tt.source, err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval(%q) = %v, 期望 %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleExpressionEvalErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		env     RuleEnv
+		wantErr string
+	}{
+		{"in 右侧不是列表", `city in "北京"`, RuleEnv{"city": "北京"}, "in 右侧必须为列表"},
+		{"字段中的正则无效", `jobName matches hrTitle`, RuleEnv{"jobName": "Go", "hrTitle": "(猎头"}, "正则无效"},
+		{"错误在逻辑运算中传递", `city == "北京" and city in "北京"`, RuleEnv{"city": "北京"}, "in 右侧必须为列表"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := CompileRuleExpression(tt.source)
+			if err != nil {
+				t.Fatalf("CompileRuleExpression(%q): %v", tt.source, err)
+			}
+			if _, err := expr.Eval(tt.env); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Eval(%q) err = %v, 期望包含 %q", tt.source, err, tt.wantErr)
+			}
+		})
+	}
+
+	// 短路求值时不会触发右侧的错误
+	expr, err := CompileRuleExpression(`city == "上海" and city in "北京"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := expr.Eval(RuleEnv{"city": "北京"}); err != nil || got {
+		t.Errorf("短路求值 = %v, %v", got, err)
+	}
+}
+
+func TestRuleExpressionFields(t *testing.T) {
+	expr, err := CompileRuleExpression(`salaryMax >= 30 and jd contains "外包" or salaryMax == null`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(expr.Fields(), ","); got != "jd,salaryMax" {
+		t.Errorf("Fields = %s", got)
+	}
+	if expr.NeedsAi() {
+		t.Error("未引用AI字段时 NeedsAi 应为 false")
+	}
+
+	expr, err = CompileRuleExpression(`aiScore == null or techStack contains "Go"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expr.NeedsAi() {
+		t.Error("引用AI字段时 NeedsAi 应为 true")
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"get_jobs_go/model"
+	"get_jobs_go/utils"
+	"log"
+	"time"
+)
+
+// WhitelistMatcher 白名单匹配器：公司类型匹配公司名称，职位类型匹配职位名称，关键词类型匹配职位名称或职位描述
+type WhitelistMatcher struct {
+	entries []*whitelistMatchEntry
+}
+
+type whitelistMatchEntry struct {
+	entity *model.WhitelistEntity
+	rule   *textMatchRule
+}
+
+// NewWhitelistMatcher 根据白名单条目创建匹配器（条目应按优先级从高到低排列），已过期或正则无效的条目不参与匹配
+func NewWhitelistMatcher(entities []*model.WhitelistEntity, now time.Time) *WhitelistMatcher {
+	matcher := &WhitelistMatcher{}
+	for _, entity := range entities {
+		if entity.IsExpired(now) {
+			continue
+		}
+
+		rule, err := newTextMatchRule(entity.Value, entity.MatchMode)
+		if err != nil {
+			log.Printf("白名单正则无效，已忽略 | 类型：%s | 值：%s | 错误：%v", entity.Type, entity.Value, err)
+			continue
+		}
+		if rule != nil {
+			matcher.entries = append(matcher.entries, &whitelistMatchEntry{entity: entity, rule: rule})
+		}
+	}
+	return matcher
+}
+
+// Match 返回岗位命中的第一个白名单条目，未命中返回 nil
+func (m *WhitelistMatcher) Match(job *model.Job) *model.WhitelistEntity {
+	if m == nil {
+		return nil
+	}
+
+	company := utils.NormalizeText(job.CompanyName)
+	jobName := utils.NormalizeText(job.JobName)
+	jobInfo := utils.NormalizeText(job.JobInfo)
+	for _, entry := range m.entries {
+		var hit bool
+		switch entry.entity.Type {
+		case model.WHITELIST_TYPE_COMPANY:
+			hit = company != "" && entry.rule.match(company)
+		case model.WHITELIST_TYPE_JOB:
+			hit = jobName != "" && entry.rule.match(jobName)
+		case model.WHITELIST_TYPE_KEYWORD:
+			hit = (jobName != "" && entry.rule.match(jobName)) || (jobInfo != "" && entry.rule.match(jobInfo))
+		}
+		if hit {
+			return entry.entity
+		}
+	}
+	return nil
+}
+
+// Len 参与匹配的条目数量
+func (m *WhitelistMatcher) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.entries)
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "math"
+
+// EARTH_RADIUS_KM 地球平均半径（千米）
+const EARTH_RADIUS_KM = 6371.0
+
+// HaversineKm 按 haversine 公式计算两个经纬度坐标之间的球面距离（千米）
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EARTH_RADIUS_KM * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ValidCoordinate 判断经纬度是否有效（超出范围或为 0,0 视为缺失）
+func ValidCoordinate(lat, lng float64) bool {
+	if lat == 0 && lng == 0 {
+		return false
+	}
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
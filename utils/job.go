@@ -8,58 +8,6 @@ import (
 	"time"
 )
 
-// Platform 平台枚举
-type Platform int
-
-const (
-	ZHILIAN Platform = iota
-	BOSS
-	OTHER
-)
-
-// Job 职位信息结构体
-type Job struct {
-	// 岗位链接
-	Href string `json:"href"`
-	// 岗位名称
-	JobName string `json:"jobName"`
-	// 岗位地区
-	JobArea string `json:"jobArea"`
-	// 岗位信息
-	JobInfo string `json:"jobInfo"`
-	// 岗位薪水
-	Salary string `json:"salary"`
-	// 公司标签
-	CompanyTag string `json:"companyTag"`
-	// HR名称
-	Recruiter string `json:"recruiter"`
-	// 公司名字
-	CompanyName string `json:"companyName"`
-	// 公司信息
-	CompanyInfo string `json:"companyInfo"`
-}
-
-// String 实现 Stringer 接口
-func (j *Job) String() string {
-	return fmt.Sprintf("【%s, %s, %s, %s, %s, %s】",
-		j.CompanyName, j.JobName, j.JobArea, j.Salary, j.CompanyTag, j.Recruiter)
-}
-
-// ToStringWithPlatform 根据平台格式化输出
-func (j *Job) ToStringWithPlatform(platform Platform) string {
-	switch platform {
-	case ZHILIAN:
-		return fmt.Sprintf("【%s, %s, %s, %s, %s, %s, %s】",
-			j.CompanyName, j.JobName, j.JobArea, j.CompanyTag, j.Salary, j.Recruiter, j.Href)
-	case BOSS:
-		return fmt.Sprintf("【%s, %s, %s, %s, %s, %s】",
-			j.CompanyName, j.JobName, j.JobArea, j.Salary, j.CompanyTag, j.Recruiter)
-	default:
-		return fmt.Sprintf("【%s, %s, %s, %s, %s, %s, %s】",
-			j.CompanyName, j.JobName, j.JobArea, j.Salary, j.CompanyTag, j.Recruiter, j.Href)
-	}
-}
-
 // UNLIMITED_CODE 不限选项的代码
 const UNLIMITED_CODE = "0"
 
@@ -208,21 +156,6 @@ func SleepRandom(minSeconds, maxSeconds int) {
 
 // 示例使用函数
 func ExampleUsage() {
-	// 创建一个职位对象
-	job := &Job{
-		CompanyName: "测试公司",
-		JobName:     "Go开发工程师",
-		JobArea:     "北京",
-		Salary:      "20-30K",
-		CompanyTag:  "互联网",
-		Recruiter:   "张HR",
-		Href:        "https://example.com/job/123",
-	}
-
-	// 输出职位信息
-	fmt.Println(job.String())
-	fmt.Println(job.ToStringWithPlatform(BOSS))
-
 	// 测试时间格式化
 	start := time.Now()
 	time.Sleep(2 * time.Second)
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 公司名中常见的法律形式后缀（按长度从长到短匹配）
+var companySuffixes = []string{
+	"股份有限公司",
+	"有限责任公司",
+	"有限公司",
+	"集团",
+	"公司",
+}
+
+// 括号及其内容，如 "腾讯科技(深圳)" 中的 "(深圳)"
+var bracketContentRegex = regexp.MustCompile(`[(\[【][^)\]】]*[)\]】]`)
+
+// ToHalfWidth 全角字符转半角（全角空格、全角字母数字和标点）
+func ToHalfWidth(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '　':
+			b.WriteRune(' ')
+		case r >= '！' && r <= '～':
+			b.WriteRune(r - 0xFEE0)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// NormalizeText 文本规范化：全角转半角、转小写、去除首尾空白并合并连续空白
+func NormalizeText(s string) string {
+	s = strings.ToLower(ToHalfWidth(s))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// NormalizeForKey 生成用于比较的紧凑形式：在 NormalizeText 基础上去掉括号内容、空白和标点
+func NormalizeForKey(s string) string {
+	s = bracketContentRegex.ReplaceAllString(NormalizeText(s), "")
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// NormalizeCompanyName 公司名规范化：去掉括号内的地区信息和法律形式后缀
+func NormalizeCompanyName(name string) string {
+	key := NormalizeForKey(name)
+	for _, suffix := range companySuffixes {
+		if strings.HasSuffix(key, suffix) && len(key) > len(suffix) {
+			key = strings.TrimSuffix(key, suffix)
+			break
+		}
+	}
+	return key
+}
+
+// NormalizeCity 城市规范化：只保留城市部分（"深圳·南山区" → "深圳"），并去掉 "市" 后缀
+func NormalizeCity(city string) string {
+	city = NormalizeText(city)
+	for _, sep := range []string{"·", "-", " ", ","} {
+		if idx := strings.Index(city, sep); idx > 0 {
+			city = city[:idx]
+		}
+	}
+	city = strings.TrimSuffix(city, "市")
+	return NormalizeForKey(city)
+}
+
+// JobDedupeKey 职位去重键：规范化的 公司|职位|城市，公司或职位为空时返回空字符串
+func JobDedupeKey(companyName, jobName, city string) string {
+	company := NormalizeCompanyName(companyName)
+	title := NormalizeForKey(jobName)
+	if company == "" || title == "" {
+		return ""
+	}
+	return strings.Join([]string{company, title, NormalizeCity(city)}, "|")
+}
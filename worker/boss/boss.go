@@ -2,6 +2,7 @@ package boss
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
@@ -9,31 +10,51 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"get_jobs_go/config"
+	"get_jobs_go/model"
 	"get_jobs_go/service"
 	"get_jobs_go/utils"
 
 	"github.com/playwright-community/playwright-go"
 )
 
+// BOSS_ORIGIN Boss直聘站点地址
+const BOSS_ORIGIN = "https://www.zhipin.com"
+
+// BOSS_DETAIL_API_URL 岗位详情接口地址
+const BOSS_DETAIL_API_URL = BOSS_ORIGIN + "/wapi/zpgeek/job/detail.json"
+
 // Boss 结构体对应Java的Boss类
 type Boss struct {
 	page               playwright.Page
+	capture            *NetworkCapture
 	config             *config.BossConfig
 	bossService        *service.BossService
 	aiService          *service.AiService
-	blackCompanies     map[string]bool
-	blackRecruiters    map[string]bool
-	blackJobs          map[string]bool
-	encryptIdToUserId  sync.Map
+	jobService         *service.JobService
+	filterRuleService  *service.FilterRuleService
+	filterRules        []*service.FilterRule
+	blackCompanies     *service.BlacklistMatcher
+	blackRecruiters    *service.BlacklistMatcher
+	blackJobs          *service.BlacklistMatcher
+	whitelist          *service.WhitelistMatcher
+	classifier         *service.RecruiterClassifier
+	commute            *service.CommuteFilter
+	jdKeywords         *service.JdKeywordFilter
+	seenJobs           map[string]string      // 本次运行已处理的岗位（encryptId -> 首次搜到它的关键词）
+	duplicates         int                    // 本次运行在其他关键词/城市下重复出现的岗位数
+	listedJobs         map[string]JobListItem // 搜索列表接口返回的岗位（encryptJobId -> 岗位），用于补全详情请求参数
 	progressCallback   ProgressCallback
 	shouldStopCallback func() bool
-	resultList         []*utils.Job
+	resultList         []*model.Job
+	runId              string
+	aiDisabledReported bool
 	mu                 sync.RWMutex
 }
 
@@ -44,14 +65,15 @@ type ProgressCallback func(message string, current, total int)
 func NewBoss(
 	bossService *service.BossService,
 	aiService *service.AiService,
+	jobService *service.JobService,
+	filterRuleService *service.FilterRuleService,
 ) *Boss {
 	return &Boss{
-		bossService:     bossService,
-		aiService:       aiService,
-		blackCompanies:  make(map[string]bool),
-		blackRecruiters: make(map[string]bool),
-		blackJobs:       make(map[string]bool),
-		resultList:      make([]*utils.Job, 0),
+		bossService:       bossService,
+		aiService:         aiService,
+		jobService:        jobService,
+		filterRuleService: filterRuleService,
+		resultList:        make([]*model.Job, 0),
 	}
 }
 
@@ -60,11 +82,21 @@ func (b *Boss) SetPage(page playwright.Page) {
 	b.page = page
 }
 
+// SetCapture 设置页面上的网络捕获器（岗位列表与详情接口响应通过订阅获取）
+func (b *Boss) SetCapture(capture *NetworkCapture) {
+	b.capture = capture
+}
+
 // SetConfig 设置配置
 func (b *Boss) SetConfig(config *config.BossConfig) {
 	b.config = config
 }
 
+// SetRunId 设置运行ID（用于AI用量统计）
+func (b *Boss) SetRunId(runId string) {
+	b.runId = runId
+}
+
 // SetProgressCallback 设置进度回调
 func (b *Boss) SetProgressCallback(callback ProgressCallback) {
 	b.progressCallback = callback
@@ -75,7 +107,7 @@ func (b *Boss) SetShouldStopCallback(callback func() bool) {
 	b.shouldStopCallback = callback
 }
 
-// Prepare 准备阶段：加载黑名单
+// Prepare 准备阶段：加载黑名单、白名单与过滤规则
 func (b *Boss) Prepare() error {
 	// 从数据库加载黑名单
 	blackCompanies, err := b.bossService.GetBlackCompanies()
@@ -97,7 +129,37 @@ func (b *Boss) Prepare() error {
 	b.blackJobs = blackJobs
 
 	log.Printf("黑名单加载完成: 公司(%d) 招聘者(%d) 职位(%d)",
-		len(b.blackCompanies), len(b.blackRecruiters), len(b.blackJobs))
+		b.blackCompanies.Len(), b.blackRecruiters.Len(), b.blackJobs.Len())
+
+	whitelist, err := b.bossService.GetWhitelist()
+	if err != nil {
+		return fmt.Errorf("加载白名单失败: %v", err)
+	}
+	b.whitelist = whitelist
+	log.Printf("白名单加载完成: %d 条", b.whitelist.Len())
+
+	b.classifier = service.NewRecruiterClassifier(b.config.HunterCompanies, b.config.AgencyCompanies)
+	b.commute = service.NewCommuteFilter(b.config.CommutePoints, b.config.MaxCommuteKm, b.config.Districts)
+	b.jdKeywords = service.NewJdKeywordFilter(b.config.JdInclude, b.config.JdExclude)
+
+	filterRules, err := b.filterRuleService.LoadRules(b.config)
+	if err != nil {
+		return fmt.Errorf("加载过滤规则失败: %v", err)
+	}
+	b.filterRules = filterRules
+	log.Printf("过滤规则加载完成: %d 条", len(b.filterRules))
+
+	// 每次运行重新开始岗位去重
+	b.seenJobs = make(map[string]string)
+	b.duplicates = 0
+	b.listedJobs = make(map[string]JobListItem)
+	if b.capture == nil {
+		return fmt.Errorf("未设置网络捕获器")
+	}
+
+	// 每次运行重新启用AI调用，并按运行ID统计AI用量
+	b.aiService.BeginRun(b.runId)
+	b.aiDisabledReported = false
 
 	return nil
 }
@@ -116,7 +178,7 @@ func (b *Boss) Execute() int {
 			break
 		}
 
-		count := b.postJobByCity(cityCode)
+		count := b.postJobByCity(b.resolveCityCode(cityCode))
 		totalCount += count
 
 		if b.shouldStopCallback != nil && b.shouldStopCallback() {
@@ -125,15 +187,25 @@ func (b *Boss) Execute() int {
 		}
 	}
 
+	if b.duplicates > 0 {
+		b.progressCallback(fmt.Sprintf("本次运行跳过重复岗位 %d 个（多个关键词/城市搜到同一岗位）", b.duplicates), 0, 0)
+	}
+
+	cacheStats := b.aiService.GetCacheStats()
+	log.Printf("AI缓存统计：命中 %d 次，未命中 %d 次", cacheStats.Hits, cacheStats.Misses)
+	if runCost, err := b.aiService.GetRunCost(b.runId); err == nil && runCost > 0 {
+		b.progressCallback(fmt.Sprintf("本次运行AI费用：%.4f", runCost), 0, 0)
+	}
+
 	return totalCount
 }
 
 // GetResultList 获取结果列表
-func (b *Boss) GetResultList() []*utils.Job {
+func (b *Boss) GetResultList() []*model.Job {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	result := make([]*utils.Job, len(b.resultList))
+	result := make([]*model.Job, len(b.resultList))
 	copy(result, b.resultList)
 	return result
 }
@@ -161,6 +233,10 @@ func (b *Boss) postJobsByKeyword(searchUrl, keyword string) int {
 	encodedKeyword := url.QueryEscape(keyword)
 	fullUrl := searchUrl + "&query=" + encodedKeyword
 
+	// 收集搜索列表接口返回的岗位，补全卡片链接中缺少的详情请求参数
+	jobList := b.capture.Subscribe(ENDPOINT_JOB_LIST, nil)
+	defer jobList.Cancel()
+
 	// 导航到搜索页面
 	_, err := b.page.Goto(fullUrl, playwright.PageGotoOptions{
 		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
@@ -181,7 +257,8 @@ func (b *Boss) postJobsByKeyword(searchUrl, keyword string) int {
 	}
 
 	// 滚动加载所有岗位
-	b.scrollToLoadAllJobs(keyword)
+	b.scrollToLoadAllJobs(keyword, jobList)
+	b.collectJobList(jobList)
 
 	// 获取最终岗位数量
 	cards, err := b.page.QuerySelectorAll("//ul[contains(@class, 'rec-job-list')]//li[contains(@class, 'job-card-box')]")
@@ -198,30 +275,70 @@ func (b *Boss) postJobsByKeyword(searchUrl, keyword string) int {
 	b.page.Evaluate("window.scrollTo(0, 0);")
 	utils.Sleep(1)
 
-	// 逐个处理岗位
+	// 先解析并过滤岗位，收集待投递岗位（配置了详情并发数时通过页面池并发获取详情）
+	var candidates []*model.Job
+	var stopped bool
+	if b.config.DetailConcurrency > 1 {
+		candidates, stopped = b.screenJobsConcurrently(keyword, loadedCount)
+	} else {
+		candidates, stopped = b.screenJobs(keyword, loadedCount)
+	}
+	if stopped {
+		return 0
+	}
+
+	// 按过滤规则累计的优先级从高到低投递，优先级相同时保持页面顺序
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Priority > candidates[j].Priority
+	})
+
 	postCount := 0
+	for i, job := range candidates {
+		if b.shouldStopCallback != nil && b.shouldStopCallback() {
+			b.progressCallback("用户取消投递", i, len(candidates))
+			return postCount
+		}
+
+		b.progressCallback("正在投递："+job.JobName, i+1, len(candidates))
+		if b.resumeSubmission(keyword, job) {
+			postCount++
+		}
+	}
+
+	return postCount
+}
+
+// screenJobs 逐个点击卡片解析并过滤岗位，返回待投递岗位，用户取消时返回 true
+func (b *Boss) screenJobs(keyword string, loadedCount int) ([]*model.Job, bool) {
+	var candidates []*model.Job
 	for i := 0; i < loadedCount; i++ {
 		if b.shouldStopCallback != nil && b.shouldStopCallback() {
 			b.progressCallback("用户取消投递", i, loadedCount)
-			return postCount
+			return nil, true
 		}
 
 		// 重新获取卡片避免元素过期
-		cards, err = b.page.QuerySelectorAll("//ul[contains(@class, 'rec-job-list')]//li[contains(@class, 'job-card-box')]")
+		cards, err := b.page.QuerySelectorAll("//ul[contains(@class, 'rec-job-list')]//li[contains(@class, 'job-card-box')]")
 		if err != nil || i >= len(cards) {
 			continue
 		}
 
-		job, shouldSkip := b.processJobCard(cards[i], i, loadedCount)
-		if shouldSkip {
+		b.progressCallback("正在筛选岗位", i+1, loadedCount)
+
+		// 本次运行已处理过的岗位只记录关键词，不再打开详情
+		encryptId, jobName := b.cardJobId(cards[i])
+		if b.markSeen(encryptId, jobName, keyword) {
+			continue
+		}
+
+		// 已投递或已沟通的岗位不再打开详情
+		if b.isJobContacted(encryptId, jobName) {
 			continue
 		}
 
-		// 投递简历
-		b.progressCallback("正在投递："+job.JobName, i+1, loadedCount)
-		success := b.resumeSubmission(keyword, job)
-		if success {
-			postCount++
+		job, shouldSkip := b.processJobCard(cards[i], keyword, i, loadedCount)
+		if !shouldSkip {
+			candidates = append(candidates, job)
 		}
 
 		// 滚动避免页面刷新问题
@@ -230,15 +347,276 @@ func (b *Boss) postJobsByKeyword(searchUrl, keyword string) int {
 			utils.Sleep(1)
 		}
 	}
+	return candidates, false
+}
 
-	return postCount
+// screenJobsConcurrently 通过页面池并发获取岗位详情并过滤，待投递岗位保持页面顺序，用户取消时返回 true。
+// 卡片按顺序分发（去重与已沟通检查仍逐个进行），页面全部占用时分发阻塞；
+// 无法直接获取详情的岗位（缺少参数、请求失败或超时）最后回退为逐个点击卡片
+func (b *Boss) screenJobsConcurrently(keyword string, loadedCount int) ([]*model.Job, bool) {
+	cards, err := b.page.QuerySelectorAll("//ul[contains(@class, 'rec-job-list')]//li[contains(@class, 'job-card-box')]")
+	if err != nil {
+		log.Printf("获取岗位卡片失败: %v", err)
+		return nil, false
+	}
+
+	pool := NewPagePool(b.page.Context(), b.config.DetailConcurrency, time.Duration(b.config.DetailTimeout)*time.Second)
+	defer pool.Close()
+	go b.closeOnStop(pool)
+	log.Printf("【%s】并发获取岗位详情，页面数: %d，超时: %v", keyword, pool.Size(), pool.timeout)
+
+	results := make([]*model.Job, len(cards))
+	workers := make(chan struct{}, pool.Size())
+	var fallback []int
+	var fallbackMu sync.Mutex
+	var dispatched int
+	var wg sync.WaitGroup
+
+	addFallback := func(index int) {
+		fallbackMu.Lock()
+		fallback = append(fallback, index)
+		fallbackMu.Unlock()
+	}
+
+dispatch:
+	for i, card := range cards {
+		select {
+		case <-pool.Done():
+			break dispatch
+		default:
+		}
+
+		// 进度按分发顺序汇报（与逐个点击时一致），不随并发筛选的完成顺序跳动
+		dispatched = i + 1
+		b.progressCallback("正在筛选岗位", dispatched, loadedCount)
+
+		// 本次运行已处理过的岗位只记录关键词，已投递或已沟通的岗位不再获取详情
+		encryptId, jobName := b.cardJobId(card)
+		if b.markSeen(encryptId, jobName, keyword) || b.isJobContacted(encryptId, jobName) {
+			continue
+		}
+
+		detailUrl := b.jobDetailApiUrl(card)
+		if detailUrl == "" {
+			addFallback(i)
+			continue
+		}
+
+		// 详情获取与筛选都占用名额，避免AI评估堆积
+		select {
+		case workers <- struct{}{}:
+		case <-pool.Done():
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func(index int, detailUrl string) {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			body, err := RunWithPage(pool, func(page playwright.Page) ([]byte, error) {
+				return b.fetchJobDetail(page, detailUrl)
+			})
+			if err != nil {
+				if !errors.Is(err, ErrPagePoolClosed) {
+					log.Printf("并发获取岗位详情失败，稍后逐个点击 | 链接：%s | 错误：%v", detailUrl, err)
+					addFallback(index)
+				}
+				return
+			}
+
+			job := b.parseJobDetail(body)
+			if job == nil {
+				addFallback(index)
+				return
+			}
+
+			if job, shouldSkip := b.assessJob(job, keyword); !shouldSkip {
+				results[index] = job
+			}
+		}(i, detailUrl)
+	}
+	wg.Wait()
+
+	if b.shouldStopCallback != nil && b.shouldStopCallback() {
+		b.progressCallback("用户取消投递", dispatched, loadedCount)
+		return nil, true
+	}
+
+	// 回退：按页面顺序逐个点击卡片
+	sort.Ints(fallback)
+	for _, i := range fallback {
+		if b.shouldStopCallback != nil && b.shouldStopCallback() {
+			b.progressCallback("用户取消投递", i, loadedCount)
+			return nil, true
+		}
+
+		cards, err = b.page.QuerySelectorAll("//ul[contains(@class, 'rec-job-list')]//li[contains(@class, 'job-card-box')]")
+		if err != nil || i >= len(cards) {
+			continue
+		}
+
+		b.progressCallback("重新筛选岗位", i+1, loadedCount)
+		if job, shouldSkip := b.processJobCard(cards[i], keyword, i, len(cards)); !shouldSkip {
+			results[i] = job
+		}
+	}
+
+	var candidates []*model.Job
+	for _, job := range results {
+		if job != nil {
+			candidates = append(candidates, job)
+		}
+	}
+	return candidates, false
+}
+
+// closeOnStop 停止指令触发后关闭页面池，等待中的分发与进行中的详情请求随之结束
+func (b *Boss) closeOnStop(pool *PagePool) {
+	if b.shouldStopCallback == nil {
+		return
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pool.Done():
+			return
+		case <-ticker.C:
+			if b.shouldStopCallback() {
+				pool.Close()
+				return
+			}
+		}
+	}
+}
+
+// jobDetailApiUrl 根据卡片的 securityId 与 lid 构建岗位详情接口地址（缺少 securityId 时返回空）
+func (b *Boss) jobDetailApiUrl(card playwright.ElementHandle) string {
+	securityId, lid := b.cardSecurityParams(card)
+	if securityId == "" {
+		return ""
+	}
+	params := url.Values{}
+	params.Set("securityId", securityId)
+	params.Set("lid", lid)
+	return BOSS_DETAIL_API_URL + "?" + params.Encode()
+}
+
+// cardSecurityParams 获取卡片岗位的 securityId 与 lid：优先取卡片链接中的参数，缺少时使用搜索列表接口返回的值
+func (b *Boss) cardSecurityParams(card playwright.ElementHandle) (string, string) {
+	link, err := card.QuerySelector("a.job-name")
+	if err != nil || link == nil {
+		return "", ""
+	}
+	href, _ := link.GetAttribute("href")
+	parsed, err := url.Parse(href)
+	if err != nil {
+		return "", ""
+	}
+
+	securityId, lid := parsed.Query().Get("securityId"), parsed.Query().Get("lid")
+	if securityId == "" {
+		if item, ok := b.listedJobs[b.extractEncryptId(href)]; ok {
+			securityId, lid = item.SecurityId, item.Lid
+		}
+	}
+	return securityId, lid
+}
+
+// fetchJobDetail 在页面池的页面中携带登录Cookie请求岗位详情接口，返回响应内容
+func (b *Boss) fetchJobDetail(page playwright.Page, detailUrl string) ([]byte, error) {
+	// 新页面先进入站点域名，使请求与卡片点击一样为同源请求
+	if !strings.HasPrefix(page.URL(), BOSS_ORIGIN) {
+		if _, err := page.Goto(BOSS_ORIGIN+"/robots.txt", playwright.PageGotoOptions{
+			WaitUntil: playwright.WaitUntilStateDomcontentloaded,
+		}); err != nil {
+			return nil, fmt.Errorf("打开站点页面失败: %w", err)
+		}
+	}
+
+	result, err := page.Evaluate(`async (url) => {
+		const response = await fetch(url, { credentials: "include", headers: { "Accept": "application/json" } });
+		if (!response.ok) {
+			throw new Error("HTTP " + response.status);
+		}
+		return await response.text();
+	}`, detailUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	body, ok := result.(string)
+	if !ok || body == "" {
+		return nil, fmt.Errorf("岗位详情响应为空")
+	}
+	b.capture.Record(ENDPOINT_JOB_DETAIL, []byte(body))
+	return []byte(body), nil
+}
+
+// cardJobId 从卡片链接中解析encryptId与岗位名称
+func (b *Boss) cardJobId(card playwright.ElementHandle) (string, string) {
+	link, err := card.QuerySelector("a.job-name")
+	if err != nil || link == nil {
+		return "", ""
+	}
+	href, _ := link.GetAttribute("href")
+	name, _ := link.TextContent()
+	return b.extractEncryptId(href), strings.TrimSpace(name)
+}
+
+// markSeen 记录本次运行处理过的岗位，岗位已处理过时追加搜索关键词并返回 true
+func (b *Boss) markSeen(encryptId, jobName, keyword string) bool {
+	if encryptId == "" {
+		return false
+	}
+
+	firstKeyword, seen := b.seenJobs[encryptId]
+	if !seen {
+		b.seenJobs[encryptId] = keyword
+		return false
+	}
+
+	b.duplicates++
+	log.Printf("跳过重复岗位 | 岗位：%s | encryptId：%s | 首次关键词：%s | 当前关键词：%s", jobName, encryptId, firstKeyword, keyword)
+	if err := b.jobService.AddHitKeyword(model.PLATFORM_BOSS, encryptId, keyword); err != nil {
+		log.Printf("记录岗位搜索关键词失败: %v", err)
+	}
+	return true
+}
+
+// isJobContacted 判断岗位是否已投递或已沟通
+func (b *Boss) isJobContacted(encryptId, jobName string) bool {
+	contacted, err := b.bossService.IsBossJobContacted(encryptId)
+	if err != nil {
+		log.Printf("查询岗位投递状态失败 | encryptId：%s | 错误：%v", encryptId, err)
+		return false
+	}
+	if contacted {
+		log.Printf("跳过已投递/已沟通的岗位 | 岗位：%s | encryptId：%s", jobName, encryptId)
+	}
+	return contacted
 }
 
 // processJobCard 处理单个岗位卡片
-func (b *Boss) processJobCard(card playwright.ElementHandle, index, total int) (*utils.Job, bool) {
-	var detailResp *playwright.Response
+func (b *Boss) processJobCard(card playwright.ElementHandle, keyword string, index, total int) (*model.Job, bool) {
+	// 先订阅当前卡片的详情响应再点击卡片：按 securityId 关联，卡片缺少 securityId 时按响应中的 encryptId 关联，
+	// 两者都没有时无法区分其他卡片的响应（如下方先点击的第一个卡片），直接跳过
+	encryptId, jobName := b.cardJobId(card)
+	securityId, _ := b.cardSecurityParams(card)
+	var match ResponseMatcher
+	switch {
+	case securityId != "":
+		match = MatchParam("securityId", securityId)
+	case encryptId != "":
+		match = MatchJobDetail(encryptId)
+	default:
+		log.Printf("跳过岗位：卡片缺少 securityId 与 encryptId，无法关联岗位详情 | 岗位：%s", jobName)
+		return nil, true
+	}
+	detail := b.capture.Subscribe(ENDPOINT_JOB_DETAIL, match)
 
-	// 点击卡片并等待详情响应
 	if index == 0 && total > 1 {
 		// 第一个卡片特殊处理
 		secondCard, err := b.page.QuerySelector("//ul[contains(@class, 'rec-job-list')]//li[contains(@class, 'job-card-box')]")
@@ -248,139 +626,353 @@ func (b *Boss) processJobCard(card playwright.ElementHandle, index, total int) (
 		}
 	}
 
-	// 设置响应监听
-	responseChan := make(chan *playwright.Response, 1)
-	b.page.OnResponse(func(response playwright.Response) {
-		url := response.URL()
-		if strings.Contains(url, "/wapi/zpgeek/job/detail.json") &&
-			strings.EqualFold(response.Request().Method(), "GET") {
-			select {
-			case responseChan <- &response:
-			default:
-			}
-		}
-	})
-
-	// 点击当前卡片
+	// 点击当前卡片并等待详情响应
 	card.Click()
 
-	// 等待响应或超时
-	select {
-	case detailResp = <-responseChan:
-	case <-time.After(5 * time.Second):
+	response, err := detail.Await(5 * time.Second)
+	if err != nil {
+		log.Printf("获取岗位详情失败: %v", err)
+		return nil, true
 	}
 
-	b.page.RemoveListeners("response")
-
 	// 解析岗位详情
-	job, shouldSkip := b.parseJobDetail(detailResp)
-	return job, shouldSkip
+	job := b.parseJobDetail(response.Body)
+	if job == nil {
+		return nil, true
+	}
+	return b.assessJob(job, keyword)
 }
 
-// parseJobDetail 解析岗位详情
-func (b *Boss) parseJobDetail(detailResp *playwright.Response) (*utils.Job, bool) {
-	if detailResp == nil {
+// assessJob 过滤并评估已解析的岗位，保存岗位记录，返回待投递岗位（应跳过时返回 true）
+func (b *Boss) assessJob(job *model.Job, keyword string) (*model.Job, bool) {
+	job.HitKeywords = keyword
+
+	// 白名单岗位优先投递，且不受软过滤（HR活跃状态、自定义规则、AI属性与评分）影响
+	if entry := b.whitelist.Match(job); entry != nil {
+		job.WhitelistHit = entry.Label()
+		job.Priority += entry.Priority
+		log.Printf("命中白名单 %s | 公司：%s | 岗位：%s | 优先级：%d", job.WhitelistHit, job.CompanyName, job.JobName, job.Priority)
+	}
+
+	// 过滤检查
+	if reason := b.shouldFilterJob(job); reason != "" {
+		b.saveJob(job, model.DELIVERY_STATUS_FILTERED, reason)
 		return nil, true
 	}
 
-	body, err := (*detailResp).Body()
-	if err != nil {
+	// AI提取职位描述结构化属性并按属性过滤
+	if reason := b.softFilter(job, b.extractJobAttributes(job)); reason != "" {
+		b.saveJob(job, model.DELIVERY_STATUS_FILTERED, reason)
 		return nil, true
 	}
 
-	// 解析JSON响应
-	var responseData map[string]interface{}
-	if err := json.Unmarshal(body, &responseData); err != nil {
+	// AI匹配度评分（在打开聊天之前）
+	if reason := b.softFilter(job, b.scoreJob(job)); reason != "" {
+		b.saveJob(job, model.DELIVERY_STATUS_FILTERED, reason)
 		return nil, true
 	}
 
-	// 提取岗位信息
-	zpData, _ := responseData["zpData"].(map[string]interface{})
-	if zpData == nil {
+	// 执行依赖AI评分与AI属性的过滤规则
+	if reason := b.softFilter(job, b.applyFilterRules(job, true)); reason != "" {
+		b.saveJob(job, model.DELIVERY_STATUS_FILTERED, reason)
 		return nil, true
 	}
 
-	jobInfo, _ := zpData["jobInfo"].(map[string]interface{})
-	brandInfo, _ := zpData["brandComInfo"].(map[string]interface{})
-	bossInfo, _ := zpData["bossInfo"].(map[string]interface{})
+	// 记录岗位（保留已有的投递状态）
+	b.saveJob(job, "", "")
+	return job, false
+}
 
-	// 构建Job对象
-	job := &utils.Job{
-		JobName:     b.getStringValue(jobInfo, "jobName"),
-		Salary:      b.getStringValue(jobInfo, "salaryDesc"),
-		CompanyName: b.getStringValue(brandInfo, "brandName"),
-		Recruiter:   b.getStringValue(bossInfo, "name"),
-		JobInfo:     b.getStringValue(jobInfo, "postDescription"),
+// parseJobDetail 解析岗位详情接口的响应，映射为统一职位模型
+func (b *Boss) parseJobDetail(body []byte) *model.Job {
+	if len(body) == 0 {
+		return nil
+	}
+
+	detail, err := DecodeWapi[JobDetailData](body)
+	if err != nil {
+		log.Printf("解析岗位详情失败: %v", err)
+		return nil
 	}
+	jobInfo, brandInfo, bossInfo := detail.JobInfo, detail.BrandComInfo, detail.BossInfo
+
+	// 构建Job对象
+	job := &model.Job{
+		Platform:       model.PLATFORM_BOSS,
+		PlatformJobId:  jobInfo.EncryptId,
+		PlatformUserId: jobInfo.EncryptUserId,
+		JobName:        jobInfo.JobName,
+		Salary:         jobInfo.SalaryDesc,
+		City:           jobInfo.LocationName,
+		Experience:     jobInfo.ExperienceName,
+		Degree:         jobInfo.DegreeName,
+		JobInfo:        jobInfo.PostDescription,
+		JobLabels:      strings.Join(jobInfo.ShowSkills, ","),
+		CompanyName:    brandInfo.BrandName,
+		CompanyInfo:    brandInfo.Introduce,
+		Industry:       brandInfo.IndustryName,
+		CompanyScale:   brandInfo.ScaleName,
+		FinancingStage: brandInfo.StageName,
+		Recruiter:      bossInfo.Name,
+		RecruiterTitle: bossInfo.Title,
+		HrActiveStatus: bossInfo.ActiveTimeDesc,
+		Address:        jobInfo.Address,
+		District:       service.JoinDistrict(jobInfo.AreaDistrict, jobInfo.BusinessDistrict),
+		Longitude:      jobInfo.Longitude,
+		Latitude:       jobInfo.Latitude,
+	}
+	if jobInfo.EncryptId != "" {
+		job.Href = BOSS_ORIGIN + "/job_detail/" + jobInfo.EncryptId + ".html"
+	}
+
+	// 解析HR活跃度
+	if activity, ok := service.ParseHrActivity(job.HrActiveStatus); ok {
+		days := activity.Days
+		job.HrActivity = activity.Code
+		job.HrInactiveDays = &days
+	}
+
+	// 通勤距离
+	b.commute.Locate(job)
+
+	// 识别招聘方类型（猎头/外包/直招）
+	job.RecruiterType, job.RecruiterReason = b.classifier.Classify(&service.RecruiterProfile{
+		HrTitle:     job.RecruiterTitle,
+		HrCompany:   bossInfo.BrandName,
+		CompanyName: job.CompanyName,
+		Industry:    job.Industry,
+		ProxyJob:    jobInfo.ProxyJob == 1,
+	})
 
 	// 构建工作地区
 	var tags []string
-	if location := b.getStringValue(jobInfo, "locationName"); location != "" {
-		tags = append(tags, location)
+	if job.City != "" {
+		tags = append(tags, job.City)
 	}
-	if experience := b.getStringValue(jobInfo, "experienceName"); experience != "" {
-		tags = append(tags, experience)
+	if job.Experience != "" {
+		tags = append(tags, job.Experience)
 	}
-	if degree := b.getStringValue(jobInfo, "degreeName"); degree != "" {
-		tags = append(tags, degree)
+	if job.Degree != "" {
+		tags = append(tags, job.Degree)
 	}
 	job.JobArea = strings.Join(tags, ", ")
 
-	// 过滤检查
-	if b.shouldFilterJob(job, bossInfo) {
-		return nil, true
+	return job
+}
+
+// shouldFilterJob 检查是否应该过滤该岗位，返回过滤原因（为空表示不过滤）
+// 黑名单与跨平台去重对白名单岗位同样生效
+func (b *Boss) shouldFilterJob(job *model.Job) string {
+	// 职位黑名单过滤
+	if entry := b.blackJobs.Match(job.JobName); entry != nil {
+		log.Printf("被过滤：职位黑名单命中 | 公司：%s | 岗位：%s | 条目：%s(%s) | 原因：%s",
+			job.CompanyName, job.JobName, entry.Value, entry.MatchMode, entry.Reason)
+		return model.FILTER_REASON_JOB_BLACKLIST
 	}
 
-	return job, false
+	// 公司黑名单过滤
+	if entry := b.blackCompanies.Match(job.CompanyName); entry != nil {
+		log.Printf("被过滤：公司黑名单命中 | 公司：%s | 岗位：%s | 条目：%s(%s) | 原因：%s",
+			job.CompanyName, job.JobName, entry.Value, entry.MatchMode, entry.Reason)
+		return model.FILTER_REASON_COMPANY_BLACKLIST
+	}
+
+	// 招聘者黑名单过滤
+	if entry := b.blackRecruiters.Match(job.RecruiterTitle); entry != nil {
+		log.Printf("被过滤：招聘者黑名单命中 | 公司：%s | 岗位：%s | 招聘者：%s | 条目：%s(%s) | 原因：%s",
+			job.CompanyName, job.JobName, job.RecruiterTitle, entry.Value, entry.MatchMode, entry.Reason)
+		return model.FILTER_REASON_RECRUITER_BLACKLIST
+	}
+
+	// 已与该HR投递或沟通过（同一HR只有一个会话，不重复打招呼）
+	contacted, err := b.bossService.IsRecruiterContacted(job.PlatformUserId)
+	if err != nil {
+		log.Printf("查询招聘者沟通状态失败: %v", err)
+	} else if contacted {
+		log.Printf("被过滤：已与该HR沟通过 | 公司：%s | 岗位：%s | 招聘者：%s", job.CompanyName, job.JobName, job.Recruiter)
+		return model.FILTER_REASON_HR_CONTACTED
+	}
+
+	// 猎头与外包/派遣机构过滤（白名单岗位不受影响）
+	if reason := b.softFilter(job, b.filterByRecruiterType(job)); reason != "" {
+		return reason
+	}
+
+	// 职位描述关键词过滤（白名单岗位不受影响）
+	if reason := b.softFilter(job, b.filterByJdKeywords(job)); reason != "" {
+		return reason
+	}
+
+	// 通勤距离与商圈过滤（白名单岗位不受影响）
+	if reason := b.softFilter(job, b.filterByLocation(job)); reason != "" {
+		return reason
+	}
+
+	// 跨平台去重：同一职位已在其他平台投递过
+	duplicate, err := b.jobService.FindCrossPlatformDuplicate(job)
+	if err != nil {
+		log.Printf("跨平台去重检查失败: %v", err)
+	} else if duplicate != nil {
+		log.Printf("被过滤：已在%s平台投递过相同职位 | 公司：%s | 岗位：%s",
+			duplicate.Platform, job.CompanyName, job.JobName)
+		return model.FILTER_REASON_DUPLICATE
+	}
+
+	// 自定义过滤规则（HR活跃状态过滤也以内置规则的形式执行）
+	return b.softFilter(job, b.applyFilterRules(job, false))
 }
 
-// shouldFilterJob 检查是否应该过滤该岗位
-func (b *Boss) shouldFilterJob(job *utils.Job, bossInfo map[string]interface{}) bool {
-	// 职位黑名单过滤
-	if b.isInBlacklist(job.JobName, b.blackJobs) {
-		log.Printf("被过滤：职位黑名单命中 | 公司：%s | 岗位：%s", job.CompanyName, job.JobName)
-		return true
+// filterByRecruiterType 按招聘方类型过滤，返回过滤原因（为空表示不过滤）
+func (b *Boss) filterByRecruiterType(job *model.Job) string {
+	switch {
+	case b.config.FilterHeadhunter && job.RecruiterType == model.RECRUITER_TYPE_HEADHUNTER:
+		log.Printf("被过滤：猎头招聘 | 公司：%s | 岗位：%s | 依据：%s", job.CompanyName, job.JobName, job.RecruiterReason)
+		return model.FILTER_REASON_HEADHUNTER
+	case b.config.FilterAgency && job.RecruiterType == model.RECRUITER_TYPE_OUTSOURCING:
+		log.Printf("被过滤：外包/派遣机构招聘 | 公司：%s | 岗位：%s | 依据：%s", job.CompanyName, job.JobName, job.RecruiterReason)
+		return model.FILTER_REASON_AGENCY
 	}
+	return ""
+}
 
-	// HR活跃状态过滤
-	if b.config.FilterDeadHR {
-		activeTime := b.getStringValue(bossInfo, "activeTimeDesc")
-		if strings.Contains(activeTime, "年") {
-			log.Printf("被过滤：HR活跃状态包含'年' | 公司：%s | 岗位：%s | 活跃：%s",
-				job.CompanyName, job.JobName, activeTime)
-			return true
-		}
+// filterByJdKeywords 按职位描述与标签的包含/排除关键词过滤，命中的关键词记录到岗位上
+func (b *Boss) filterByJdKeywords(job *model.Job) string {
+	result := b.jdKeywords.Check(job)
+	job.JdHighlights = strings.Join(result.Highlights, ",")
+	if result.Reason != "" {
+		log.Printf("被过滤：职位描述关键词 %s | 公司：%s | 岗位：%s | 命中：%s",
+			result.Reason, job.CompanyName, job.JobName, job.JdHighlights)
 	}
+	return result.Reason
+}
 
-	// 公司黑名单过滤
-	if b.isInBlacklist(job.CompanyName, b.blackCompanies) {
-		log.Printf("被过滤：公司黑名单命中 | 公司：%s | 岗位：%s", job.CompanyName, job.JobName)
-		return true
+// filterByLocation 按通勤距离与商圈过滤，返回过滤原因（为空表示不过滤）
+func (b *Boss) filterByLocation(job *model.Job) string {
+	switch reason := b.commute.Check(job); reason {
+	case model.FILTER_REASON_COMMUTE:
+		log.Printf("被过滤：通勤距离过远 | 公司：%s | 岗位：%s | 距%s：%.1fkm | 地址：%s",
+			job.CompanyName, job.JobName, job.CommutePoint, *job.DistanceKm, job.Address)
+		return reason
+	case model.FILTER_REASON_DISTRICT:
+		log.Printf("被过滤：不在目标商圈 | 公司：%s | 岗位：%s | 商圈：%s | 地址：%s",
+			job.CompanyName, job.JobName, job.District, job.Address)
+		return reason
 	}
+	return ""
+}
 
-	// 招聘者黑名单过滤
-	hrPosition := b.getStringValue(bossInfo, "title")
-	if b.isInBlacklist(hrPosition, b.blackRecruiters) {
-		log.Printf("被过滤：招聘者黑名单命中 | 公司：%s | 岗位：%s | 招聘者：%s",
-			job.CompanyName, job.JobName, hrPosition)
-		return true
+// softFilter 白名单岗位忽略软过滤原因，其余岗位原样返回
+func (b *Boss) softFilter(job *model.Job, reason string) string {
+	if reason != "" && job.WhitelistHit != "" {
+		log.Printf("白名单岗位忽略过滤（%s） | 公司：%s | 岗位：%s", reason, job.CompanyName, job.JobName)
+		return ""
 	}
+	return reason
+}
 
-	return false
+// applyFilterRules 执行过滤规则，命中 skip 规则时返回规则名称作为过滤原因
+func (b *Boss) applyFilterRules(job *model.Job, afterAi bool) string {
+	outcome := b.filterRuleService.ApplyRules(b.filterRules, job, afterAi)
+	if outcome.SkipRule != "" {
+		log.Printf("被过滤：命中过滤规则 %s | 公司：%s | 岗位：%s", outcome.SkipRule, job.CompanyName, job.JobName)
+		return outcome.SkipRule
+	}
+	if len(outcome.Matched) > 0 {
+		log.Printf("命中过滤规则 %s | 公司：%s | 岗位：%s | 优先级：%d | 标签：%s",
+			strings.Join(outcome.Matched, ","), job.CompanyName, job.JobName, job.Priority, job.Tags)
+	}
+	return ""
 }
 
-// isInBlacklist 检查是否在黑名单中
-func (b *Boss) isInBlacklist(value string, blacklist map[string]bool) bool {
-	for blackItem := range blacklist {
-		if strings.Contains(value, blackItem) {
-			return true
-		}
+// extractJobAttributes 使用AI提取职位描述中的结构化属性并记录到岗位上，命中属性过滤条件时返回过滤原因
+func (b *Boss) extractJobAttributes(job *model.Job) string {
+	if !b.config.EnableAIExtract || job.JobInfo == "" {
+		return ""
+	}
+
+	attributes, err := b.aiService.ExtractJdAttributes(job.JobInfo)
+	if err != nil {
+		b.reportAiError(err)
+		log.Printf("AI提取职位属性失败 | 公司：%s | 岗位：%s | 错误：%v", job.CompanyName, job.JobName, err)
+		return ""
+	}
+
+	if raw, err := json.Marshal(attributes); err == nil {
+		job.JdAttributes = string(raw)
+	}
+	job.TechStack = strings.Join(attributes.TechStack, ",")
+	job.RequiredYears = attributes.RequiredYears
+	job.WorkMode = attributes.WorkMode
+	job.OvertimeSignals = strings.Join(attributes.OvertimeSignals, ",")
+	job.Outsourcing = 0
+	if attributes.Outsourcing {
+		job.Outsourcing = 1
+	}
+	job.Education = attributes.Education
+	job.TeamSize = attributes.TeamSize
+
+	if b.config.FilterOvertime && len(attributes.OvertimeSignals) > 0 {
+		log.Printf("被过滤：存在加班信号 | 公司：%s | 岗位：%s | 信号：%s", job.CompanyName, job.JobName, job.OvertimeSignals)
+		return model.FILTER_REASON_OVERTIME
+	}
+	if b.config.FilterOutsourcing && attributes.Outsourcing {
+		log.Printf("被过滤：外包/驻场岗位 | 公司：%s | 岗位：%s | 依据：%s",
+			job.CompanyName, job.JobName, strings.Join(attributes.OutsourcingHints, ","))
+		return model.FILTER_REASON_OUTSOURCING
+	}
+	if b.config.MaxRequiredYears > 0 && attributes.RequiredYears != nil && *attributes.RequiredYears > b.config.MaxRequiredYears {
+		log.Printf("被过滤：年限要求%d年高于%d年 | 公司：%s | 岗位：%s",
+			*attributes.RequiredYears, b.config.MaxRequiredYears, job.CompanyName, job.JobName)
+		return model.FILTER_REASON_REQUIRED_YEARS
+	}
+	return ""
+}
+
+// scoreJob 使用AI评估岗位匹配度并记录到岗位上，低于阈值时返回过滤原因
+func (b *Boss) scoreJob(job *model.Job) string {
+	if !b.config.EnableAIScore || job.JobInfo == "" {
+		return ""
+	}
+
+	introduce := ""
+	if aiConfig, err := b.aiService.GetAiConfig(); err == nil && aiConfig != nil {
+		introduce = aiConfig.Introduce
+	}
+
+	result, err := b.aiService.ScoreJobFit(job.JobInfo, introduce)
+	if err != nil {
+		// 评分失败不影响投递
+		b.reportAiError(err)
+		log.Printf("AI匹配度评分失败 | 公司：%s | 岗位：%s | 错误：%v", job.CompanyName, job.JobName, err)
+		return ""
+	}
+
+	score := result.Score
+	job.AiScore = &score
+	job.AiMatchedSkills = strings.Join(result.MatchedSkills, ",")
+	job.AiMissingSkills = strings.Join(result.MissingSkills, ",")
+	job.AiScoreReason = result.Reason
+
+	if score < b.config.AIScoreThreshold {
+		log.Printf("被过滤：AI匹配度%d低于阈值%d | 公司：%s | 岗位：%s | 理由：%s",
+			score, b.config.AIScoreThreshold, job.CompanyName, job.JobName, result.Reason)
+		return model.FILTER_REASON_AI_LOW_SCORE
+	}
+	return ""
+}
+
+// saveJob 保存岗位到统一职位表，status 为空时保留已有状态
+func (b *Boss) saveJob(job *model.Job, status, filterReason string) {
+	job.DeliveryStatus = status
+	job.FilterReason = filterReason
+
+	if err := b.jobService.SaveOrUpdateJob(job); err != nil {
+		log.Printf("保存岗位失败 | 公司：%s | 岗位：%s | 错误：%v", job.CompanyName, job.JobName, err)
 	}
-	return false
 }
 
 // resumeSubmission 投递简历
-func (b *Boss) resumeSubmission(keyword string, job *utils.Job) bool {
+func (b *Boss) resumeSubmission(keyword string, job *model.Job) bool {
 	if b.shouldStopCallback != nil && b.shouldStopCallback() {
 		log.Printf("停止指令已触发，跳过投递 | 公司：%s | 岗位：%s", job.CompanyName, job.JobName)
 		return false
@@ -391,21 +983,13 @@ func (b *Boss) resumeSubmission(keyword string, job *utils.Job) bool {
 		return false
 	}
 
-	// 查找"查看更多信息"按钮
-	moreInfoBtn, err := b.page.QuerySelector("a.more-job-btn")
-	if err != nil || moreInfoBtn == nil {
-		log.Printf("未找到'查看更多信息'按钮，跳过...")
-		return false
-	}
-
-	href, err := moreInfoBtn.GetAttribute("href")
-	if err != nil || !strings.HasPrefix(href, "/job_detail/") {
-		log.Printf("未获取到岗位详情链接，跳过...")
+	// 岗位已在筛选阶段解析出详情链接，投递时直接打开
+	detailUrl := job.Href
+	if detailUrl == "" {
+		log.Printf("未获取到岗位详情链接，跳过 | 公司：%s | 岗位：%s", job.CompanyName, job.JobName)
 		return false
 	}
 
-	detailUrl := "https://www.zhipin.com" + href
-
 	// 在新页面打开详情
 	context := b.page.Context()
 	newPage, err := context.NewPage()
@@ -423,8 +1007,13 @@ func (b *Boss) resumeSubmission(keyword string, job *utils.Job) bool {
 		return false
 	}
 
-	// 查找立即沟通按钮
-	chatBtn, found := b.waitForChatButton(newPage)
+	// 查找立即沟通按钮，按钮为“继续沟通”时说明已沟通过，记录后跳过
+	chatBtn, contacted, found := b.waitForChatButton(newPage)
+	if contacted {
+		log.Printf("已沟通过，跳过 | 公司：%s | 岗位：%s", job.CompanyName, job.JobName)
+		b.updateDeliveryStatus(detailUrl, job, model.DELIVERY_STATUS_CONTACTED)
+		return false
+	}
 	if !found {
 		log.Printf("未找到立即沟通按钮，跳过岗位: %s", job.JobName)
 		return false
@@ -437,6 +1026,7 @@ func (b *Boss) resumeSubmission(keyword string, job *utils.Job) bool {
 	inputLocator, inputReady := b.waitForChatInput(newPage)
 	if !inputReady {
 		log.Printf("聊天输入框未出现，跳过: %s", job.JobName)
+		b.updateDeliveryStatus(detailUrl, job, model.DELIVERY_STATUS_FAILED)
 		return false
 	}
 
@@ -454,7 +1044,7 @@ func (b *Boss) resumeSubmission(keyword string, job *utils.Job) bool {
 		job.CompanyName, job.JobName, job.Salary, message, imgResume)
 
 	// 更新投递状态
-	b.updateDeliveryStatus(detailUrl, job)
+	b.updateDeliveryStatus(detailUrl, job, model.DELIVERY_STATUS_DELIVERED)
 
 	b.mu.Lock()
 	b.resultList = append(b.resultList, job)
@@ -463,23 +1053,26 @@ func (b *Boss) resumeSubmission(keyword string, job *utils.Job) bool {
 	return true
 }
 
-// 等待聊天按钮
-func (b *Boss) waitForChatButton(page playwright.Page) (playwright.ElementHandle, bool) {
+// 等待聊天按钮，返回按钮、是否已沟通过（按钮为“继续沟通”）、是否找到立即沟通按钮
+func (b *Boss) waitForChatButton(page playwright.Page) (playwright.ElementHandle, bool, bool) {
 	for i := 0; i < 5; i++ {
 		if b.shouldStopCallback != nil && b.shouldStopCallback() {
-			return nil, false
+			return nil, false, false
 		}
 
 		chatBtn, err := page.QuerySelector("a.btn-startchat, a.op-btn-chat")
 		if err == nil && chatBtn != nil {
 			text, _ := chatBtn.TextContent()
 			if strings.Contains(text, "立即沟通") {
-				return chatBtn, true
+				return chatBtn, false, true
+			}
+			if strings.Contains(text, "继续沟通") {
+				return chatBtn, true, false
 			}
 		}
 		utils.Sleep(1)
 	}
-	return nil, false
+	return nil, false, false
 }
 
 // 等待聊天输入框
@@ -501,27 +1094,84 @@ func (b *Boss) waitForChatInput(page playwright.Page) (playwright.ElementHandle,
 	return nil, false
 }
 
-// generateMessage 生成消息内容
-func (b *Boss) generateMessage(keyword string, job *utils.Job) string {
-	if b.config.EnableAI && job.JobInfo != "" {
-		aiMessage, err := b.aiService.SendRequest(b.buildAIPrompt(keyword, job))
-		if err == nil && aiMessage != "" && !strings.Contains(strings.ToLower(aiMessage), "false") {
-			return aiMessage
+// generateMessage 生成消息内容：AI生成的打招呼语需通过校验，未通过时重新生成一次，仍未通过则回退到默认打招呼语
+func (b *Boss) generateMessage(keyword string, job *model.Job) string {
+	job.Greeting = b.config.SayHi
+	job.GreetingSource = model.GREETING_SOURCE_TEMPLATE
+	job.GreetingReject = ""
+
+	// 白名单条目配置了自定义打招呼语时直接使用
+	if entry := b.whitelist.Match(job); entry != nil && strings.TrimSpace(entry.Greeting) != "" {
+		job.Greeting = strings.TrimSpace(entry.Greeting)
+		job.GreetingSource = model.GREETING_SOURCE_WHITELIST
+		return job.Greeting
+	}
+
+	if !b.config.EnableAI || job.JobInfo == "" {
+		return job.Greeting
+	}
+
+	data := service.NewPromptTemplateData(job, keyword, b.config.SayHi)
+	prompt, err := b.aiService.BuildGreetingPrompt(data)
+	if err != nil {
+		log.Printf("构建AI提示词失败: %v", err)
+		return job.Greeting
+	}
+
+	validator := service.NewGreetingValidator(data.MaxLength, b.config.GreetingBanWords)
+	job.GreetingSource = model.GREETING_SOURCE_FALLBACK
+	for attempt := 0; attempt < 2; attempt++ {
+		// 重新生成时跳过缓存，避免再次拿到未通过校验的结果
+		aiMessage, err := b.aiService.SendRequestWithOptions(prompt, service.AiRequestOptions{
+			Purpose:     service.AI_PURPOSE_GREETING,
+			BypassCache: attempt > 0,
+		})
+		if err != nil {
+			b.reportAiError(err)
+			job.GreetingReject = service.GREETING_REJECT_AI_ERROR
+			return job.Greeting
+		}
+
+		greeting, rejectReason := validator.Validate(aiMessage, prompt, data.Introduce, data.SayHi)
+		if rejectReason == "" {
+			job.Greeting = greeting
+			job.GreetingSource = model.GREETING_SOURCE_AI
+			if attempt > 0 {
+				job.GreetingSource = model.GREETING_SOURCE_AI_RETRY
+			}
+			return job.Greeting
 		}
+
+		job.GreetingReject = rejectReason
+		log.Printf("AI打招呼语未通过校验(%s) | 公司：%s | 岗位：%s | 内容：%s",
+			rejectReason, job.CompanyName, job.JobName, aiMessage)
 	}
-	return b.config.SayHi
+	return job.Greeting
 }
 
-// buildAIPrompt 构建AI提示词
-func (b *Boss) buildAIPrompt(keyword string, job *utils.Job) string {
-	aiConfig, err := b.aiService.GetAiConfig()
-	introduce := ""
-	if err == nil && aiConfig != nil {
-		introduce = aiConfig.Introduce
+// reportAiError AI熔断或预算用尽时通过进度回调提示一次
+func (b *Boss) reportAiError(err error) {
+	var message string
+	switch {
+	case errors.Is(err, service.ErrAiCircuitOpen):
+		message = "AI服务连续调用失败，本次运行不再调用AI，改用默认打招呼语"
+	case errors.Is(err, service.ErrAiBudgetExhausted):
+		message = "AI预算已用尽，本次运行已停用AI，改用默认打招呼语"
+	default:
+		return
 	}
 
-	return fmt.Sprintf("请基于以下信息生成简洁友好的中文打招呼语，不超过60字：\n个人介绍：%s\n关键词：%s\n职位名称：%s\n职位描述：%s\n参考语：%s",
-		introduce, keyword, job.JobName, job.JobInfo, b.config.SayHi)
+	// 并发筛选时多个岗位可能同时失败，只提示一次
+	b.mu.Lock()
+	reported := b.aiDisabledReported
+	b.aiDisabledReported = true
+	b.mu.Unlock()
+	if reported {
+		return
+	}
+	if b.progressCallback != nil {
+		b.progressCallback(message, 0, 0)
+	}
 }
 
 // sendChatMessage 发送聊天消息
@@ -691,13 +1341,17 @@ func contains(s, substr string) bool {
 }
 
 // updateDeliveryStatus 更新投递状态
-func (b *Boss) updateDeliveryStatus(detailUrl string, job *utils.Job) {
-	encryptId := b.extractEncryptId(detailUrl)
-	if encryptId != "" {
-		// 这里需要根据实际的数据库操作来实现状态更新
-		log.Printf("更新投递状态 | 公司：%s | 岗位：%s | encryptId：%s",
-			job.CompanyName, job.JobName, encryptId)
+func (b *Boss) updateDeliveryStatus(detailUrl string, job *model.Job, status string) {
+	if job.PlatformJobId == "" {
+		job.PlatformJobId = b.extractEncryptId(detailUrl)
+	}
+	if job.PlatformJobId == "" {
+		return
 	}
+
+	log.Printf("更新投递状态 | 公司：%s | 岗位：%s | encryptId：%s | 状态：%s",
+		job.CompanyName, job.JobName, job.PlatformJobId, status)
+	b.saveJob(job, status, "")
 }
 
 // extractEncryptId 从URL中提取encryptId
@@ -716,8 +1370,32 @@ func (b *Boss) extractEncryptId(detailUrl string) string {
 	return detailUrl[start : start+end]
 }
 
+// collectJobList 记录订阅到的搜索列表接口响应中的岗位（不等待新的响应）
+func (b *Boss) collectJobList(jobList *Subscription) {
+	for {
+		select {
+		case response, ok := <-jobList.C:
+			if !ok {
+				return
+			}
+			data, err := DecodeWapi[JobListData](response.Body)
+			if err != nil {
+				log.Printf("解析岗位列表接口响应失败: %v", err)
+				continue
+			}
+			for _, item := range data.JobList {
+				if item.EncryptJobId != "" {
+					b.listedJobs[item.EncryptJobId] = item
+				}
+			}
+		default:
+			return
+		}
+	}
+}
+
 // scrollToLoadAllJobs 滚动加载所有岗位
-func (b *Boss) scrollToLoadAllJobs(keyword string) {
+func (b *Boss) scrollToLoadAllJobs(keyword string, jobList *Subscription) {
 	lastCount := -1
 	stableTries := 0
 
@@ -737,6 +1415,7 @@ func (b *Boss) scrollToLoadAllJobs(keyword string) {
 
 		// 滚动页面
 		b.page.Evaluate("() => window.scrollBy(0, Math.floor(window.innerHeight * 1.5))")
+		b.collectJobList(jobList)
 
 		// 检查卡片数量变化
 		cards, err := b.page.QuerySelectorAll("//ul[contains(@class, 'rec-job-list')]//li[contains(@class, 'job-card-box')]")
@@ -758,6 +1437,14 @@ func (b *Boss) scrollToLoadAllJobs(keyword string) {
 	}
 }
 
+// resolveCityCode 使用 customCityCode 将自定义城市名称映射为城市代码，未配置时原样返回
+func (b *Boss) resolveCityCode(cityCode string) string {
+	if code, ok := b.config.CustomCityCode[cityCode]; ok && code != "" {
+		return code
+	}
+	return cityCode
+}
+
 // getSearchUrl 构建搜索URL
 func (b *Boss) getSearchUrl(cityCode string) string {
 	baseUrl := "https://www.zhipin.com/web/geek/job?"
@@ -791,21 +1478,6 @@ func (b *Boss) getSearchUrl(cityCode string) string {
 	return baseUrl + strings.Join(params, "&")
 }
 
-// getStringValue 安全获取字符串值
-func (b *Boss) getStringValue(data map[string]interface{}, key string) string {
-	if data == nil {
-		return ""
-	}
-	value, exists := data[key]
-	if !exists {
-		return ""
-	}
-	if str, ok := value.(string); ok {
-		return str
-	}
-	return ""
-}
-
 // 薪资解析相关方法
 func (b *Boss) isSalaryNotExpected(salary string) bool {
 	expectedSalary := b.config.ExpectedSalary
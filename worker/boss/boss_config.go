@@ -20,16 +20,3 @@ type BossConfig struct {
     WaitTime      string   `yaml:"wait_time" json:"wait_time"`
     DeadStatus    []string `yaml:"dead_status" json:"dead_status"`
 }
-
-type JobDetail struct {
-    EncryptID      string `json:"encrypt_id"`
-    EncryptUserID  string `json:"encrypt_user_id"`
-    JobName        string `json:"job_name"`
-    Salary         string `json:"salary"`
-    CompanyName    string `json:"company_name"`
-    Recruiter      string `json:"recruiter"`
-    JobInfo        string `json:"job_info"`
-    JobArea        string `json:"job_area"`
-    HRActiveStatus string `json:"hr_active_status"`
-    HRPosition     string `json:"hr_position"`
-}
\ No newline at end of file
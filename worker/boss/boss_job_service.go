@@ -8,39 +8,31 @@ import (
 	"time"
 
 	"get_jobs_go/config"
+	"get_jobs_go/model"
 	"get_jobs_go/service"
+	"get_jobs_go/worker/platform"
 	"get_jobs_go/worker/playwright_manager"
 )
 
 // JobProgressMessage 任务进度消息
-type JobProgressMessage struct {
-	Platform  string `json:"platform"`
-	Type      string `json:"type"` // info, warning, error, progress, success
-	Message   string `json:"message"`
-	Current   *int   `json:"current,omitempty"`
-	Total     *int   `json:"total,omitempty"`
-	Timestamp int64  `json:"timestamp"`
-}
+type JobProgressMessage = platform.JobProgressMessage
 
 // JobPlatformService 任务平台服务接口
-type JobPlatformService interface {
-	ExecuteDelivery(progressCallback func(message JobProgressMessage)) error
-	StopDelivery() error
-	GetStatus() map[string]interface{}
-	GetPlatformName() string
-	IsRunning() bool
-}
+type JobPlatformService = platform.JobPlatformService
 
 // BossJobService Boss直聘任务服务
 type BossJobService struct {
 	playwrightManager *playwright_manager.PlaywrightManager
 	configService     *service.ConfigService
 	bossProvider      func() *Boss
+	capture           *NetworkCapture // 挂在Boss页面上的网络捕获器，跨多次投递复用
 
 	running     bool
 	shouldStop  bool
 	statusMutex sync.RWMutex
 	platform    string
+	accountId   int64  // 所属账号（0=默认账号）
+	accountName string // 账号名称
 }
 
 // NewBossJobService 创建Boss任务服务
@@ -53,10 +45,18 @@ func NewBossJobService(
 		playwrightManager: playwrightManager,
 		configService:     configService,
 		bossProvider:      bossProvider,
+		capture:           NewNetworkCapture(),
 		platform:          "boss",
+		accountName:       model.DEFAULT_ACCOUNT_NAME,
 	}
 }
 
+// SetAccount 设置任务所属的账号（playwrightManager、configService 与 bossProvider 须使用该账号的上下文与服务）
+func (s *BossJobService) SetAccount(accountId int64, accountName string) {
+	s.accountId = accountId
+	s.accountName = accountName
+}
+
 // =============================
 // ExecuteDelivery：核心任务执行逻辑（带登录等待 Loop）
 // =============================
@@ -86,7 +86,7 @@ func (s *BossJobService) ExecuteDelivery(progressCallback func(message JobProgre
 	// =============================
 	// ① 获取Boss页面
 	// =============================
-	page := s.playwrightManager.GetBossPage()
+	page := s.playwrightManager.GetPage(s.platform)
 	if page == nil {
 		progressCallback(JobProgressMessage{
 			Platform:  s.platform,
@@ -160,13 +160,13 @@ LOGIN_DONE:
 	// =============================
 	// ③ 暂停后台监控（避免冲突）
 	// =============================
-	s.playwrightManager.PauseBossMonitoring()
-	defer s.playwrightManager.ResumeBossMonitoring()
+	s.playwrightManager.PauseMonitoring(s.platform)
+	defer s.playwrightManager.ResumeMonitoring(s.platform)
 
 	// =============================
 	// ④ 加载配置
 	// =============================
-	_, err := s.configService.GetBossConfig()
+	bossConfig, err := s.loadBossConfig()
 	if err != nil {
 		progressCallback(JobProgressMessage{
 			Platform:  s.platform,
@@ -194,9 +194,14 @@ LOGIN_DONE:
 	// =============================
 	// ⑤ 创建 Boss 实例
 	// =============================
+	s.capture.Attach(page)
+	s.capture.SetRecordDir(bossConfig.CaptureDir)
+
 	bossInstance := s.bossProvider()
 	bossInstance.SetPage(page)
-	bossInstance.SetConfig(&config.GlobalConfig.Boss)
+	bossInstance.SetCapture(s.capture)
+	bossInstance.SetConfig(bossConfig)
+	bossInstance.SetRunId(s.newRunId())
 
 	// 设置进度回调
 	bossInstance.SetProgressCallback(func(message string, current, total int) {
@@ -276,6 +281,7 @@ func (s *BossJobService) GetStatus() map[string]interface{} {
 
 	return map[string]interface{}{
 		"platform":   s.platform,
+		"accountId":  s.accountId,
 		"isRunning":  s.running,
 		"isLoggedIn": s.playwrightManager.IsLoggedIn(s.platform),
 	}
@@ -299,3 +305,38 @@ func (s *BossJobService) ShouldStop() bool {
 	defer s.statusMutex.RUnlock()
 	return s.shouldStop
 }
+
+// loadBossConfig 加载本次投递的Boss配置：默认账号使用配置文件中的 boss 配置；其他账号使用数据库中该账号的配置
+// （配置文件独有的自定义城市编码、过滤规则与通勤点沿用全局配置），该账号未保存配置时同样使用配置文件
+func (s *BossJobService) loadBossConfig() (*config.BossConfig, error) {
+	accountConfig, err := s.configService.GetBossConfig()
+	if err != nil {
+		return nil, err
+	}
+	if s.accountId == model.DEFAULT_ACCOUNT_ID {
+		return &config.GlobalConfig.Boss, nil
+	}
+
+	hasConfig, err := s.configService.HasBossConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !hasConfig {
+		log.Printf("账号 %s 未保存Boss配置，使用配置文件中的配置", s.accountName)
+		return &config.GlobalConfig.Boss, nil
+	}
+
+	accountConfig.CustomCityCode = config.GlobalConfig.Boss.CustomCityCode
+	accountConfig.FilterRules = config.GlobalConfig.Boss.FilterRules
+	accountConfig.CommutePoints = config.GlobalConfig.Boss.CommutePoints
+	return accountConfig, nil
+}
+
+// newRunId 生成运行ID（用于AI用量统计），其他账号的运行ID包含账号名称
+func (s *BossJobService) newRunId() string {
+	timestamp := time.Now().Format("20060102150405")
+	if s.accountId == model.DEFAULT_ACCOUNT_ID {
+		return fmt.Sprintf("%s-%s", s.platform, timestamp)
+	}
+	return fmt.Sprintf("%s-%s-%s", s.platform, s.accountName, timestamp)
+}
@@ -0,0 +1,231 @@
+package boss
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// SUBSCRIPTION_BUFFER 每个订阅缓存的响应数，订阅方处理不及时超出缓存的响应被丢弃
+const SUBSCRIPTION_BUFFER = 32
+
+// CapturedResponse 捕获到的 wapi 接口响应
+type CapturedResponse struct {
+	Endpoint   string     // 接口路径（ENDPOINT_*）
+	URL        string     // 完整请求地址
+	Params     url.Values // 请求参数（用于关联请求，如 securityId、lid）
+	Status     int
+	Body       []byte
+	CapturedAt time.Time
+}
+
+// ResponseMatcher 判断捕获的响应是否属于订阅方发起的请求
+type ResponseMatcher func(response *CapturedResponse) bool
+
+// MatchParam 按请求参数关联响应，value 为空时不匹配任何响应（避免把其他请求的响应当作自己的）
+func MatchParam(key, value string) ResponseMatcher {
+	return func(response *CapturedResponse) bool {
+		return value != "" && response.Params.Get(key) == value
+	}
+}
+
+// Subscription 接口响应订阅，通过 C 接收匹配的响应，不再需要时调用 Cancel
+type Subscription struct {
+	C        <-chan *CapturedResponse
+	ch       chan *CapturedResponse
+	endpoint string
+	match    ResponseMatcher
+	capture  *NetworkCapture
+	once     sync.Once
+}
+
+// Cancel 取消订阅并关闭 C（可重复调用）
+func (s *Subscription) Cancel() {
+	s.once.Do(func() {
+		s.capture.mu.Lock()
+		delete(s.capture.subscriptions, s)
+		s.capture.mu.Unlock()
+		close(s.ch)
+	})
+}
+
+// Await 等待订阅的第一个响应，超时返回错误并取消订阅
+func (s *Subscription) Await(timeout time.Duration) (*CapturedResponse, error) {
+	defer s.Cancel()
+
+	select {
+	case response, ok := <-s.C:
+		if !ok {
+			return nil, fmt.Errorf("订阅已取消")
+		}
+		return response, nil
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("等待接口 %s 响应超时（%v）", s.endpoint, timeout)
+	}
+}
+
+// NetworkCapture 长期挂在页面上的 wapi 响应捕获器：页面只注册一个响应监听，
+// 按接口路径与请求参数把响应分发给订阅方，不影响页面上的其他监听；设置记录目录后原始响应另存为测试样本
+type NetworkCapture struct {
+	page          playwright.Page
+	handler       func(playwright.Response)
+	subscriptions map[*Subscription]bool
+	recordDir     string
+	recorded      int64
+	mu            sync.Mutex
+}
+
+// NewNetworkCapture 创建网络捕获器，需调用 Attach 挂到页面上
+func NewNetworkCapture() *NetworkCapture {
+	return &NetworkCapture{
+		subscriptions: make(map[*Subscription]bool),
+	}
+}
+
+// Attach 挂到页面上（同一页面重复调用无效果，换页面时先从原页面移除监听）
+func (c *NetworkCapture) Attach(page playwright.Page) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.page == page {
+		return
+	}
+	if c.page != nil && c.handler != nil {
+		c.page.RemoveListener("response", c.handler)
+	}
+
+	c.page = page
+	c.handler = c.onResponse
+	page.OnResponse(c.handler)
+}
+
+// SetRecordDir 设置原始响应的记录目录，为空时不记录
+func (c *NetworkCapture) SetRecordDir(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordDir = dir
+}
+
+// Subscribe 订阅接口响应，match 为空时接收该接口的全部响应
+func (c *NetworkCapture) Subscribe(endpoint string, match ResponseMatcher) *Subscription {
+	ch := make(chan *CapturedResponse, SUBSCRIPTION_BUFFER)
+	subscription := &Subscription{
+		C:        ch,
+		ch:       ch,
+		endpoint: endpoint,
+		match:    match,
+		capture:  c,
+	}
+
+	c.mu.Lock()
+	c.subscriptions[subscription] = true
+	c.mu.Unlock()
+	return subscription
+}
+
+// Record 记录未经页面监听获取的原始响应（如页面池直接请求的接口）
+func (c *NetworkCapture) Record(endpoint string, body []byte) {
+	c.mu.Lock()
+	dir := c.recordDir
+	c.mu.Unlock()
+	if dir != "" {
+		c.record(dir, endpoint, body)
+	}
+}
+
+// onResponse 页面响应监听：监听在事件分发中同步执行且持有事件锁，读取响应内容须放到协程中
+func (c *NetworkCapture) onResponse(response playwright.Response) {
+	endpoint := matchEndpoint(response.URL())
+	if endpoint == "" || !strings.EqualFold(response.Request().Method(), "GET") {
+		return
+	}
+
+	c.mu.Lock()
+	wanted := c.recordDir != ""
+	for subscription := range c.subscriptions {
+		if subscription.endpoint == endpoint {
+			wanted = true
+			break
+		}
+	}
+	c.mu.Unlock()
+	if !wanted {
+		return
+	}
+
+	go c.dispatch(endpoint, response)
+}
+
+// dispatch 读取响应内容，按需记录并分发给匹配的订阅方
+func (c *NetworkCapture) dispatch(endpoint string, response playwright.Response) {
+	body, err := response.Body()
+	if err != nil {
+		log.Printf("读取接口响应失败 | 接口：%s | 错误：%v", endpoint, err)
+		return
+	}
+
+	captured := &CapturedResponse{
+		Endpoint:   endpoint,
+		URL:        response.URL(),
+		Status:     response.Status(),
+		Body:       body,
+		CapturedAt: time.Now(),
+	}
+	if parsed, err := url.Parse(captured.URL); err == nil {
+		captured.Params = parsed.Query()
+	}
+
+	c.Record(endpoint, body)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for subscription := range c.subscriptions {
+		if subscription.endpoint != endpoint || (subscription.match != nil && !subscription.match(captured)) {
+			continue
+		}
+		select {
+		case subscription.ch <- captured:
+		default:
+			log.Printf("订阅方处理不及时，丢弃接口响应 | 接口：%s", endpoint)
+		}
+	}
+}
+
+// record 将原始响应保存为 <目录>/<接口名>/<时间>-<序号>.json
+func (c *NetworkCapture) record(dir, endpoint string, body []byte) {
+	name := strings.TrimSuffix(path.Base(endpoint), ".json")
+	target := filepath.Join(dir, name)
+	if err := os.MkdirAll(target, 0755); err != nil {
+		log.Printf("创建接口响应记录目录失败: %v", err)
+		return
+	}
+
+	seq := atomic.AddInt64(&c.recorded, 1)
+	file := filepath.Join(target, fmt.Sprintf("%s-%d.json", time.Now().Format("20060102-150405.000"), seq))
+	if err := os.WriteFile(file, body, 0644); err != nil {
+		log.Printf("记录接口响应失败: %v", err)
+	}
+}
+
+// matchEndpoint 返回地址对应的 wapi 接口路径，不是关注的接口时返回空
+func matchEndpoint(rawUrl string) string {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	for _, endpoint := range WAPI_ENDPOINTS {
+		if parsed.Path == endpoint {
+			return endpoint
+		}
+	}
+	return ""
+}
@@ -0,0 +1,193 @@
+package boss
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// DEFAULT_DETAIL_TIMEOUT 单个岗位详情页面的默认超时时间
+const DEFAULT_DETAIL_TIMEOUT = 20 * time.Second
+
+// ErrPagePoolClosed 页面池已关闭（任务停止或筛选结束）
+var ErrPagePoolClosed = errors.New("页面池已关闭")
+
+// PagePool 有界页面池：最多同时打开 size 个页面，页面全部被占用时 Acquire 阻塞（背压），
+// 页面用完归还后复用，超时或出错的页面直接丢弃并在需要时重新创建
+type PagePool struct {
+	context  playwright.BrowserContext
+	timeout  time.Duration
+	slots    chan struct{}        // 占用名额（容量即并发上限）
+	idle     chan playwright.Page // 空闲页面
+	pages    map[playwright.Page]bool
+	mu       sync.Mutex
+	done     chan struct{}
+	closeOne sync.Once
+}
+
+// NewPagePool 创建页面池，页面按需创建，timeout ≤ 0 时使用默认超时时间
+func NewPagePool(context playwright.BrowserContext, size int, timeout time.Duration) *PagePool {
+	if size < 1 {
+		size = 1
+	}
+	if timeout <= 0 {
+		timeout = DEFAULT_DETAIL_TIMEOUT
+	}
+	return &PagePool{
+		context: context,
+		timeout: timeout,
+		slots:   make(chan struct{}, size),
+		idle:    make(chan playwright.Page, size),
+		pages:   make(map[playwright.Page]bool),
+		done:    make(chan struct{}),
+	}
+}
+
+// Size 页面池的并发上限
+func (p *PagePool) Size() int {
+	return cap(p.slots)
+}
+
+// Acquire 获取一个页面（优先复用空闲页面），全部页面都在使用中时阻塞，页面池关闭后返回 ErrPagePoolClosed
+func (p *PagePool) Acquire() (playwright.Page, error) {
+	select {
+	case <-p.done:
+		return nil, ErrPagePoolClosed
+	case p.slots <- struct{}{}:
+	}
+
+	select {
+	case page := <-p.idle:
+		return page, nil
+	default:
+	}
+
+	page, err := p.context.NewPage()
+	if err != nil {
+		<-p.slots
+		return nil, fmt.Errorf("创建页面失败: %w", err)
+	}
+	page.SetDefaultTimeout(float64(p.timeout.Milliseconds()))
+
+	p.mu.Lock()
+	closed := p.isClosed()
+	if !closed {
+		p.pages[page] = true
+	}
+	p.mu.Unlock()
+
+	if closed {
+		page.Close()
+		<-p.slots
+		return nil, ErrPagePoolClosed
+	}
+	return page, nil
+}
+
+// Release 归还页面供后续任务复用（页面池已关闭时页面已随之关闭）
+func (p *PagePool) Release(page playwright.Page) {
+	// 检查与归还在同一把锁内完成，避免 Close 之后把已关闭的页面放回空闲队列；
+	// 页面池关闭时 Close 已关闭全部页面，无需再次关闭
+	p.mu.Lock()
+	if !p.isClosed() {
+		// 空闲队列容量等于并发上限，持有名额的页面归还时不会阻塞
+		p.idle <- page
+	}
+	p.mu.Unlock()
+	<-p.slots
+}
+
+// Discard 丢弃超时或出错的页面并释放名额
+func (p *PagePool) Discard(page playwright.Page) {
+	p.mu.Lock()
+	_, tracked := p.pages[page]
+	delete(p.pages, page)
+	p.mu.Unlock()
+
+	// 页面池关闭时已统一关闭全部页面
+	if tracked {
+		if err := page.Close(); err != nil {
+			log.Printf("关闭页面失败: %v", err)
+		}
+	}
+	<-p.slots
+}
+
+// RunWithPage 从页面池获取页面执行 fn 并返回其结果：超过超时时间、页面池关闭或出错时丢弃页面，否则归还页面
+func RunWithPage[T any](p *PagePool, fn func(page playwright.Page) (T, error)) (T, error) {
+	var zero T
+	page, err := p.Acquire()
+	if err != nil {
+		return zero, err
+	}
+
+	type outcome struct {
+		value T
+		err   error
+	}
+	result := make(chan outcome, 1)
+	go func() {
+		value, err := fn(page)
+		result <- outcome{value, err}
+	}()
+
+	select {
+	case res := <-result:
+		if res.err != nil {
+			p.Discard(page)
+			return zero, res.err
+		}
+		p.Release(page)
+		return res.value, nil
+	case <-time.After(p.timeout):
+		// 关闭页面会让仍在进行的 Playwright 调用立即返回错误，fn 随之结束
+		p.Discard(page)
+		return zero, fmt.Errorf("页面操作超时（%v）", p.timeout)
+	case <-p.done:
+		p.Discard(page)
+		return zero, ErrPagePoolClosed
+	}
+}
+
+// Close 关闭页面池与全部页面，等待中的 Acquire 立即返回，进行中的页面操作随页面关闭而结束
+func (p *PagePool) Close() {
+	p.closeOne.Do(func() {
+		p.mu.Lock()
+		close(p.done)
+		pages := p.pages
+		p.pages = make(map[playwright.Page]bool)
+		// 清空空闲队列（其中的页面随 pages 一并关闭）
+	drain:
+		for {
+			select {
+			case <-p.idle:
+			default:
+				break drain
+			}
+		}
+		p.mu.Unlock()
+
+		for page := range pages {
+			page.Close()
+		}
+	})
+}
+
+// Done 页面池关闭时关闭的通道
+func (p *PagePool) Done() <-chan struct{} {
+	return p.done
+}
+
+// isClosed 页面池是否已关闭（调用方持有 mu）
+func (p *PagePool) isClosed() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+package boss
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Boss直聘 wapi 接口路径（用于订阅网络响应）
+const (
+	ENDPOINT_JOB_LIST   = "/wapi/zpgeek/search/joblist.json"
+	ENDPOINT_JOB_DETAIL = "/wapi/zpgeek/job/detail.json"
+)
+
+// WAPI_ENDPOINTS 网络捕获关注的全部接口
+var WAPI_ENDPOINTS = []string{ENDPOINT_JOB_LIST, ENDPOINT_JOB_DETAIL}
+
+// WapiResponse wapi 接口的通用响应结构，code 为 0 表示成功
+type WapiResponse[T any] struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	ZpData  T      `json:"zpData"`
+}
+
+// JobListData 搜索岗位列表（joblist.json）
+type JobListData struct {
+	HasMore    bool          `json:"hasMore"`
+	TotalCount int           `json:"totalCount"`
+	Lid        string        `json:"lid"`
+	JobList    []JobListItem `json:"jobList"`
+}
+
+// JobListItem 搜索岗位列表中的岗位
+type JobListItem struct {
+	EncryptJobId     string   `json:"encryptJobId"`
+	SecurityId       string   `json:"securityId"`
+	Lid              string   `json:"lid"`
+	JobName          string   `json:"jobName"`
+	SalaryDesc       string   `json:"salaryDesc"`
+	JobExperience    string   `json:"jobExperience"`
+	JobDegree        string   `json:"jobDegree"`
+	CityName         string   `json:"cityName"`
+	AreaDistrict     string   `json:"areaDistrict"`
+	BusinessDistrict string   `json:"businessDistrict"`
+	Skills           []string `json:"skills"`
+	BrandName        string   `json:"brandName"`
+	BrandScaleName   string   `json:"brandScaleName"`
+	BrandStageName   string   `json:"brandStageName"`
+	BrandIndustry    string   `json:"brandIndustry"`
+	EncryptBossId    string   `json:"encryptBossId"`
+	BossName         string   `json:"bossName"`
+	BossTitle        string   `json:"bossTitle"`
+}
+
+// JobDetailData 岗位详情（detail.json）
+type JobDetailData struct {
+	SecurityId   string       `json:"securityId"`
+	Lid          string       `json:"lid"`
+	JobInfo      JobInfo      `json:"jobInfo"`
+	BossInfo     BossInfo     `json:"bossInfo"`
+	BrandComInfo BrandComInfo `json:"brandComInfo"`
+}
+
+// JobInfo 岗位详情中的职位信息
+type JobInfo struct {
+	EncryptId        string   `json:"encryptId"`
+	EncryptUserId    string   `json:"encryptUserId"`
+	JobName          string   `json:"jobName"`
+	SalaryDesc       string   `json:"salaryDesc"`
+	LocationName     string   `json:"locationName"`
+	ExperienceName   string   `json:"experienceName"`
+	DegreeName       string   `json:"degreeName"`
+	PostDescription  string   `json:"postDescription"`
+	ShowSkills       []string `json:"showSkills"`
+	Address          string   `json:"address"`
+	AreaDistrict     string   `json:"areaDistrict"`
+	BusinessDistrict string   `json:"businessDistrict"`
+	Longitude        *float64 `json:"longitude"`
+	Latitude         *float64 `json:"latitude"`
+	ProxyJob         int      `json:"proxyJob"`
+}
+
+// BossInfo 岗位详情中的招聘者信息
+type BossInfo struct {
+	Name           string `json:"name"`
+	Title          string `json:"title"`
+	BrandName      string `json:"brandName"`
+	ActiveTimeDesc string `json:"activeTimeDesc"`
+}
+
+// BrandComInfo 岗位详情中的公司信息
+type BrandComInfo struct {
+	BrandName    string `json:"brandName"`
+	Introduce    string `json:"introduce"`
+	IndustryName string `json:"industryName"`
+	ScaleName    string `json:"scaleName"`
+	StageName    string `json:"stageName"`
+}
+
+// DecodeWapi 将接口响应解析为对应的数据结构，code 非 0（如触发验证、登录失效）时返回错误
+func DecodeWapi[T any](body []byte) (*T, error) {
+	var response WapiResponse[T]
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("解析接口响应失败: %w", err)
+	}
+	if response.Code != 0 {
+		return nil, fmt.Errorf("接口返回错误（code=%d）: %s", response.Code, response.Message)
+	}
+	return &response.ZpData, nil
+}
+
+// MatchJobDetail 按响应中的岗位 encryptId 关联岗位详情（请求地址中没有 securityId 可供关联时使用），encryptId 为空时不匹配任何响应
+func MatchJobDetail(encryptId string) ResponseMatcher {
+	return func(response *CapturedResponse) bool {
+		if encryptId == "" {
+			return false
+		}
+		detail, err := DecodeWapi[JobDetailData](response.Body)
+		return err == nil && detail.JobInfo.EncryptId == encryptId
+	}
+}
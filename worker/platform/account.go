@@ -0,0 +1,69 @@
+package platform
+
+// ACCOUNT_PLATFORM_PREFIX 账号投递任务在编排器中的名称前缀
+const ACCOUNT_PLATFORM_PREFIX = "account:"
+
+// AccountDelivery 单个账号的投递任务：在该账号的浏览器上下文中按 mode 执行其平台服务，进度消息标注账号名称。
+// 实现 JobPlatformService，可注册到上层编排器，由上层编排器决定账号之间逐个执行还是同时执行
+type AccountDelivery struct {
+	accountId    int64
+	accountName  string
+	mode         RunMode
+	orchestrator *Orchestrator
+}
+
+// NewAccountDelivery 创建账号投递任务
+func NewAccountDelivery(accountId int64, accountName string, mode RunMode, services ...JobPlatformService) *AccountDelivery {
+	return &AccountDelivery{
+		accountId:    accountId,
+		accountName:  accountName,
+		mode:         mode,
+		orchestrator: NewOrchestrator(services...),
+	}
+}
+
+// AccountPlatformName 账号投递任务在编排器中的名称（用于 ExecuteDelivery 指定账号）
+func AccountPlatformName(accountName string) string {
+	return ACCOUNT_PLATFORM_PREFIX + accountName
+}
+
+// ExecuteDelivery 执行该账号的投递任务
+func (a *AccountDelivery) ExecuteDelivery(progressCallback func(message JobProgressMessage)) error {
+	return a.orchestrator.ExecuteDelivery(a.mode, nil, func(message JobProgressMessage) {
+		message.Account = a.accountName
+		progressCallback(message)
+	})
+}
+
+// StopDelivery 停止该账号的投递任务
+func (a *AccountDelivery) StopDelivery() error {
+	return a.orchestrator.StopDelivery()
+}
+
+// GetStatus 获取该账号各平台的任务状态
+func (a *AccountDelivery) GetStatus() map[string]interface{} {
+	status := a.orchestrator.GetStatus()
+	status["accountId"] = a.accountId
+	status["account"] = a.accountName
+	return status
+}
+
+// GetPlatformName 获取编排器中的名称（account:<账号名称>）
+func (a *AccountDelivery) GetPlatformName() string {
+	return AccountPlatformName(a.accountName)
+}
+
+// IsRunning 检查是否正在运行
+func (a *AccountDelivery) IsRunning() bool {
+	return a.orchestrator.IsRunning()
+}
+
+// AccountId 账号ID
+func (a *AccountDelivery) AccountId() int64 {
+	return a.accountId
+}
+
+// AccountName 账号名称
+func (a *AccountDelivery) AccountName() string {
+	return a.accountName
+}
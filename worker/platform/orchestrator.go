@@ -0,0 +1,243 @@
+// worker/platform/orchestrator.go
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// RunMode 多平台投递的执行方式
+type RunMode string
+
+const (
+	RunModeSequential RunMode = "sequential" // 按注册顺序逐个平台执行
+	RunModeConcurrent RunMode = "concurrent" // 所有平台同时执行
+)
+
+// ORCHESTRATOR_PLATFORM 编排器自身发出的进度消息使用的平台名
+const ORCHESTRATOR_PLATFORM = "all"
+
+// Orchestrator 多平台投递编排器：统一调度多个 JobPlatformService，并合并为一条进度流
+type Orchestrator struct {
+	services []JobPlatformService
+
+	running     bool
+	shouldStop  bool
+	statusMutex sync.RWMutex
+}
+
+// NewOrchestrator 创建编排器
+func NewOrchestrator(services ...JobPlatformService) *Orchestrator {
+	o := &Orchestrator{}
+	for _, svc := range services {
+		o.Register(svc)
+	}
+	return o
+}
+
+// Register 注册平台任务服务（同名平台会覆盖之前的注册）
+func (o *Orchestrator) Register(svc JobPlatformService) {
+	if svc == nil {
+		return
+	}
+
+	o.statusMutex.Lock()
+	defer o.statusMutex.Unlock()
+
+	for i, existing := range o.services {
+		if existing.GetPlatformName() == svc.GetPlatformName() {
+			o.services[i] = svc
+			return
+		}
+	}
+	o.services = append(o.services, svc)
+}
+
+// GetPlatformNames 获取已注册的平台名称
+func (o *Orchestrator) GetPlatformNames() []string {
+	o.statusMutex.RLock()
+	defer o.statusMutex.RUnlock()
+
+	names := make([]string, 0, len(o.services))
+	for _, svc := range o.services {
+		names = append(names, svc.GetPlatformName())
+	}
+	return names
+}
+
+// selectServices 根据平台名称筛选服务（为空表示全部）
+func (o *Orchestrator) selectServices(platforms []string) []JobPlatformService {
+	o.statusMutex.RLock()
+	defer o.statusMutex.RUnlock()
+
+	if len(platforms) == 0 {
+		return append([]JobPlatformService{}, o.services...)
+	}
+
+	selected := make([]JobPlatformService, 0, len(platforms))
+	for _, name := range platforms {
+		for _, svc := range o.services {
+			if svc.GetPlatformName() == name {
+				selected = append(selected, svc)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// ExecuteDelivery 执行多平台投递，platforms 为空时执行所有已注册平台
+func (o *Orchestrator) ExecuteDelivery(mode RunMode, platforms []string, progressCallback func(message JobProgressMessage)) error {
+	// 合并进度流：各平台可能并发回调，统一加锁串行输出
+	var callbackMutex sync.Mutex
+	emit := func(message JobProgressMessage) {
+		callbackMutex.Lock()
+		defer callbackMutex.Unlock()
+		progressCallback(message)
+	}
+
+	o.statusMutex.Lock()
+	if o.running {
+		o.statusMutex.Unlock()
+		emit(o.newMessage("warning", "多平台任务已在运行中"))
+		return nil
+	}
+	o.running = true
+	o.shouldStop = false
+	o.statusMutex.Unlock()
+
+	defer func() {
+		o.statusMutex.Lock()
+		o.running = false
+		o.shouldStop = false
+		o.statusMutex.Unlock()
+	}()
+
+	services := o.selectServices(platforms)
+	if len(services) == 0 {
+		emit(o.newMessage("warning", "没有可执行的平台"))
+		return nil
+	}
+
+	if mode == "" {
+		mode = RunModeSequential
+	}
+
+	emit(o.newMessage("info", fmt.Sprintf("开始执行多平台投递（%s），平台数：%d", mode, len(services))))
+
+	var errs []error
+	var errsMutex sync.Mutex
+	run := func(index int, svc JobPlatformService) {
+		defer func() {
+			if r := recover(); r != nil {
+				errsMutex.Lock()
+				errs = append(errs, fmt.Errorf("%s 平台执行异常: %v", svc.GetPlatformName(), r))
+				errsMutex.Unlock()
+			}
+		}()
+
+		current, total := index+1, len(services)
+		emit(JobProgressMessage{
+			Platform:  ORCHESTRATOR_PLATFORM,
+			Type:      "progress",
+			Message:   "开始执行平台：" + svc.GetPlatformName(),
+			Current:   &current,
+			Total:     &total,
+			Timestamp: time.Now().UnixMilli(),
+		})
+
+		if err := svc.ExecuteDelivery(emit); err != nil {
+			errsMutex.Lock()
+			errs = append(errs, fmt.Errorf("%s: %w", svc.GetPlatformName(), err))
+			errsMutex.Unlock()
+		}
+	}
+
+	switch mode {
+	case RunModeConcurrent:
+		var wg sync.WaitGroup
+		for i, svc := range services {
+			wg.Add(1)
+			go func(i int, svc JobPlatformService) {
+				defer wg.Done()
+				run(i, svc)
+			}(i, svc)
+		}
+		wg.Wait()
+	default:
+		for i, svc := range services {
+			if o.ShouldStop() {
+				emit(o.newMessage("warning", "多平台任务已被停止，跳过剩余平台"))
+				break
+			}
+			run(i, svc)
+		}
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		emit(o.newMessage("error", "多平台投递完成，部分平台失败: "+err.Error()))
+		return err
+	}
+
+	emit(o.newMessage("success", "多平台投递完成"))
+	return nil
+}
+
+// StopDelivery 停止所有平台的投递任务
+func (o *Orchestrator) StopDelivery() error {
+	o.statusMutex.Lock()
+	if o.running {
+		o.shouldStop = true
+		log.Println("收到停止多平台投递任务的请求")
+	}
+	o.statusMutex.Unlock()
+
+	var errs []error
+	for _, svc := range o.selectServices(nil) {
+		if err := svc.StopDelivery(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetStatus 获取所有平台的任务状态
+func (o *Orchestrator) GetStatus() map[string]interface{} {
+	platformStatus := make(map[string]interface{})
+	for _, svc := range o.selectServices(nil) {
+		platformStatus[svc.GetPlatformName()] = svc.GetStatus()
+	}
+
+	return map[string]interface{}{
+		"isRunning": o.IsRunning(),
+		"platforms": platformStatus,
+	}
+}
+
+// IsRunning 检查是否正在运行
+func (o *Orchestrator) IsRunning() bool {
+	o.statusMutex.RLock()
+	defer o.statusMutex.RUnlock()
+	return o.running
+}
+
+// ShouldStop 检查是否应该停止
+func (o *Orchestrator) ShouldStop() bool {
+	o.statusMutex.RLock()
+	defer o.statusMutex.RUnlock()
+	return o.shouldStop
+}
+
+// newMessage 构建编排器自身的进度消息
+func (o *Orchestrator) newMessage(msgType, message string) JobProgressMessage {
+	return JobProgressMessage{
+		Platform:  ORCHESTRATOR_PLATFORM,
+		Type:      msgType,
+		Message:   message,
+		Timestamp: time.Now().UnixMilli(),
+	}
+}
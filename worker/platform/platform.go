@@ -0,0 +1,22 @@
+// worker/platform/platform.go
+package platform
+
+// JobProgressMessage 任务进度消息
+type JobProgressMessage struct {
+	Platform  string `json:"platform"`
+	Account   string `json:"account,omitempty"` // 账号名称（多账号投递时由 AccountDelivery 填写）
+	Type      string `json:"type"`              // info, warning, error, progress, success
+	Message   string `json:"message"`
+	Current   *int   `json:"current,omitempty"`
+	Total     *int   `json:"total,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// JobPlatformService 任务平台服务接口
+type JobPlatformService interface {
+	ExecuteDelivery(progressCallback func(message JobProgressMessage)) error
+	StopDelivery() error
+	GetStatus() map[string]interface{}
+	GetPlatformName() string
+	IsRunning() bool
+}
@@ -0,0 +1,84 @@
+package playwright_manager
+
+import (
+	"fmt"
+	"get_jobs_go/config"
+	"get_jobs_go/model"
+	"get_jobs_go/service"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ForAccount 获取账号的管理器（不存在时创建并初始化）：账号拥有独立的 BrowserContext、页面、Cookie 与登录状态，
+// 与默认账号共享 Playwright 引擎（非持久化模式下还共享浏览器进程）。cookieService 须基于该账号的数据库会话创建
+func (m *PlaywrightManager) ForAccount(account *model.AccountEntity, cookieService service.CookieService) (*PlaywrightManager, error) {
+	if account == nil || account.ID == model.DEFAULT_ACCOUNT_ID {
+		return m, nil
+	}
+	if m.shared {
+		return nil, fmt.Errorf("只能通过默认账号的管理器创建其他账号的管理器")
+	}
+	if m.playwright == nil {
+		return nil, fmt.Errorf("Playwright管理器未初始化")
+	}
+
+	m.accountsMu.Lock()
+	defer m.accountsMu.Unlock()
+
+	if existing, ok := m.accounts[account.ID]; ok {
+		return existing, nil
+	}
+
+	child := NewPlaywrightManager(cookieService, m.accountBrowserConfig(account))
+	child.accountId = account.ID
+	child.shared = true
+	child.playwright = m.playwright
+	if !child.isPersistent() {
+		child.browser = m.browser
+	}
+
+	log.Infof("初始化账号 %s 的浏览器上下文...", account.Name)
+	if err := child.Init(); err != nil {
+		child.Close()
+		return nil, fmt.Errorf("账号 %s 浏览器上下文初始化失败: %w", account.Name, err)
+	}
+
+	if m.accounts == nil {
+		m.accounts = make(map[int64]*PlaywrightManager)
+	}
+	m.accounts[account.ID] = child
+	return child, nil
+}
+
+// AccountId 管理器所属的账号ID（0=默认账号）
+func (m *PlaywrightManager) AccountId() int64 {
+	return m.accountId
+}
+
+// accountBrowserConfig 账号的浏览器配置：使用账号自己的配置目录与存储状态文件，不开启远程调试端口（避免与默认账号冲突）
+func (m *PlaywrightManager) accountBrowserConfig(account *model.AccountEntity) config.BrowserConfig {
+	cfg := m.browserConfig
+	cfg.Args = append([]string{}, m.browserConfig.Args...)
+	cfg.Profile = account.BrowserProfile()
+	cfg.DebugPort = 0
+	if cfg.StorageState != "" {
+		ext := filepath.Ext(cfg.StorageState)
+		cfg.StorageState = strings.TrimSuffix(cfg.StorageState, ext) + "-" + cfg.Profile + ext
+	}
+	return cfg
+}
+
+// closeAccounts 关闭所有其他账号的管理器
+func (m *PlaywrightManager) closeAccounts() {
+	m.accountsMu.Lock()
+	accounts := m.accounts
+	m.accounts = nil
+	m.accountsMu.Unlock()
+
+	for accountId, child := range accounts {
+		log.Infof("关闭账号 %d 的浏览器上下文...", accountId)
+		child.Close()
+	}
+}
@@ -0,0 +1,224 @@
+package playwright_manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"get_jobs_go/utils"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// DEFAULT_BROWSER_PROFILE 未配置 profile 时使用的账号配置目录名
+const DEFAULT_BROWSER_PROFILE = "default"
+
+// localStorageInitScript 在页面脚本执行前写入导入的 localStorage（只补充不存在的键，不覆盖站点已有数据）
+const localStorageInitScript = `(() => {
+	const items = %s[location.origin];
+	if (!items) return;
+	try {
+		for (const [name, value] of items) {
+			if (localStorage.getItem(name) === null) localStorage.setItem(name, value);
+		}
+	} catch (e) {}
+})();`
+
+// isPersistent 是否使用持久化浏览器配置目录
+func (m *PlaywrightManager) isPersistent() bool {
+	return m.browserConfig.UserDataDir != ""
+}
+
+// profileDir 当前账号的浏览器配置目录（userDataDir/profile，相对路径基于项目根目录）
+func (m *PlaywrightManager) profileDir() (string, error) {
+	profile := m.browserConfig.Profile
+	if profile == "" {
+		profile = DEFAULT_BROWSER_PROFILE
+	}
+	dir, err := resolveProjectPath(m.browserConfig.UserDataDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, profile), nil
+}
+
+// launchContext 启动浏览器并创建共享上下文：配置了 userDataDir 时使用持久化上下文（保留 localStorage、IndexedDB 等站点数据），
+// 否则启动普通浏览器并从 storageState 文件（存在时）恢复登录状态
+func (m *PlaywrightManager) launchContext(browserType playwright.BrowserType) (playwright.BrowserContext, error) {
+	statePath, err := m.storageStatePath()
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.isPersistent() {
+		// 其他账号复用默认账号的浏览器进程，只创建独立的上下文
+		if m.browser == nil {
+			browser, err := browserType.Launch(m.launchOptions())
+			if err != nil {
+				return nil, err
+			}
+			m.browser = browser
+		}
+		browser := m.browser
+
+		options := m.contextOptions()
+		if statePath != "" && fileExists(statePath) {
+			options.StorageStatePath = playwright.String(statePath)
+			log.Infof("从存储状态文件恢复登录状态: %s", statePath)
+		}
+		return browser.NewContext(options)
+	}
+
+	dir, err := m.profileDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建浏览器配置目录失败: %w", err)
+	}
+
+	context, err := browserType.LaunchPersistentContext(dir, m.persistentOptions())
+	if err != nil {
+		return nil, err
+	}
+	m.browser = context.Browser()
+	log.Infof("已使用持久化浏览器配置目录: %s", dir)
+
+	if statePath != "" && fileExists(statePath) {
+		m.context = context
+		if err := m.ImportStorageState(statePath); err != nil {
+			log.Warnf("导入存储状态失败: %v", err)
+		}
+	}
+	return context, nil
+}
+
+// persistentOptions 合并启动参数与上下文参数，构建持久化上下文参数
+func (m *PlaywrightManager) persistentOptions() playwright.BrowserTypeLaunchPersistentContextOptions {
+	launch := m.launchOptions()
+	context := m.contextOptions()
+	return playwright.BrowserTypeLaunchPersistentContextOptions{
+		Headless:       launch.Headless,
+		Args:           launch.Args,
+		SlowMo:         launch.SlowMo,
+		Channel:        launch.Channel,
+		ExecutablePath: launch.ExecutablePath,
+		Viewport:       context.Viewport,
+		UserAgent:      context.UserAgent,
+		Locale:         context.Locale,
+		TimezoneId:     context.TimezoneId,
+	}
+}
+
+// storageStatePath 配置的存储状态文件路径（未配置返回空）
+func (m *PlaywrightManager) storageStatePath() (string, error) {
+	if m.browserConfig.StorageState == "" {
+		return "", nil
+	}
+	return resolveProjectPath(m.browserConfig.StorageState)
+}
+
+// ImportStorageState 从 Playwright 存储状态文件导入 Cookie 与 localStorage 到当前上下文
+func (m *PlaywrightManager) ImportStorageState(path string) error {
+	if m.context == nil {
+		return fmt.Errorf("浏览器上下文未初始化")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取存储状态文件失败: %w", err)
+	}
+	var state playwright.OptionalStorageState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("解析存储状态文件失败: %w", err)
+	}
+
+	if len(state.Cookies) > 0 {
+		if err := m.context.AddCookies(state.Cookies); err != nil {
+			return fmt.Errorf("导入Cookie失败: %w", err)
+		}
+	}
+
+	origins := make(map[string][][2]string)
+	for _, origin := range state.Origins {
+		for _, item := range origin.LocalStorage {
+			origins[origin.Origin] = append(origins[origin.Origin], [2]string{item.Name, item.Value})
+		}
+	}
+	if len(origins) > 0 {
+		originsJson, err := json.Marshal(origins)
+		if err != nil {
+			return err
+		}
+		script := fmt.Sprintf(localStorageInitScript, originsJson)
+		if err := m.context.AddInitScript(playwright.Script{Content: playwright.String(script)}); err != nil {
+			return fmt.Errorf("导入localStorage失败: %w", err)
+		}
+	}
+
+	log.Infof("已导入存储状态: %s（Cookie %d 条，站点 %d 个）", path, len(state.Cookies), len(origins))
+	return nil
+}
+
+// ExportStorageState 导出当前上下文的 Cookie 与 localStorage 到 Playwright 存储状态文件
+func (m *PlaywrightManager) ExportStorageState(path string) error {
+	if m.context == nil {
+		return fmt.Errorf("浏览器上下文未初始化")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建存储状态目录失败: %w", err)
+	}
+	if _, err := m.context.StorageState(path); err != nil {
+		return fmt.Errorf("导出存储状态失败: %w", err)
+	}
+	log.Infof("已导出存储状态: %s", path)
+	return nil
+}
+
+// exportConfiguredStorageState 配置了 storageState 时导出存储状态（登录成功与关闭时调用）
+func (m *PlaywrightManager) exportConfiguredStorageState() {
+	path, err := m.storageStatePath()
+	if err != nil || path == "" {
+		return
+	}
+	if err := m.ExportStorageState(path); err != nil {
+		log.Warnf("%v", err)
+	}
+}
+
+// hasDomainCookies 上下文中是否已有指定域名的 Cookie（来自持久化配置目录或存储状态文件）
+func (m *PlaywrightManager) hasDomainCookies(domain string) bool {
+	if domain == "" {
+		return false
+	}
+	cookies, err := m.context.Cookies()
+	if err != nil {
+		return false
+	}
+	for _, cookie := range cookies {
+		if strings.HasSuffix(strings.TrimPrefix(cookie.Domain, "."), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveProjectPath 相对路径按项目根目录解析
+func resolveProjectPath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	root, err := utils.GetProjectRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, path), nil
+}
+
+// fileExists 判断文件是否存在
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
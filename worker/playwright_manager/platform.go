@@ -0,0 +1,160 @@
+package playwright_manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// PlatformRegistration 平台注册信息：每个平台在共享 BrowserContext 中拥有独立的 Page
+type PlatformRegistration struct {
+	Name          string                                   // 平台名称（boss/zhilian/job51/liepin），同时作为 Cookie 的 platform 键
+	CookieDomain  string                                   // Cookie 所属域名，保存 Cookie 时只保留该域名下的条目
+	Setup         func(page playwright.Page) error         // 平台初始化流程（注入 Cookie、导航首页等）
+	LoginDetector func(page playwright.Page) (bool, error) // 登录状态检测
+	LoginGuide    func(page playwright.Page)               // 未登录时的登录引导（可选）
+}
+
+// bossPlatform Boss直聘平台注册信息
+func (m *PlaywrightManager) bossPlatform() *PlatformRegistration {
+	return &PlatformRegistration{
+		Name:          "boss",
+		CookieDomain:  "zhipin.com",
+		Setup:         m.setupBossPlatform,
+		LoginDetector: m.checkIfBossLoggedIn,
+		LoginGuide:    m.guideBossLogin,
+	}
+}
+
+func (m *PlaywrightManager) setupBossPlatform(page playwright.Page) error {
+	log.Info("开始初始化Boss直聘平台...")
+
+	// ========= 1. 尝试从数据库加载 Cookie =========
+	m.loadCookiesFromDatabase("boss")
+
+	// ========= 2. 导航到 Boss 首页（带重试机制）=========
+	if !m.navigateWithRetry(page, BOSS_URL, 3) {
+		log.Warn("Boss直聘页面导航失败")
+		// 导航失败属于初始化失败 → 返回错误
+		return fmt.Errorf("boss platform navigate failed")
+	}
+
+	// ========= 3. 尝试等待网络空闲状态（非关键步骤）=========
+	err := page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
+		State: playwright.LoadStateNetworkidle,
+	})
+	if err != nil {
+		log.Debugf("等待Boss页面网络空闲失败: %v", err)
+	}
+
+	log.Info("Boss直聘平台初始化完成")
+	return nil
+}
+
+// 检测登录状态（完整防阻塞版）
+func (m *PlaywrightManager) checkIfBossLoggedIn(page playwright.Page) (bool, error) {
+	if page == nil {
+		return false, fmt.Errorf("bossPage is nil")
+	}
+
+	// 最长只允许整个函数执行 5 秒
+	totalTimeout := time.After(5 * time.Second)
+	done := make(chan struct{})
+	var result bool
+	var err error
+
+	go func() {
+		// 统一设置每一步 Playwright 调用的硬超时
+		stepTimeout := 1500 * time.Millisecond
+
+		// --- Step 1：用户昵称 ---
+		userName := page.Locator("li.nav-figure span.label-text").First()
+		visible, _ := runWithTimeout(stepTimeout, func() (bool, error) {
+			return userName.IsVisible()
+		})
+		if visible {
+			result = true
+			close(done)
+			return
+		}
+
+		// --- Step 2：头像 ---
+		avatar := page.Locator("li.nav-figure").First()
+		visible, _ = runWithTimeout(stepTimeout, func() (bool, error) {
+			return avatar.IsVisible()
+		})
+		if visible {
+			result = true
+			close(done)
+			return
+		}
+
+		// --- Step 3：登录入口文本 ---
+		loginAnchor := page.Locator("li.nav-sign a, .btns").First()
+
+		text, _ := runWithTimeout(stepTimeout, func() (string, error) {
+			return loginAnchor.TextContent()
+		})
+		if strings.Contains(text, "登录") {
+			result = false
+			close(done)
+			return
+		}
+
+		// --- 最终无法判断 ---
+		result = false
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return result, err
+	case <-totalTimeout:
+		return false, fmt.Errorf("checkIfBossLoggedIn total timeout (5s)")
+	}
+}
+
+// guideBossLogin Boss 平台未登录时自动跳转登录页并切换二维码登录
+func (m *PlaywrightManager) guideBossLogin(page playwright.Page) {
+	// ---- 1. 当前 URL ----
+	currentUrl := page.URL()
+
+	// ---- 2. 若不在登录页，则跳转一次 ----
+	if currentUrl == "" || !strings.Contains(currentUrl, "/web/user/") {
+		_, _ = page.Goto(
+			BOSS_URL+"/web/user/?ka=header-login",
+			playwright.PageGotoOptions{Timeout: playwright.Float(60000)},
+		)
+		time.Sleep(800 * time.Millisecond)
+	}
+
+	// ---- 3. 尝试切换二维码登录 ----
+
+	// 新版选择器
+	qr := page.Locator(".btn-sign-switch.ewm-switch")
+	if visible, _ := qr.IsVisible(); visible {
+		_ = qr.Click()
+		return
+	}
+
+	// 文本匹配 “APP扫码登录”
+	tip := page.GetByText("APP扫码登录")
+	if visible, _ := tip.IsVisible(); visible {
+		_ = tip.Click()
+		log.Info("已点击包含文本的二维码登录切换提示（APP扫码登录）")
+		return
+	}
+
+	// 旧版选择器（li.sign-switch-tip）
+	legacy := page.Locator("li.sign-switch-tip")
+	if visible, _ := legacy.IsVisible(); visible {
+		_ = legacy.Click()
+		log.Info("已通过旧版选择器切换二维码登录（li.sign-switch-tip）")
+		return
+	}
+
+	log.Info("未找到二维码登录切换按钮，保持当前登录页")
+}
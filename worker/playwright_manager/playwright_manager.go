@@ -3,6 +3,7 @@ package playwright_manager
 import (
 	"encoding/json"
 	"fmt"
+	"get_jobs_go/config"
 	"get_jobs_go/service"
 	"strings"
 	"sync"
@@ -69,34 +70,81 @@ func (l *LoginStatusListenerList) Emit(change LoginStatusChange) {
 
 // PlaywrightManager Playwright 管理器
 type PlaywrightManager struct {
-	playwright           *playwright.Playwright    // Playwright 实例
-	browser              playwright.Browser        // 浏览器实例（所有平台共享）
-	context              playwright.BrowserContext // 浏览器上下文（所有平台共享，在同一个窗口中打开多个标签页）
-	bossPage             playwright.Page           // Boss直聘页面实例
-	loginStatus          sync.Map                  // 登录状态追踪（平台 -> 是否已登录）
+	playwright           *playwright.Playwright     // Playwright 实例
+	browser              playwright.Browser         // 浏览器实例（所有平台共享）
+	context              playwright.BrowserContext  // 浏览器上下文（所有平台共享，在同一个窗口中打开多个标签页）
+	platforms            []*PlatformRegistration    // 已注册的平台（按注册顺序初始化）
+	pages                map[string]playwright.Page // 平台页面实例（平台 -> Page）
+	pagesMu              sync.RWMutex
+	loginStatus          sync.Map // 登录状态追踪（平台 -> 是否已登录）
 	listenerIDCounter    int32
-	loginStatusListeners *LoginStatusListenerList // 登录状态监听器
-	bossMonitoringPaused atomic.Bool              // 控制是否暂停对bossPage的后台监控，避免与任务执行并发访问同一页面
-	cookieService        service.CookieService    // Cookie服务
+	loginStatusListeners *LoginStatusListenerList     // 登录状态监听器
+	monitoringPaused     sync.Map                     // 后台监控暂停标记（平台 -> *atomic.Bool），避免与任务执行并发访问同一页面
+	cookieService        service.CookieService        // Cookie服务
+	browserConfig        config.BrowserConfig         // 浏览器启动配置
+	dbCookiesSkipped     sync.Map                     // 因浏览器上下文已有Cookie而跳过数据库Cookie注入的平台
+	accountId            int64                        // 所属账号（0=默认账号）
+	shared               bool                         // 是否与默认账号共享 Playwright 引擎与浏览器进程（关闭时只释放自己的上下文）
+	accounts             map[int64]*PlaywrightManager // 其他账号的管理器（账号ID -> 管理器），由默认账号的管理器统一关闭
+	accountsMu           sync.Mutex
 }
 
-// NewPlaywrightManager 创建新的Playwright管理器
-func NewPlaywrightManager(cookieService service.CookieService) *PlaywrightManager {
-	return &PlaywrightManager{
+// NewPlaywrightManager 创建新的Playwright管理器（默认注册Boss直聘平台）
+func NewPlaywrightManager(cookieService service.CookieService, browserConfig config.BrowserConfig) *PlaywrightManager {
+	m := &PlaywrightManager{
 		cookieService:        cookieService,
+		browserConfig:        browserConfig,
 		loginStatusListeners: NewLoginStatusListenerList(),
+		pages:                make(map[string]playwright.Page),
 	}
+	m.RegisterPlatform(m.bossPlatform())
+	return m
+}
+
+// RegisterPlatform 注册平台（需在 Init 之前调用，重复注册同名平台会覆盖之前的注册信息）
+func (m *PlaywrightManager) RegisterPlatform(reg *PlatformRegistration) {
+	if reg == nil || reg.Name == "" {
+		return
+	}
+	for i, existing := range m.platforms {
+		if existing.Name == reg.Name {
+			m.platforms[i] = reg
+			return
+		}
+	}
+	m.platforms = append(m.platforms, reg)
+}
+
+// GetPlatforms 获取已注册的平台名称列表
+func (m *PlaywrightManager) GetPlatforms() []string {
+	names := make([]string, 0, len(m.platforms))
+	for _, reg := range m.platforms {
+		names = append(names, reg.Name)
+	}
+	return names
+}
+
+// getRegistration 根据平台名称获取注册信息
+func (m *PlaywrightManager) getRegistration(platform string) *PlatformRegistration {
+	for _, reg := range m.platforms {
+		if reg.Name == platform {
+			return reg
+		}
+	}
+	return nil
 }
 
 func (m *PlaywrightManager) IsInitialized() bool {
+	m.pagesMu.RLock()
+	defer m.pagesMu.RUnlock()
 	return m.playwright != nil &&
-		m.browser != nil &&
-		m.bossPage != nil
+		m.context != nil &&
+		len(m.pages) > 0
 }
 
 func (m *PlaywrightManager) Init() error {
 	// 设置日志级别为 Debug
-    log.SetLevel(log.DebugLevel)
+	log.SetLevel(log.DebugLevel)
 	if m.IsInitialized() {
 		return nil
 	}
@@ -106,48 +154,39 @@ func (m *PlaywrightManager) Init() error {
 	log.Info("========================================")
 
 	// -------------------------------
-	// 1. 启动 Playwright
+	// 1. 启动 Playwright（其他账号复用默认账号的引擎）
 	// -------------------------------
-	pw, err := playwright.Run()
-	if err != nil {
-		log.Errorf("✗ Playwright 启动失败: %v", err)
-		return err
+	if m.playwright == nil {
+		pw, err := playwright.Run()
+		if err != nil {
+			log.Errorf("✗ Playwright 启动失败: %v", err)
+			return err
+		}
+		m.playwright = pw
+		log.Info("✓ Playwright 引擎已启动")
 	}
-	m.playwright = pw
-	log.Info("✓ Playwright 引擎已启动")
+	pw := m.playwright
 
 	// -------------------------------
 	// 2. 启动浏览器实例
 	// -------------------------------
-	browser, err := pw.Chromium.Launch(playwright.BrowserTypeLaunchOptions{
-		Headless: playwright.Bool(false),
-		SlowMo:   playwright.Float(50),
-		Args: []string{
-			"--remote-debugging-port=7866",
-			"--start-maximized",
-		},
-	})
+	cfg := m.browserConfig
+	browserType, err := m.browserType(pw)
 	if err != nil {
 		log.Errorf("✗ 浏览器启动失败: %v", err)
 		return err
 	}
-	m.browser = browser
-	log.Info("✓ Chrome 浏览器已启动 (调试端口: 7866)")
 
 	// -------------------------------
-	// 3. 创建共享 BrowserContext
+	// 3. 创建共享 BrowserContext（配置了 userDataDir 时为持久化上下文）
 	// -------------------------------
-	context, err := browser.NewContext(playwright.BrowserNewContextOptions{
-		Viewport: nil, // 不设置固定视口
-		UserAgent: playwright.String(
-			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/135.0.0.0 Safari/537.36",
-		),
-	})
+	context, err := m.launchContext(browserType)
 	if err != nil {
 		log.Errorf("✗ BrowserContext 创建失败: %v", err)
 		return err
 	}
 	m.context = context
+	log.Infof("✓ %s 浏览器已启动 (无头模式: %v, 调试端口: %d, 持久化配置: %v)", browserType.Name(), cfg.Headless, cfg.DebugPort, m.isPersistent())
 	log.Info("✓ BrowserContext 已创建（所有平台共享）")
 
 	// -------------------------------
@@ -155,14 +194,19 @@ func (m *PlaywrightManager) Init() error {
 	// -------------------------------
 	log.Info("开始创建所有平台的 Page...")
 
-	bossPage, err := context.NewPage()
-	if err != nil {
-		log.Errorf("✗ Boss Page 创建失败: %v", err)
-		return err
+	for _, reg := range m.platforms {
+		page, err := context.NewPage()
+		if err != nil {
+			log.Errorf("✗ %s Page 创建失败: %v", reg.Name, err)
+			return err
+		}
+		page.SetDefaultTimeout(float64(DEFAULR_TIMEOUT.Milliseconds()))
+
+		m.pagesMu.Lock()
+		m.pages[reg.Name] = page
+		m.pagesMu.Unlock()
+		log.Infof("✓ %s Page 已创建", reg.Name)
 	}
-	bossPage.SetDefaultTimeout(float64(DEFAULR_TIMEOUT.Milliseconds()))
-	m.bossPage = bossPage
-	log.Info("✓ Boss Page 已创建")
 
 	// -------------------------------
 	// 5. 并发初始化平台
@@ -170,21 +214,15 @@ func (m *PlaywrightManager) Init() error {
 	log.Info("开始并发初始化所有平台...")
 
 	var wg sync.WaitGroup
-
-	// Boss
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := m.setupBossPlatform(); err != nil {
-			log.Errorf("Boss 初始化失败: %v", err)
-		}
-	}()
-
-	// 预留其他平台（与 Java 对齐）
-	// go func() { ... setupLiepinPlatform() }
-	// go func() { ... setup51Platform() }
-	// go func() { ... setupZhilianPlatform() }
-
+	for _, reg := range m.platforms {
+		wg.Add(1)
+		go func(reg *PlatformRegistration) {
+			defer wg.Done()
+			if err := m.setupPlatform(reg); err != nil {
+				log.Errorf("%s 初始化失败: %v", reg.Name, err)
+			}
+		}(reg)
+	}
 	wg.Wait()
 
 	//定时检测登录状态
@@ -196,91 +234,179 @@ func (m *PlaywrightManager) Init() error {
 	return nil
 }
 
-func (m *PlaywrightManager) setupBossPlatform() error {
-	log.Info("开始初始化Boss直聘平台...")
+// browserType 根据配置选择浏览器类型（为空时使用 chromium）
+func (m *PlaywrightManager) browserType(pw *playwright.Playwright) (playwright.BrowserType, error) {
+	switch strings.ToLower(m.browserConfig.Type) {
+	case "", config.BROWSER_TYPE_CHROMIUM:
+		return pw.Chromium, nil
+	case config.BROWSER_TYPE_FIREFOX:
+		return pw.Firefox, nil
+	case config.BROWSER_TYPE_WEBKIT:
+		return pw.WebKit, nil
+	}
+	return nil, fmt.Errorf("不支持的浏览器类型: %s（可选 chromium / firefox / webkit）", m.browserConfig.Type)
+}
 
-	// ========= 1. 尝试从数据库加载 Cookie =========
-	cookieEntity, err := m.cookieService.GetCookieByPlatform("boss")
-	if err != nil {
-		log.Warnf("从数据库加载Boss Cookie失败: %v", err)
-	} else if cookieEntity != nil && cookieEntity.CookieValue != "" {
-		cookies, err := m.parseCookiesFromString(cookieEntity.CookieValue)
-		if err != nil {
-			log.Warnf("解析Boss Cookie失败: %v", err)
-		} else if len(cookies) > 0 {
-			err = m.context.AddCookies(cookies)
-			if err != nil {
-				log.Warnf("注入Boss Cookie失败: %v", err)
-			} else {
-				log.Infof("已从数据库加载Boss Cookie并注入浏览器上下文，共 %d 条", len(cookies))
-			}
+// launchOptions 根据配置构建浏览器启动参数（远程调试端口仅对 chromium 生效）
+func (m *PlaywrightManager) launchOptions() playwright.BrowserTypeLaunchOptions {
+	cfg := m.browserConfig
+	args := append([]string{}, cfg.Args...)
+	if cfg.DebugPort > 0 {
+		if cfg.Type == "" || strings.EqualFold(cfg.Type, config.BROWSER_TYPE_CHROMIUM) {
+			args = append(args, fmt.Sprintf("--remote-debugging-port=%d", cfg.DebugPort))
 		} else {
-			log.Warn("解析Cookie失败，未能加载任何Cookie")
+			log.Warnf("远程调试端口仅支持 chromium，已忽略 (浏览器类型: %s)", cfg.Type)
 		}
-	} else {
-		log.Info("数据库未找到Boss Cookie或值为空，跳过Cookie注入")
 	}
 
-	// ========= 2. 导航到 Boss 首页（带重试机制）=========
-	maxRetries := 3
-	navigateSuccess := false
+	options := playwright.BrowserTypeLaunchOptions{
+		Headless: playwright.Bool(cfg.Headless),
+		Args:     args,
+	}
+	if cfg.SlowMo > 0 {
+		options.SlowMo = playwright.Float(cfg.SlowMo)
+	}
+	if cfg.Channel != "" {
+		options.Channel = playwright.String(cfg.Channel)
+	}
+	if cfg.ExecutablePath != "" {
+		options.ExecutablePath = playwright.String(cfg.ExecutablePath)
+	}
+	return options
+}
 
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		_, err := m.bossPage.Goto(BOSS_URL, playwright.PageGotoOptions{
-			Timeout:   playwright.Float(60000),
-			WaitUntil: playwright.WaitUntilStateDomcontentloaded,
-		})
+// contextOptions 根据配置构建 BrowserContext 参数
+func (m *PlaywrightManager) contextOptions() playwright.BrowserNewContextOptions {
+	cfg := m.browserConfig
+	options := playwright.BrowserNewContextOptions{}
+	if cfg.Viewport.Width > 0 && cfg.Viewport.Height > 0 {
+		options.Viewport = &playwright.Size{Width: cfg.Viewport.Width, Height: cfg.Viewport.Height}
+	}
+	if cfg.UserAgent != "" {
+		options.UserAgent = playwright.String(cfg.UserAgent)
+	}
+	if cfg.Locale != "" {
+		options.Locale = playwright.String(cfg.Locale)
+	}
+	if cfg.TimezoneId != "" {
+		options.TimezoneId = playwright.String(cfg.TimezoneId)
+	}
+	return options
+}
 
-		if err == nil {
-			navigateSuccess = true
-			break
-		}
+// setupPlatform 执行平台初始化流程：平台自身的 Setup → 初始化登录状态 → 设置登录监控
+func (m *PlaywrightManager) setupPlatform(reg *PlatformRegistration) error {
+	page := m.GetPage(reg.Name)
+	if page == nil {
+		return fmt.Errorf("%s page is nil", reg.Name)
+	}
 
-		// Playwright 报错，但页面可能已成功加载 —— 检查 URL
-		pageAccessible := false
-		url := m.bossPage.URL() // 只返回 string
-		if strings.Contains(url, BOSS_URL) {
-			pageAccessible = true
+	// ========= 1. 平台自身的初始化流程 =========
+	if reg.Setup != nil {
+		if err := reg.Setup(page); err != nil {
+			return err
 		}
+	}
 
-		if pageAccessible {
-			navigateSuccess = true
-			break
-		}
+	// ========= 2. 初始化登录状态并通知监听器 =========
+	isLoggedIn := false
+	if reg.LoginDetector != nil {
+		isLoggedIn, _ = reg.LoginDetector(page)
+	}
 
-		// 失败则重试
-		if attempt < maxRetries {
-			time.Sleep(2 * time.Second)
+	// 浏览器保存的登录状态已失效时，改用数据库中的 Cookie 兜底
+	if skipped, _ := m.dbCookiesSkipped.LoadAndDelete(reg.Name); skipped != nil && !isLoggedIn && reg.LoginDetector != nil {
+		log.Infof("浏览器保存的%s登录状态无效，改用数据库Cookie", reg.Name)
+		if m.injectCookiesFromDatabase(reg.Name) {
+			if _, err := page.Reload(); err != nil {
+				log.Debugf("重新加载%s页面失败: %v", reg.Name, err)
+			}
+			isLoggedIn, _ = reg.LoginDetector(page)
 		}
 	}
+	m.SetLoginStatus(reg.Name, isLoggedIn)
+
+	// ========= 3. 设置登录监控 =========
+	m.setupLoginMonitoring(reg.Name, page)
+
+	return nil
+}
+
+// GetPage 获取指定平台的页面实例
+func (m *PlaywrightManager) GetPage(platform string) playwright.Page {
+	m.pagesMu.RLock()
+	defer m.pagesMu.RUnlock()
+	return m.pages[platform]
+}
+
+// GetBossPage 获取Boss直聘页面实例
+func (m *PlaywrightManager) GetBossPage() playwright.Page {
+	return m.GetPage("boss")
+}
 
-	if !navigateSuccess {
-		log.Warn("Boss直聘页面导航失败")
-		// 导航失败属于初始化失败 → 返回错误
-		return fmt.Errorf("boss platform navigate failed")
+// loadCookiesFromDatabase 从数据库加载指定平台的 Cookie 并注入共享上下文
+// 持久化配置目录或存储状态文件中已有该平台的 Cookie 时先不注入，登录检测失败后再用数据库 Cookie 兜底
+func (m *PlaywrightManager) loadCookiesFromDatabase(platform string) {
+	if reg := m.getRegistration(platform); reg != nil && m.hasDomainCookies(reg.CookieDomain) {
+		log.Infof("浏览器上下文中已有%s Cookie，暂不注入数据库Cookie", platform)
+		m.dbCookiesSkipped.Store(platform, true)
+		return
 	}
+	m.injectCookiesFromDatabase(platform)
+}
 
-	// ========= 3. 尝试等待网络空闲状态（非关键步骤）=========
-	err = m.bossPage.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
-		State: playwright.LoadStateNetworkidle,
-	})
+// injectCookiesFromDatabase 从数据库读取指定平台的 Cookie 并注入共享上下文，返回是否注入成功
+func (m *PlaywrightManager) injectCookiesFromDatabase(platform string) bool {
+	cookieEntity, err := m.cookieService.GetCookieByPlatform(platform)
 	if err != nil {
-		log.Debugf("等待Boss页面网络空闲失败: %v", err)
+		log.Warnf("从数据库加载%s Cookie失败: %v", platform, err)
+		return false
+	}
+	if cookieEntity == nil || cookieEntity.CookieValue == "" {
+		log.Infof("数据库未找到%s Cookie或值为空，跳过Cookie注入", platform)
+		return false
 	}
 
-	// ========= 4. 初始化登录状态并通知监听器 =========
-	isLoggedIn,_ := m.checkIfBossLoggedIn()
-	m.SetLoginStatus("boss", isLoggedIn)
-
-	// ========= 5. 设置登录监控（类似 Java setupLoginMonitoring）=========
-	m.setupLoginMonitoring(m.bossPage)
+	cookies, err := m.parseCookiesFromString(cookieEntity.CookieValue)
+	if err != nil {
+		log.Warnf("解析%s Cookie失败: %v", platform, err)
+		return false
+	}
+	if len(cookies) == 0 {
+		log.Warn("解析Cookie失败，未能加载任何Cookie")
+		return false
+	}
 
-	log.Info("Boss直聘平台初始化完成")
-	return nil
+	if err := m.context.AddCookies(cookies); err != nil {
+		log.Warnf("注入%s Cookie失败: %v", platform, err)
+		return false
+	}
+	log.Infof("已从数据库加载%s Cookie并注入浏览器上下文，共 %d 条", platform, len(cookies))
+	return true
 }
 
-func (m *PlaywrightManager) GetBossPage() playwright.Page {
-	return m.bossPage
+// navigateWithRetry 导航到指定地址（带重试机制）
+func (m *PlaywrightManager) navigateWithRetry(page playwright.Page, targetUrl string, maxRetries int) bool {
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		_, err := page.Goto(targetUrl, playwright.PageGotoOptions{
+			Timeout:   playwright.Float(60000),
+			WaitUntil: playwright.WaitUntilStateDomcontentloaded,
+		})
+		if err == nil {
+			return true
+		}
+
+		// Playwright 报错，但页面可能已成功加载 —— 检查 URL
+		if strings.Contains(page.URL(), targetUrl) {
+			return true
+		}
+
+		// 失败则重试
+		if attempt < maxRetries {
+			time.Sleep(2 * time.Second)
+		}
+	}
+	return false
 }
 
 // parseCookiesFromString 从JSON字符串解析Cookie列表
@@ -319,71 +445,6 @@ func runWithTimeout[T any](d time.Duration, fn func() (T, error)) (T, error) {
 	}
 }
 
-// 检测登录状态（完整防阻塞版）
-func (pm *PlaywrightManager) checkIfBossLoggedIn() (bool, error) {
-	page := pm.bossPage
-	if page == nil {
-		return false, fmt.Errorf("bossPage is nil")
-	}
-
-	// 最长只允许整个函数执行 5 秒
-	totalTimeout := time.After(5 * time.Second)
-	done := make(chan struct{})
-	var result bool
-	var err error
-
-	go func() {
-		// 统一设置每一步 Playwright 调用的硬超时
-		stepTimeout := 1500 * time.Millisecond
-
-		// --- Step 1：用户昵称 ---
-		userName := page.Locator("li.nav-figure span.label-text").First()
-		visible, _ := runWithTimeout(stepTimeout, func() (bool, error) {
-			return userName.IsVisible()
-		})
-		if visible {
-			result = true
-			close(done)
-			return
-		}
-
-		// --- Step 2：头像 ---
-		avatar := page.Locator("li.nav-figure").First()
-		visible, _ = runWithTimeout(stepTimeout, func() (bool, error) {
-			return avatar.IsVisible()
-		})
-		if visible {
-			result = true
-			close(done)
-			return
-		}
-
-		// --- Step 3：登录入口文本 ---
-		loginAnchor := page.Locator("li.nav-sign a, .btns").First()
-
-		text, _ := runWithTimeout(stepTimeout, func() (string, error) {
-			return loginAnchor.TextContent()
-		})
-		if strings.Contains(text, "登录") {
-			result = false
-			close(done)
-			return
-		}
-
-		// --- 最终无法判断 ---
-		result = false
-		close(done)
-	}()
-
-	select {
-	case <-done:
-		return result, err
-	case <-totalTimeout:
-		return false, fmt.Errorf("checkIfBossLoggedIn total timeout (5s)")
-	}
-}
-
-
 func (m *PlaywrightManager) SetLoginStatus(platform string, isLoggedIn bool) {
 	// ========== 1. 获取之前状态 ==========
 	var previousStatus *bool
@@ -400,55 +461,17 @@ func (m *PlaywrightManager) SetLoginStatus(platform string, isLoggedIn bool) {
 	// ========== 2. 更新状态 ==========
 	m.loginStatus.Store(platform, isLoggedIn)
 
-	// ========== 3. Boss 平台：未登录 → 自动跳转并切二维码 ==========
-	if platform == "boss" && !isLoggedIn {
+	// ========== 3. 未登录 → 执行平台注册的登录引导 ==========
+	if reg := m.getRegistration(platform); reg != nil && reg.LoginGuide != nil && !isLoggedIn {
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
-					log.Errorf("设置Boss未登录状态时执行登录引导失败: %v", r)
+					log.Errorf("设置%s未登录状态时执行登录引导失败: %v", platform, r)
 				}
 			}()
 
-			if m.bossPage != nil {
-
-				// ---- 3.1 当前 URL ----
-				currentUrl := m.bossPage.URL()
-
-				// ---- 3.2 若不在登录页，则跳转一次 ----
-				if currentUrl == "" || !strings.Contains(currentUrl, "/web/user/") {
-					_, _ = m.bossPage.Goto(
-						BOSS_URL+"/web/user/?ka=header-login",
-						playwright.PageGotoOptions{Timeout: playwright.Float(60000)},
-					)
-					time.Sleep(800 * time.Millisecond)
-				}
-
-				// ---- 3.3 尝试切换二维码登录 ----
-
-				// 新版选择器
-				qr := m.bossPage.Locator(".btn-sign-switch.ewm-switch")
-				if visible, _ := qr.IsVisible(); visible {
-					_ = qr.Click()
-					return
-				}
-
-				// 文本匹配 “APP扫码登录”
-				tip := m.bossPage.GetByText("APP扫码登录")
-				if visible, _ := tip.IsVisible(); visible {
-					_ = tip.Click()
-					log.Info("已点击包含文本的二维码登录切换提示（APP扫码登录）")
-					return
-				}
-
-				// 旧版选择器（li.sign-switch-tip）
-				legacy := m.bossPage.Locator("li.sign-switch-tip")
-				if visible, _ := legacy.IsVisible(); visible {
-					_ = legacy.Click()
-					log.Info("已通过旧版选择器切换二维码登录（li.sign-switch-tip）")
-					return
-				}
-
-				log.Info("未找到二维码登录切换按钮，保持当前登录页")
+			if page := m.GetPage(platform); page != nil {
+				reg.LoginGuide(page)
 			}
 		}()
 	}
@@ -464,10 +487,10 @@ func (m *PlaywrightManager) SetLoginStatus(platform string, isLoggedIn bool) {
 	m.loginStatusListeners.Emit(change)
 }
 
-// setupLoginMonitoring 设置 Boss 登录状态监控（与 Java 版本完全对齐）
-func (m *PlaywrightManager) setupLoginMonitoring(page playwright.Page) {
+// setupLoginMonitoring 设置平台登录状态监控（与 Java 版本完全对齐）
+func (m *PlaywrightManager) setupLoginMonitoring(platform string, page playwright.Page) {
 	if page == nil {
-		log.Warn("setupLoginMonitoring: page 为 nil，无法绑定监听器")
+		log.Warnf("setupLoginMonitoring: %s page 为 nil，无法绑定监听器", platform)
 		return
 	}
 
@@ -479,29 +502,31 @@ func (m *PlaywrightManager) setupLoginMonitoring(page playwright.Page) {
 		}
 
 		// 若监控暂停（atomic），直接忽略
-		if m.bossMonitoringPaused.Load() {
+		if m.isMonitoringPaused(platform) {
 			return
 		}
 
 		// 执行登录状态检测
-		m.checkLoginStatus("boss")
+		m.checkLoginStatus(platform)
 	})
 
-	log.Info("Boss 平台登录状态监控已启用")
+	log.Infof("%s 平台登录状态监控已启用", platform)
 }
 
-func (m *PlaywrightManager) checkLoginStatus( platform string) {
+func (m *PlaywrightManager) checkLoginStatus(platform string) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Debugf("检查 %s 平台登录状态时发生异常: %v", platform, r)
 		}
 	}()
 
-	// ========== 1. 仅 boss 支持检查（保持 Java 一致） ==========
-	var isLoggedIn bool
-	if platform == "boss" {
-		isLoggedIn,_ = m.checkIfBossLoggedIn() // 已实现的稳定版本
+	// ========== 1. 使用平台注册的检测器 ==========
+	reg := m.getRegistration(platform)
+	page := m.GetPage(platform)
+	if reg == nil || reg.LoginDetector == nil || page == nil {
+		return
 	}
+	isLoggedIn, _ := reg.LoginDetector(page)
 
 	// ========== 2. 获取 previousStatus ==========
 	var previous *bool
@@ -522,37 +547,47 @@ func (m *PlaywrightManager) onLoginSuccess(platform string) {
 	// 1) 更新登录状态并触发所有监听器（保持与 Java 一致）
 	m.SetLoginStatus(platform, true)
 
-	// 2) BOSS 平台：登录成功后自动保存 Cookie 到数据库
-	if platform == "boss" {
-		m.saveBossCookiesToDatabase("login success")
-	}
+	// 2) 登录成功后自动保存该平台的 Cookie 到数据库，并导出存储状态（如已配置）
+	m.saveCookiesToDatabase(platform, "login success")
+	m.exportConfiguredStorageState()
 }
 
-// saveBossCookiesToDatabase 统一的 Boss Cookie 保存方法（使用 JSON 序列化）
-func (m *PlaywrightManager) saveBossCookiesToDatabase(remark string) {
+// saveCookiesToDatabase 统一的 Cookie 保存方法（使用 JSON 序列化，按平台 CookieDomain 过滤）
+func (m *PlaywrightManager) saveCookiesToDatabase(platform, remark string) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Warnf("保存Boss Cookie失败（panic恢复）: %v", r)
+			log.Warnf("保存%s Cookie失败（panic恢复）: %v", platform, r)
 		}
 	}()
 
 	cookies, err := m.context.Cookies()
 	if err != nil {
-		log.Warnf("保存Boss Cookie失败，无法获取Cookies: %v", err)
+		log.Warnf("保存%s Cookie失败，无法获取Cookies: %v", platform, err)
 		return
 	}
 
+	// 共享上下文中包含所有平台的 Cookie，只保留属于该平台域名的部分
+	if reg := m.getRegistration(platform); reg != nil && reg.CookieDomain != "" {
+		filtered := make([]playwright.Cookie, 0, len(cookies))
+		for _, cookie := range cookies {
+			if strings.HasSuffix(strings.TrimPrefix(cookie.Domain, "."), reg.CookieDomain) {
+				filtered = append(filtered, cookie)
+			}
+		}
+		cookies = filtered
+	}
+
 	// 序列化为 JSON
 	cookieBytes, err := json.Marshal(cookies)
 	if err != nil {
-		log.Warnf("保存Boss Cookie失败，序列化错误: %v", err)
+		log.Warnf("保存%s Cookie失败，序列化错误: %v", platform, err)
 		return
 	}
 
 	cookieJson := string(cookieBytes)
-	ok, _ := m.cookieService.SaveOrUpdateCookie("boss", cookieJson, remark)
+	ok, _ := m.cookieService.SaveOrUpdateCookie(platform, cookieJson, remark)
 	if ok {
-		log.Infof("保存Boss Cookie成功，共 %d 条，remark=%s", len(cookies), remark)
+		log.Infof("保存%s Cookie成功，共 %d 条，remark=%s", platform, len(cookies), remark)
 	}
 }
 
@@ -567,17 +602,43 @@ func (m *PlaywrightManager) Close() {
 		}
 	}()
 
-	// 1. 关闭 Boss 页面
-	if m.bossPage != nil {
-		if err := m.bossPage.Close(); err != nil {
-			log.Warnf("关闭Boss直聘页面时发生错误: %v", err)
+	// 0. 先关闭其他账号的浏览器上下文
+	m.closeAccounts()
+
+	// 1. 导出存储状态（如已配置），并关闭所有平台页面
+	if m.context != nil {
+		m.exportConfiguredStorageState()
+	}
+	m.pagesMu.Lock()
+	for platform, page := range m.pages {
+		if err := page.Close(); err != nil {
+			log.Warnf("关闭%s页面时发生错误: %v", platform, err)
 		} else {
-			log.Info("Boss直聘页面已关闭")
+			log.Infof("%s页面已关闭", platform)
 		}
-		m.bossPage = nil
+		delete(m.pages, platform)
 	}
+	m.pagesMu.Unlock()
 
-	// 2. 关闭浏览器
+	// 2. 关闭浏览器（持久化上下文没有独立的浏览器实例，需关闭上下文；与默认账号共享浏览器时只关闭自己的上下文）
+	if m.shared && !m.isPersistent() && m.context != nil {
+		if err := m.context.Close(); err != nil {
+			log.Warnf("关闭账号浏览器上下文时发生错误: %v", err)
+		}
+	}
+	if m.shared {
+		// 共享的浏览器属于默认账号，即使上下文未创建成功也不能关闭
+		m.browser = nil
+	}
+	if m.isPersistent() && m.context != nil {
+		if err := m.context.Close(); err != nil {
+			log.Warnf("关闭持久化浏览器上下文时发生错误: %v", err)
+		} else {
+			log.Info("持久化浏览器上下文已关闭")
+		}
+		m.browser = nil
+	}
+	m.context = nil
 	if m.browser != nil {
 		if err := m.browser.Close(); err != nil {
 			log.Warnf("关闭浏览器时发生错误: %v", err)
@@ -587,7 +648,10 @@ func (m *PlaywrightManager) Close() {
 		m.browser = nil
 	}
 
-	// 3. 关闭 Playwright 实例
+	// 3. 关闭 Playwright 实例（共享的引擎由默认账号的管理器关闭）
+	if m.shared {
+		m.playwright = nil
+	}
 	if m.playwright != nil {
 		if err := m.playwright.Stop(); err != nil {
 			log.Warnf("关闭Playwright实例时发生错误: %v", err)
@@ -610,39 +674,49 @@ func (m *PlaywrightManager) IsLoggedIn(platform string) bool {
 	return false
 }
 
-// PauseBossMonitoring 暂停 Boss 页面后台登录监控（避免与业务流程并发操作页面）
-func (m *PlaywrightManager) PauseBossMonitoring() {
-	m.bossMonitoringPaused.Store(true)
-	log.Debug("Boss登录监控已暂停")
+// monitoringFlag 获取平台的监控暂停标记
+func (m *PlaywrightManager) monitoringFlag(platform string) *atomic.Bool {
+	flag, _ := m.monitoringPaused.LoadOrStore(platform, &atomic.Bool{})
+	return flag.(*atomic.Bool)
 }
 
-// ResumeBossMonitoring 恢复 Boss 页面后台登录监控
-func (m *PlaywrightManager) ResumeBossMonitoring() {
-	m.bossMonitoringPaused.Store(false)
-	log.Debug("Boss登录监控已恢复")
+// isMonitoringPaused 检查平台后台监控是否已暂停
+func (m *PlaywrightManager) isMonitoringPaused(platform string) bool {
+	return m.monitoringFlag(platform).Load()
 }
 
-// startScheduledLoginCheck 启动后台定时检查登录状态（每 3 秒）
+// PauseMonitoring 暂停指定平台页面的后台登录监控（避免与业务流程并发操作页面）
+func (m *PlaywrightManager) PauseMonitoring(platform string) {
+	m.monitoringFlag(platform).Store(true)
+	log.Debugf("%s登录监控已暂停", platform)
+}
+
+// ResumeMonitoring 恢复指定平台页面的后台登录监控
+func (m *PlaywrightManager) ResumeMonitoring(platform string) {
+	m.monitoringFlag(platform).Store(false)
+	log.Debugf("%s登录监控已恢复", platform)
+}
+
+// startScheduledLoginCheck 启动后台定时检查登录状态（每 3 秒，遍历所有已注册平台）
 func (m *PlaywrightManager) startScheduledLoginCheck() {
 	go func() {
-		log.Info("启动定时登录检测（Boss，每3秒）")
+		log.Infof("启动定时登录检测（%s，每3秒）", strings.Join(m.GetPlatforms(), "/"))
 
 		ticker := time.NewTicker(3 * time.Second)
 		defer ticker.Stop()
 
 		for range ticker.C {
+			for _, reg := range m.platforms {
+				// 若监控暂停，跳过
+				if m.isMonitoringPaused(reg.Name) {
+					log.Debugf("%s 登录监控已暂停，本轮检测跳过", reg.Name)
+					continue
+				}
 
-			log.Debug("定时器 tick：准备执行 Boss 登录检测")
-
-			// 若监控暂停，跳过
-			if m.bossMonitoringPaused.Load() {
-				log.Debug("Boss 登录监控已暂停，本轮检测跳过")
-				continue
-			}
-
-			// Boss 登录状态检查
-			if m.bossPage != nil {
-				log.Debug("执行 Boss 登录检测...")
+				if m.GetPage(reg.Name) == nil {
+					log.Debugf("%s 页面为空，跳过检测", reg.Name)
+					continue
+				}
 
 				func() {
 					defer func() {
@@ -651,12 +725,8 @@ func (m *PlaywrightManager) startScheduledLoginCheck() {
 						}
 					}()
 
-					m.checkLoginStatus("boss")
+					m.checkLoginStatus(reg.Name)
 				}()
-
-				log.Debug("Boss 登录检测完成")
-			} else {
-				log.Debug("Boss 页面为空，跳过检测")
 			}
 		}
 	}()